@@ -0,0 +1,123 @@
+package http
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/airbenders/profile/utils/httputils"
+	"github.com/airbenders/profile/utils/mailer"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// MailHandler struct
+type MailHandler struct {
+	u domain.MailUseCase
+}
+
+// NewMailHandler is the constructor
+func NewMailHandler(u domain.MailUseCase) *MailHandler {
+	return &MailHandler{u: u}
+}
+
+// GetFailedDeliveries returns the emails given up as dead, newest first. Only for admins. Paginated with page and
+// limit
+func (h *MailHandler) GetFailedDeliveries(c *gin.Context) {
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if !isAdmin(loggedID) {
+		c.JSON(http.StatusForbidden, errors.NewForbiddenError("only admins can see failed deliveries"))
+		return
+	}
+	page, err := httputils.ParsePage(c, 50, 200)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	deliveries, err := h.u.GetFailedDeliveries(ctx, page)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// ReceiveMailEvents takes the bounces and complaints of the mail provider, and stops the emails to the addresses that
+// can't get them. The body is either a JSON list of events or a bounce or abuse report message, sent as
+// message/rfc822. The provider authenticates with the MAIL_WEBHOOK_TOKEN as bearer token
+func (h *MailHandler) ReceiveMailEvents(c *gin.Context) {
+	if !validWebhookToken(c.GetHeader("Authorization")) {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("invalid webhook token"))
+		return
+	}
+
+	var events []domain.MailEvent
+	switch c.ContentType() {
+	case "application/json":
+		if err := c.ShouldBindJSON(&events); err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+			return
+		}
+	case "message/rfc822":
+		var err error
+		events, err = mailer.ParseReport(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError("invalid report: "+err.Error()))
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("the content type must be application/json or message/rfc822"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	suppressed, err := h.u.HandleMailEvents(ctx, events)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, httputils.NewResponse(fmt.Sprintf("%d of %d events suppressed their address", suppressed,
+		len(events))))
+}
+
+// validWebhookToken is true if the authorization is the MAIL_WEBHOOK_TOKEN as bearer token. No token is valid when
+// it isn't set
+func validWebhookToken(authorization string) bool {
+	token := os.Getenv("MAIL_WEBHOOK_TOKEN")
+	if token == "" || !strings.HasPrefix(authorization, "Bearer ") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authorization, "Bearer ")), []byte(token)) == 1
+}
+
+// isAdmin is true if the id is one of the comma separated ADMIN_IDS
+func isAdmin(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, admin := range strings.Split(os.Getenv("ADMIN_IDS"), ",") {
+		if strings.TrimSpace(admin) == id {
+			return true
+		}
+	}
+	return false
+}
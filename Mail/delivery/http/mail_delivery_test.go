@@ -0,0 +1,153 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/airbenders/profile/Mail/delivery/http"
+	"github.com/airbenders/profile/app"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMailHandlerGetFailedDeliveries(t *testing.T) {
+	mockUseCase := new(mocks.MailUseCase)
+	h := http.NewMailHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(nil, nil, nil, nil, nil, h, mw, mw, parser)
+	t.Setenv("ADMIN_IDS", "root, admin")
+
+	t.Run("success", func(t *testing.T) {
+		deliveries := []domain.MailDelivery{{ID: "d", To: "ada@concordia.ca", Body: []byte("secret link"),
+			Status: domain.MailDead, Attempts: domain.MaxMailAttempts, LastError: "550 no such user"}}
+		mockUseCase.On("GetFailedDeliveries", mock.Anything, domain.Page{Number: 2, Size: 10}).
+			Return(deliveries, nil).Once()
+		req := httptest.NewRequest("GET", "/api/v1/mail/failed?page=2&limit=10", nil)
+		req.Header.Set("id", "admin")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code)
+		assert.NotContains(t, w.Body.String(), "secret link")
+		var received []domain.MailDelivery
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &received))
+		assert.Equal(t, "550 no such user", received[0].LastError)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("not-admin", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/mail/failed", nil)
+		req.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 403, w.Code)
+	})
+
+	t.Run("invalid-limit", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/mail/failed?limit=1000", nil)
+		req.Header.Set("id", "root")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockUseCase.On("GetFailedDeliveries", mock.Anything, domain.Page{Number: 1, Size: 50}).
+			Return(nil, errors.New("error")).Once()
+		req := httptest.NewRequest("GET", "/api/v1/mail/failed", nil)
+		req.Header.Set("id", "root")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 500, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestMailHandlerReceiveMailEvents(t *testing.T) {
+	mockUseCase := new(mocks.MailUseCase)
+	h := http.NewMailHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(nil, nil, nil, nil, nil, h, mw, mw, parser)
+	t.Setenv("MAIL_WEBHOOK_TOKEN", "webhook-token")
+	post := func(contentType string, body string, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/mail/events", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("json", func(t *testing.T) {
+		events := []domain.MailEvent{{Type: domain.MailBounce, Email: "ada@concordia.ca", Permanent: true,
+			Diagnostic: "550 user unknown"}}
+		mockUseCase.On("HandleMailEvents", mock.Anything, events).Return(1, nil).Once()
+
+		w := post("application/json",
+			`[{"type":"bounce","email":"ada@concordia.ca","permanent":true,"diagnostic":"550 user unknown"}]`,
+			"webhook-token")
+
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("report", func(t *testing.T) {
+		report := "Content-Type: multipart/report; report-type=delivery-status; boundary=b\r\n\r\n" +
+			"--b\r\nContent-Type: message/delivery-status\r\n\r\nReporting-MTA: dns; mx.studpal.ca\r\n\r\n" +
+			"Final-Recipient: rfc822; ada@concordia.ca\r\nAction: failed\r\nStatus: 5.1.1\r\n\r\n--b--\r\n"
+		mockUseCase.On("HandleMailEvents", mock.Anything, []domain.MailEvent{{Type: domain.MailBounce,
+			Email: "ada@concordia.ca", Permanent: true, Diagnostic: "5.1.1"}}).Return(1, nil).Once()
+
+		w := post("message/rfc822", report, "webhook-token")
+
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-report", func(t *testing.T) {
+		w := post("message/rfc822", "Subject: hi\r\n\r\nhello", "webhook-token")
+
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("unsupported-content-type", func(t *testing.T) {
+		w := post("text/plain", "bounce", "webhook-token")
+
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("invalid-token", func(t *testing.T) {
+		w := post("application/json", `[]`, "guess")
+
+		assert.Equal(t, 401, w.Code)
+	})
+
+	t.Run("no-token-configured", func(t *testing.T) {
+		t.Setenv("MAIL_WEBHOOK_TOKEN", "")
+
+		w := post("application/json", `[]`, "")
+
+		assert.Equal(t, 401, w.Code)
+	})
+
+	t.Run("usecase-error", func(t *testing.T) {
+		mockUseCase.On("HandleMailEvents", mock.Anything, mock.Anything).Return(0, errors.New("err")).Once()
+
+		w := post("application/json", `[{"type":"complaint","email":"ada@concordia.ca"}]`, "webhook-token")
+
+		assert.Equal(t, 500, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,25 @@
+-- Emails waiting to be sent, sent, or given up. The worker claims due pending emails by pushing next_attempt_at
+-- forward, so an email whose worker died is tried again once the claim runs out. The body holds confirmation links
+-- and codes, so it is only kept until the email is sent or given up
+CREATE TABLE IF NOT EXISTS public.mail_delivery (
+    id text PRIMARY KEY,
+    recipient text NOT NULL,
+    body bytea,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+    attempts int NOT NULL DEFAULT 0,
+    last_error text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    next_attempt_at timestamp NOT NULL,
+    sent_at timestamp
+);
+CREATE INDEX IF NOT EXISTS mail_delivery_due ON public.mail_delivery (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS mail_delivery_status ON public.mail_delivery (status, created_at);
+
+-- Addresses that bounced for good or complained. Emails are stored lowercase
+CREATE TABLE IF NOT EXISTS public.mail_suppression (
+    email text PRIMARY KEY,
+    reason text NOT NULL CHECK (reason IN ('bounce', 'complaint')),
+    diagnostic text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL
+);
+CREATE INDEX IF NOT EXISTS mail_delivery_recipient ON public.mail_delivery (lower(recipient)) WHERE status = 'pending';
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/jackc/pgx/v4"
+	"time"
+)
+
+type mailRepository struct {
+	db pgxpoolmock.PgxPool
+}
+
+// NewMailRepository is the constructor
+func NewMailRepository(db pgxpoolmock.PgxPool) domain.MailRepository {
+	return &mailRepository{
+		db: db,
+	}
+}
+
+const (
+	deliveryColumns = `id, recipient, body, status, attempts, last_error, created_at, next_attempt_at, sent_at`
+	insertDelivery  = `INSERT INTO public.mail_delivery (id, recipient, body, status, attempts, created_at, next_attempt_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7);`
+	// SKIP LOCKED lets several workers claim at the same time without getting the same emails
+	claimDueDeliveries = `UPDATE public.mail_delivery SET attempts = attempts + 1, next_attempt_at = $2
+	WHERE id IN (SELECT id FROM public.mail_delivery WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED)
+	RETURNING ` + deliveryColumns + `;`
+	// the body is dropped once the email is sent or dead, since it isn't needed anymore and holds confirmation links.
+	// Only the worker of the latest attempt can record it: a worker whose lease ran out changes nothing
+	markDeliverySent = `UPDATE public.mail_delivery SET status = 'sent', sent_at = $3, last_error = '', body = NULL
+	WHERE id = $1 AND attempts = $2 AND status = 'pending';`
+	markDeliveryFailed = `UPDATE public.mail_delivery SET status = $3, last_error = $4, next_attempt_at = $5,
+		body = CASE WHEN $3 = 'dead' THEN NULL ELSE body END
+	WHERE id = $1 AND attempts = $2 AND status = 'pending';`
+	deleteOldDeliveries = `DELETE FROM public.mail_delivery WHERE status IN ('sent', 'dead') AND created_at < $1;`
+	selectDeliveries    = `SELECT ` + deliveryColumns + ` FROM public.mail_delivery WHERE status = $1
+	ORDER BY created_at DESC, id LIMIT $2 OFFSET $3;`
+	// a complaint is kept over a later bounce, since the owner asked for no emails
+	upsertSuppression = `INSERT INTO public.mail_suppression (email, reason, diagnostic, created_at) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (email) DO UPDATE SET reason = EXCLUDED.reason, diagnostic = EXCLUDED.diagnostic,
+		created_at = EXCLUDED.created_at
+	WHERE mail_suppression.reason <> 'complaint';`
+	giveUpDeliveriesTo = `UPDATE public.mail_delivery SET status = 'dead', last_error = $2, body = NULL
+	WHERE lower(recipient) = $1 AND status = 'pending';`
+	selectSuppression = `SELECT email, reason, diagnostic, created_at FROM public.mail_suppression WHERE email = $1;`
+)
+
+// Enqueue stores the email to be sent by the worker
+func (r *mailRepository) Enqueue(ctx context.Context, delivery *domain.MailDelivery) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, insertDelivery, delivery.ID, delivery.To, delivery.Body, string(delivery.Status), delivery.Attempts,
+		delivery.CreatedAt, delivery.NextAttemptAt)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// ClaimDue returns up to limit pending emails due at now, oldest first, counting the attempt. They aren't due again
+// for the lease, so other workers leave them alone while they are being sent
+func (r *mailRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.MailDelivery, error) {
+	rows, err := r.db.Query(ctx, claimDueDeliveries, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+// MarkSent records that the email was sent by the given attempt. Nothing changes if the email was claimed again since
+func (r *mailRepository) MarkSent(ctx context.Context, id string, attempts int, at time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, markDeliverySent, id, attempts, at)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// MarkFailed saves the status, the error and the next attempt of an email that couldn't be sent. Nothing changes if
+// the email was claimed again since its attempt
+func (r *mailRepository) MarkFailed(ctx context.Context, delivery *domain.MailDelivery) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, markDeliveryFailed, delivery.ID, delivery.Attempts, string(delivery.Status), delivery.LastError,
+		delivery.NextAttemptAt)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// DeleteOldDeliveries deletes the sent and dead emails created before the given time. Pending emails are kept.
+// Returns how many were deleted
+func (r *mailRepository) DeleteOldDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, deleteOldDeliveries, before)
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetDeliveries returns the emails with the status, newest first. Returns an empty slice if there are none
+func (r *mailRepository) GetDeliveries(ctx context.Context, status domain.MailStatus, page domain.Page) ([]domain.MailDelivery, error) {
+	rows, err := r.db.Query(ctx, selectDeliveries, string(status), page.Size, page.Offset())
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+func scanDeliveries(rows pgx.Rows) ([]domain.MailDelivery, error) {
+	deliveries := []domain.MailDelivery{}
+	for rows.Next() {
+		var delivery domain.MailDelivery
+		var status string
+		err := rows.Scan(&delivery.ID, &delivery.To, &delivery.Body, &status, &delivery.Attempts,
+			&delivery.LastError, &delivery.CreatedAt, &delivery.NextAttemptAt, &delivery.SentAt)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		delivery.Status = domain.MailStatus(status)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// Suppress stops the emails to the address: it is saved as suppressed and its queued emails are given up
+func (r *mailRepository) Suppress(ctx context.Context, suppression *domain.MailSuppression) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, upsertSuppression, suppression.Email, string(suppression.Reason), suppression.Diagnostic,
+		suppression.CreatedAt)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	_, err = tx.Exec(ctx, giveUpDeliveriesTo, suppression.Email, "address suppressed after a "+string(suppression.Reason))
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// GetSuppression returns the suppression of the lowercase address, or nil if emails can be sent to it
+func (r *mailRepository) GetSuppression(ctx context.Context, email string) (*domain.MailSuppression, error) {
+	rows, err := r.db.Query(ctx, selectSuppression, email)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	var suppression *domain.MailSuppression
+	for rows.Next() {
+		var reason string
+		suppression = &domain.MailSuppression{}
+		err = rows.Scan(&suppression.Email, &reason, &suppression.Diagnostic, &suppression.CreatedAt)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		suppression.Reason = domain.MailEventType(reason)
+	}
+	return suppression, nil
+}
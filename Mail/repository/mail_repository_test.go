@@ -0,0 +1,293 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/airbenders/profile/Mail/repository"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/pgxmocks"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var deliveryColumns = []string{"id", "recipient", "body", "status", "attempts", "last_error", "created_at",
+	"next_attempt_at", "sent_at"}
+
+func TestEnqueue(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+	now := time.Now()
+	delivery := &domain.MailDelivery{ID: "d", To: "ada@concordia.ca", Body: []byte("hello"),
+		Status: domain.MailPending, CreatedAt: now, NextAttemptAt: now}
+
+	t.Run("success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything,
+			[]interface{}{"d", "ada@concordia.ca", []byte("hello"), "pending", 0, now, now}).
+			Return(pgconn.CommandTag("INSERT 0 1"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		mr := repository.NewMailRepository(mockPool)
+		err := mr.Enqueue(context.Background(), delivery)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't-begin-transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("err"))
+
+		mr := repository.NewMailRepository(mockPool)
+		err := mr.Enqueue(context.Background(), delivery)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestClaimDue(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		expected := []domain.MailDelivery{{ID: "d", To: "ada@concordia.ca", Body: []byte("hello"),
+			Status: domain.MailPending, Attempts: 2, LastError: "timeout", CreatedAt: now, NextAttemptAt: now.Add(time.Minute)}}
+		pgxRows := pgxpoolmock.NewRows(deliveryColumns).
+			AddRow("d", "ada@concordia.ca", []byte("hello"), "pending", 2, "timeout", now, now.Add(time.Minute), nil).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), now, now.Add(time.Minute), 20).Return(pgxRows, nil)
+		mr := repository.NewMailRepository(mockPool)
+		deliveries, err := mr.ClaimDue(context.Background(), now, time.Minute, 20)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, deliveries)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("err"))
+		mr := repository.NewMailRepository(mockPool)
+		deliveries, err := mr.ClaimDue(context.Background(), now, time.Minute, 20)
+
+		assert.Error(t, err)
+		assert.Nil(t, deliveries)
+	})
+}
+
+func TestMarkDelivery(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+	now := time.Now()
+
+	t.Run("sent", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"d", 3, now}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		mr := repository.NewMailRepository(mockPool)
+		err := mr.MarkSent(context.Background(), "d", 3, now)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		delivery := &domain.MailDelivery{ID: "d", Status: domain.MailDead, Attempts: domain.MaxMailAttempts, LastError: "550",
+			NextAttemptAt: now}
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"d", domain.MaxMailAttempts, "dead", "550", now}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		mr := repository.NewMailRepository(mockPool)
+		err := mr.MarkFailed(context.Background(), delivery)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't exec transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("err")).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		mr := repository.NewMailRepository(mockPool)
+		err := mr.MarkSent(context.Background(), "d", 3, now)
+
+		assert.Error(t, err)
+		txMock.AssertExpectations(t)
+	})
+}
+
+func TestDeleteOldDeliveries(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+	before := time.Now().Add(-domain.MailRetention)
+
+	t.Run("success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{before}).
+			Return(pgconn.CommandTag("DELETE 3"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		mr := repository.NewMailRepository(mockPool)
+		count, err := mr.DeleteOldDeliveries(context.Background(), before)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't-begin-transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("err"))
+
+		mr := repository.NewMailRepository(mockPool)
+		_, err := mr.DeleteOldDeliveries(context.Background(), before)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestGetDeliveries(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	page := domain.Page{Number: 2, Size: 10}
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Now()
+		expected := []domain.MailDelivery{{ID: "d", To: "ada@concordia.ca", Body: []byte("hello"),
+			Status: domain.MailDead, Attempts: domain.MaxMailAttempts, LastError: "550", CreatedAt: now, NextAttemptAt: now}}
+		pgxRows := pgxpoolmock.NewRows(deliveryColumns).
+			AddRow("d", "ada@concordia.ca", []byte("hello"), "dead", domain.MaxMailAttempts, "550", now, now, nil).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "dead", 10, 10).Return(pgxRows, nil)
+		mr := repository.NewMailRepository(mockPool)
+		deliveries, err := mr.GetDeliveries(context.Background(), domain.MailDead, page)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, deliveries)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "dead", 10, 10).Return(nil, errors.New("err"))
+		mr := repository.NewMailRepository(mockPool)
+		deliveries, err := mr.GetDeliveries(context.Background(), domain.MailDead, page)
+
+		assert.Error(t, err)
+		assert.Nil(t, deliveries)
+	})
+}
+
+func TestSuppress(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	now := time.Now()
+	suppression := &domain.MailSuppression{Email: "ada@concordia.ca", Reason: domain.MailBounce,
+		Diagnostic: "550 5.1.1 user unknown", CreatedAt: now}
+
+	t.Run("success", func(t *testing.T) {
+		txMock := new(pgxmocks.TxMock)
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything,
+			[]interface{}{"ada@concordia.ca", "bounce", "550 5.1.1 user unknown", now}).
+			Return(pgconn.CommandTag("INSERT 0 1"), nil).Once()
+		txMock.On("Exec", mock.Anything, mock.Anything,
+			[]interface{}{"ada@concordia.ca", "address suppressed after a bounce"}).
+			Return(pgconn.CommandTag("UPDATE 2"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		mr := repository.NewMailRepository(mockPool)
+		err := mr.Suppress(context.Background(), suppression)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't exec transaction", func(t *testing.T) {
+		txMock := new(pgxmocks.TxMock)
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("err")).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		mr := repository.NewMailRepository(mockPool)
+		err := mr.Suppress(context.Background(), suppression)
+
+		assert.Error(t, err)
+		txMock.AssertExpectations(t)
+	})
+}
+
+func TestGetSuppression(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"email", "reason", "diagnostic", "created_at"}
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Now()
+		pgxRows := pgxpoolmock.NewRows(columns).AddRow("ada@concordia.ca", "complaint", "abuse", now).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "ada@concordia.ca").Return(pgxRows, nil)
+		mr := repository.NewMailRepository(mockPool)
+		suppression, err := mr.GetSuppression(context.Background(), "ada@concordia.ca")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.MailSuppression{Email: "ada@concordia.ca", Reason: domain.MailComplaint,
+			Diagnostic: "abuse", CreatedAt: now}, suppression)
+	})
+
+	t.Run("not-suppressed", func(t *testing.T) {
+		pgxRows := pgxpoolmock.NewRows(columns).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "ada@concordia.ca").Return(pgxRows, nil)
+		mr := repository.NewMailRepository(mockPool)
+		suppression, err := mr.GetSuppression(context.Background(), "ada@concordia.ca")
+
+		assert.NoError(t, err)
+		assert.Nil(t, suppression)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "ada@concordia.ca").Return(nil, errors.New("err"))
+		mr := repository.NewMailRepository(mockPool)
+		suppression, err := mr.GetSuppression(context.Background(), "ada@concordia.ca")
+
+		assert.Error(t, err)
+		assert.Nil(t, suppression)
+	})
+}
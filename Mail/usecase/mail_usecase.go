@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/google/uuid"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+const (
+	// batchSize is how many emails a worker claims at once
+	batchSize = 20
+	// sendTimeout bounds sending one email. The transport may give up sooner
+	sendTimeout = 30 * time.Second
+	// claimLease is how long claimed emails are left to their worker before others can try them. The batch is sent
+	// one email at a time, so it lasts long enough for all of them to time out, plus one send to record the results
+	claimLease = (batchSize + 1) * sendTimeout
+	// The wait before the next attempt doubles after every failed one, from firstRetryDelay up to maxRetryDelay
+	firstRetryDelay = 30 * time.Second
+	maxRetryDelay   = time.Hour
+)
+
+// mailUseCase struct implements MailUseCase interface
+type mailUseCase struct {
+	r         domain.MailRepository
+	transport domain.Mailer
+	timeout   time.Duration
+	now       func() time.Time
+}
+
+// NewMailUseCase is the constructor. Queued emails are sent with the transport
+func NewMailUseCase(r domain.MailRepository, transport domain.Mailer, timeout time.Duration) domain.MailUseCase {
+	return &mailUseCase{
+		r:         r,
+		transport: transport,
+		timeout:   timeout,
+		now:       time.Now,
+	}
+}
+
+// SendSimpleMail queues the email. It is sent by DeliverQueuedMail. Returns a 400 if the address is suppressed
+func (u *mailUseCase) SendSimpleMail(c context.Context, to string, body []byte) error {
+	ctx, cancel := context.WithTimeout(c, u.timeout)
+	defer cancel()
+
+	suppression, err := u.r.GetSuppression(ctx, normalizeAddress(to))
+	if err != nil {
+		return err
+	}
+	if suppression != nil {
+		return errors.NewBadRequestError(suppression.Message())
+	}
+
+	now := u.now()
+	return u.r.Enqueue(ctx, &domain.MailDelivery{
+		ID:            uuid.New().String(),
+		To:            to,
+		Body:          body,
+		Status:        domain.MailPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	})
+}
+
+// DeliverQueuedMail sends the due emails, then checks again every interval. Several can run at once
+func (u *mailUseCase) DeliverQueuedMail(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		// a full batch means there may be more waiting
+		for u.deliverDue() == batchSize {
+		}
+	}
+}
+
+// deliverDue sends one batch of due emails. Returns how many were claimed
+func (u *mailUseCase) deliverDue() int {
+	ctx, cancel := context.WithTimeout(context.Background(), u.timeout)
+	deliveries, err := u.r.ClaimDue(ctx, u.now(), claimLease, batchSize)
+	cancel()
+	if err != nil {
+		log.Println("failed to claim queued emails", err)
+		return 0
+	}
+
+	for i := range deliveries {
+		u.deliver(&deliveries[i])
+	}
+	return len(deliveries)
+}
+
+// deliver sends the claimed email and records how it went
+func (u *mailUseCase) deliver(delivery *domain.MailDelivery) {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	sendErr := u.transport.SendSimpleMail(ctx, delivery.To, delivery.Body)
+	cancel()
+
+	ctx, cancel = context.WithTimeout(context.Background(), u.timeout)
+	defer cancel()
+	now := u.now()
+	if sendErr == nil {
+		if err := u.r.MarkSent(ctx, delivery.ID, delivery.Attempts, now); err != nil {
+			log.Println("failed to mark email as sent", delivery.ID, err)
+		}
+		return
+	}
+
+	delivery.LastError = sendErr.Error()
+	if delivery.Attempts >= domain.MaxMailAttempts {
+		delivery.Status = domain.MailDead
+		log.Printf("gave up on email %s after %d attempts: %v\n", delivery.ID, delivery.Attempts, sendErr)
+	} else {
+		delivery.NextAttemptAt = now.Add(retryDelay(delivery.Attempts))
+	}
+	if err := u.r.MarkFailed(ctx, delivery); err != nil {
+		log.Println("failed to mark email as failed", delivery.ID, err)
+	}
+}
+
+// retryDelay is the wait after the given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// PurgeOldDeliveries deletes the sent and dead emails older than domain.MailRetention, then again every interval.
+// Meant to be run in its own goroutine
+func (u *mailUseCase) PurgeOldDeliveries(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		u.deleteOldDeliveries()
+	}
+}
+
+func (u *mailUseCase) deleteOldDeliveries() {
+	ctx, cancel := context.WithTimeout(context.Background(), u.timeout)
+	defer cancel()
+
+	count, err := u.r.DeleteOldDeliveries(ctx, u.now().Add(-domain.MailRetention))
+	if err != nil {
+		log.Println("failed to purge emails", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("purged %d old emails\n", count)
+	}
+}
+
+// GetFailedDeliveries returns the emails given up as dead, newest first
+func (u *mailUseCase) GetFailedDeliveries(c context.Context, page domain.Page) ([]domain.MailDelivery, error) {
+	ctx, cancel := context.WithTimeout(c, u.timeout)
+	defer cancel()
+
+	return u.r.GetDeliveries(ctx, domain.MailDead, page)
+}
+
+// HandleMailEvents suppresses the addresses of permanent bounces and complaints. Returns how many events suppressed
+// their address. Nothing is saved if an event is invalid
+func (u *mailUseCase) HandleMailEvents(c context.Context, events []domain.MailEvent) (int, error) {
+	ctx, cancel := context.WithTimeout(c, u.timeout)
+	defer cancel()
+
+	for i, event := range events {
+		if event.Type != domain.MailBounce && event.Type != domain.MailComplaint {
+			return 0, errors.NewBadRequestError(fmt.Sprintf("event %d: type must be %s or %s", i, domain.MailBounce,
+				domain.MailComplaint))
+		}
+		address, err := mail.ParseAddress(event.Email)
+		if err != nil {
+			return 0, errors.NewBadRequestError(fmt.Sprintf("event %d: invalid email %q", i, event.Email))
+		}
+		events[i].Email = normalizeAddress(address.Address)
+	}
+
+	suppressed := 0
+	now := u.now()
+	for _, event := range events {
+		if !event.Suppresses() {
+			continue
+		}
+		err := u.r.Suppress(ctx, &domain.MailSuppression{
+			Email:      event.Email,
+			Reason:     event.Type,
+			Diagnostic: event.Diagnostic,
+			CreatedAt:  now,
+		})
+		if err != nil {
+			return suppressed, err
+		}
+		log.Printf("suppressed %s after a %s: %s\n", event.Email, event.Type, event.Diagnostic)
+		suppressed++
+	}
+	return suppressed, nil
+}
+
+// normalizeAddress is how addresses are stored in the suppressions
+func normalizeAddress(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
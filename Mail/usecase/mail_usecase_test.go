@@ -0,0 +1,198 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/airbenders/profile/Mail/usecase"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/domain/mocks"
+	e "github.com/airbenders/profile/utils/errors"
+	"github.com/airbenders/profile/utils/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSendSimpleMail(t *testing.T) {
+	t.Run("queued", func(t *testing.T) {
+		mockMailRepo := new(mocks.MailRepositoryMock)
+		mockMailRepo.On("GetSuppression", mock.Anything, "ada@concordia.ca").Return(nil, nil).Once()
+		mockMailRepo.On("Enqueue", mock.Anything, mock.MatchedBy(func(delivery *domain.MailDelivery) bool {
+			return delivery.ID != "" && delivery.To == "Ada@Concordia.ca" && string(delivery.Body) == "hello" &&
+				delivery.Status == domain.MailPending && !delivery.NextAttemptAt.After(time.Now())
+		})).Return(nil).Once()
+		transport := mailer.NewMemoryTransport()
+		u := usecase.NewMailUseCase(mockMailRepo, transport, time.Second)
+
+		err := u.SendSimpleMail(context.TODO(), "Ada@Concordia.ca", []byte("hello"))
+
+		assert.NoError(t, err)
+		assert.Empty(t, transport.Messages())
+		mockMailRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-suppressed", func(t *testing.T) {
+		mockMailRepo := new(mocks.MailRepositoryMock)
+		mockMailRepo.On("GetSuppression", mock.Anything, "ada@concordia.ca").
+			Return(&domain.MailSuppression{Email: "ada@concordia.ca", Reason: domain.MailBounce}, nil).Once()
+		u := usecase.NewMailUseCase(mockMailRepo, nil, time.Second)
+
+		err := u.SendSimpleMail(context.TODO(), "ada@concordia.ca", []byte("hello"))
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockMailRepo.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandleMailEvents(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockMailRepo := new(mocks.MailRepositoryMock)
+		mockMailRepo.On("Suppress", mock.Anything, mock.MatchedBy(func(suppression *domain.MailSuppression) bool {
+			return suppression.Email == "ada@concordia.ca" && suppression.Reason == domain.MailBounce &&
+				suppression.Diagnostic == "550 5.1.1 user unknown"
+		})).Return(nil).Once()
+		mockMailRepo.On("Suppress", mock.Anything, mock.MatchedBy(func(suppression *domain.MailSuppression) bool {
+			return suppression.Email == "bob@mcgill.ca" && suppression.Reason == domain.MailComplaint
+		})).Return(nil).Once()
+		u := usecase.NewMailUseCase(mockMailRepo, nil, time.Second)
+
+		suppressed, err := u.HandleMailEvents(context.TODO(), []domain.MailEvent{
+			{Type: domain.MailBounce, Email: "Ada@Concordia.ca", Permanent: true, Diagnostic: "550 5.1.1 user unknown"},
+			{Type: domain.MailBounce, Email: "carl@concordia.ca", Diagnostic: "452 mailbox full"},
+			{Type: domain.MailComplaint, Email: "Bob <bob@mcgill.ca>"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, suppressed)
+		mockMailRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-invalid-event", func(t *testing.T) {
+		mockMailRepo := new(mocks.MailRepositoryMock)
+		u := usecase.NewMailUseCase(mockMailRepo, nil, time.Second)
+
+		for _, event := range []domain.MailEvent{
+			{Type: "delivered", Email: "ada@concordia.ca"},
+			{Type: domain.MailBounce, Email: "ada", Permanent: true},
+		} {
+			_, err := u.HandleMailEvents(context.TODO(), []domain.MailEvent{
+				{Type: domain.MailComplaint, Email: "bob@mcgill.ca"}, event})
+
+			assert.Error(t, err)
+			assert.Equal(t, 400, err.(*e.RestError).Code)
+		}
+		mockMailRepo.AssertNotCalled(t, "Suppress", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error-suppress", func(t *testing.T) {
+		mockMailRepo := new(mocks.MailRepositoryMock)
+		mockMailRepo.On("Suppress", mock.Anything, mock.Anything).Return(errors.New("err")).Once()
+		u := usecase.NewMailUseCase(mockMailRepo, nil, time.Second)
+
+		_, err := u.HandleMailEvents(context.TODO(), []domain.MailEvent{{Type: domain.MailComplaint, Email: "bob@mcgill.ca"}})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDeliverQueuedMail(t *testing.T) {
+	t.Run("sent", func(t *testing.T) {
+		mockMailRepo := new(mocks.MailRepositoryMock)
+		delivery := domain.MailDelivery{ID: "d", To: "ada@concordia.ca", Body: []byte("hello"), Attempts: 1}
+		mockMailRepo.On("ClaimDue", mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything, mock.Anything).
+			Return([]domain.MailDelivery{delivery}, nil).Once()
+		sent := make(chan string, 1)
+		mockMailRepo.On("MarkSent", mock.Anything, "d", 1, mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) { sent <- args.String(1) }).Return(nil).Once()
+		transport := mailer.NewMemoryTransport()
+		u := usecase.NewMailUseCase(mockMailRepo, transport, time.Second)
+
+		go u.DeliverQueuedMail(time.Hour)
+
+		assert.Equal(t, "d", <-sent)
+		message, ok := transport.Last("ada@concordia.ca")
+		assert.True(t, ok)
+		assert.Equal(t, "hello", string(message.Body))
+	})
+
+	t.Run("retried-later", func(t *testing.T) {
+		mockMailRepo := new(mocks.MailRepositoryMock)
+		delivery := domain.MailDelivery{ID: "d", To: "ada@concordia.ca", Status: domain.MailPending, Attempts: 3}
+		failed := make(chan *domain.MailDelivery, 1)
+		mockMailRepo.On("ClaimDue", mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything, mock.Anything).
+			Return([]domain.MailDelivery{delivery}, nil).Once()
+		mockMailRepo.On("MarkFailed", mock.Anything, mock.AnythingOfType("*domain.MailDelivery")).
+			Run(func(args mock.Arguments) { failed <- args.Get(1).(*domain.MailDelivery) }).Return(nil).Once()
+		transport := mailer.NewMemoryTransport()
+		transport.FailWith(errors.New("smtp: can't connect"))
+		u := usecase.NewMailUseCase(mockMailRepo, transport, time.Second)
+
+		start := time.Now()
+		go u.DeliverQueuedMail(time.Hour)
+
+		retried := <-failed
+		assert.Equal(t, domain.MailPending, retried.Status)
+		assert.Equal(t, "smtp: can't connect", retried.LastError)
+		// the third failure waits four times the first delay
+		assert.WithinDuration(t, start.Add(2*time.Minute), retried.NextAttemptAt, time.Second)
+	})
+
+	t.Run("dead", func(t *testing.T) {
+		mockMailRepo := new(mocks.MailRepositoryMock)
+		delivery := domain.MailDelivery{ID: "d", To: "ada@concordia.ca", Status: domain.MailPending,
+			Attempts: domain.MaxMailAttempts}
+		failed := make(chan *domain.MailDelivery, 1)
+		mockMailRepo.On("ClaimDue", mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything, mock.Anything).
+			Return([]domain.MailDelivery{delivery}, nil).Once()
+		mockMailRepo.On("MarkFailed", mock.Anything, mock.AnythingOfType("*domain.MailDelivery")).
+			Run(func(args mock.Arguments) { failed <- args.Get(1).(*domain.MailDelivery) }).Return(nil).Once()
+		transport := mailer.NewMemoryTransport()
+		transport.FailWith(errors.New("550 no such user"))
+		u := usecase.NewMailUseCase(mockMailRepo, transport, time.Second)
+
+		go u.DeliverQueuedMail(time.Hour)
+
+		dead := <-failed
+		assert.Equal(t, domain.MailDead, dead.Status)
+		assert.Equal(t, "550 no such user", dead.LastError)
+	})
+}
+
+func TestPurgeOldDeliveries(t *testing.T) {
+	mockMailRepo := new(mocks.MailRepositoryMock)
+	purged := make(chan time.Time, 1)
+	mockMailRepo.On("DeleteOldDeliveries", mock.Anything, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			select {
+			case purged <- args.Get(1).(time.Time):
+			default:
+			}
+		}).Return(int64(2), nil)
+	u := usecase.NewMailUseCase(mockMailRepo, nil, time.Second)
+
+	go u.PurgeOldDeliveries(time.Millisecond)
+
+	select {
+	case before := <-purged:
+		assert.WithinDuration(t, time.Now().Add(-domain.MailRetention), before, time.Minute)
+	case <-time.After(time.Second):
+		assert.Fail(t, "old emails were not purged")
+	}
+}
+
+func TestGetFailedDeliveries(t *testing.T) {
+	mockMailRepo := new(mocks.MailRepositoryMock)
+	page := domain.Page{Number: 1, Size: 50}
+	deliveries := []domain.MailDelivery{{ID: "d", Status: domain.MailDead}}
+	mockMailRepo.On("GetDeliveries", mock.Anything, domain.MailDead, page).Return(deliveries, nil).Once()
+	u := usecase.NewMailUseCase(mockMailRepo, nil, time.Second)
+
+	returned, err := u.GetFailedDeliveries(context.TODO(), page)
+
+	assert.NoError(t, err)
+	assert.Equal(t, deliveries, returned)
+	mockMailRepo.AssertExpectations(t)
+}
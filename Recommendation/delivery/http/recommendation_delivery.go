@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 50
+)
+
+// RecommendationHandler struct
+type RecommendationHandler struct {
+	u domain.RecommendationUseCase
+}
+
+// NewRecommendationHandler is the constructor
+func NewRecommendationHandler(ru domain.RecommendationUseCase) *RecommendationHandler {
+	return &RecommendationHandler{u: ru}
+}
+
+// Recommend returns the best teammates for the logged in student in the class given as a query parameter
+func (h *RecommendationHandler) Recommend(c *gin.Context) {
+	class := c.Query("class")
+	if class == "" {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("please provide a class"))
+		return
+	}
+
+	limit := defaultLimit
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxLimit {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError("limit must be a number between 1 and 50"))
+			return
+		}
+	}
+
+	loggedID, _ := c.Get("loggedID")
+	studentID, _ := loggedID.(string)
+
+	ctx := c.Request.Context()
+	recommendations, err := h.u.Recommend(ctx, studentID, class, limit)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, recommendations)
+}
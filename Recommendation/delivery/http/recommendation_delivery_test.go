@@ -0,0 +1,87 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/airbenders/profile/Recommendation/delivery/http"
+	"github.com/airbenders/profile/app"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/domain/mocks"
+	e "github.com/airbenders/profile/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecommendationHandlerRecommend(t *testing.T) {
+	mockUseCase := new(mocks.RecommendationUseCase)
+	h := http.NewRecommendationHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(nil, nil, nil, nil, h, nil, mw, mw, parser)
+
+	t.Run("success", func(t *testing.T) {
+		recommendations := []domain.Recommendation{{
+			Student: domain.Student{ID: "def"},
+			Score:   8,
+			Reasons: []string{"goes to your school"},
+		}}
+		mockUseCase.On("Recommend", mock.Anything, "abc", "COMP 354", 5).Return(recommendations, nil).Once()
+		req := httptest.NewRequest("GET", "/api/v1/recommendations?class=COMP+354&limit=5", nil)
+		req.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code)
+		var received []domain.Recommendation
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &received))
+		assert.Equal(t, recommendations[0].Reasons, received[0].Reasons)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("default-limit", func(t *testing.T) {
+		mockUseCase.On("Recommend", mock.Anything, "abc", "COMP 354", 10).Return([]domain.Recommendation{}, nil).Once()
+		req := httptest.NewRequest("GET", "/api/v1/recommendations?class=COMP+354", nil)
+		req.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-class", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/recommendations", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("invalid-limit", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/recommendations?class=COMP+354&limit=500", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("usecase-rest-error", func(t *testing.T) {
+		restErr := e.NewNotFoundError("not found")
+		mockUseCase.On("Recommend", mock.Anything, "abc", "COMP 354", 10).Return(nil, restErr).Once()
+		req := httptest.NewRequest("GET", "/api/v1/recommendations?class=COMP+354", nil)
+		req.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, restErr.Code, w.Code)
+	})
+
+	t.Run("usecase-error", func(t *testing.T) {
+		mockUseCase.On("Recommend", mock.Anything, "abc", "COMP 354", 10).Return(nil, errors.New("error")).Once()
+		req := httptest.NewRequest("GET", "/api/v1/recommendations?class=COMP+354", nil)
+		req.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 500, w.Code)
+	})
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	studentRepository "github.com/airbenders/profile/Student/repository"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/driftprogramming/pgxpoolmock"
+)
+
+type recommendationRepository struct {
+	db pgxpoolmock.PgxPool
+}
+
+// NewRecommendationRepository is the constructor
+func NewRecommendationRepository(db pgxpoolmock.PgxPool) domain.RecommendationRepository {
+	return &recommendationRepository{
+		db: db,
+	}
+}
+
+const (
+	// maxCandidates keeps scoring cheap for very popular classes
+	maxCandidates = 500
+	selectWeights = `SELECT name, weight FROM public.recommendation_weight;`
+)
+
+var (
+	// selectCandidates ranks the candidates on the strongest signals of the score, the student's school first then
+	// the most classes shared with the student, so the ones kept by the limit are the best matches
+	selectCandidates = `WITH viewer AS (SELECT school, current_classes FROM public.student WHERE id = $2)
+	SELECT ` + studentRepository.StudentColumns + `,
+	(SELECT COUNT(*) FROM public.review r JOIN public.review_tag rt ON rt.review_id = r.id
+		JOIN public.tag t ON t.name = rt.tag_name WHERE r.reviewed = s.id AND t.positive) AS positive,
+	(SELECT COUNT(*) FROM public.review r JOIN public.review_tag rt ON rt.review_id = r.id
+		JOIN public.tag t ON t.name = rt.tag_name WHERE r.reviewed = s.id AND NOT t.positive) AS negative
+	FROM public.student s ` + studentRepository.SchoolJoin + ` LEFT JOIN viewer v ON true
+	WHERE s.current_classes @> ARRAY[$1::text] AND s.id <> $2 AND ` + studentRepository.Discoverable + `
+	AND ` + studentRepository.HiddenFrom("$2") + `
+	ORDER BY (s.school = v.school) IS TRUE DESC,
+		cardinality(ARRAY(SELECT unnest(s.current_classes) INTERSECT SELECT unnest(v.current_classes))) DESC, s.id
+	LIMIT $3;`
+)
+
+// GetCandidates returns the best ranked students taking the class, other than the student and the students hidden
+// from them by a block or a mute, with their school and their review tag counts
+func (r *recommendationRepository) GetCandidates(ctx context.Context, studentID string, class string) ([]domain.Candidate, error) {
+	rows, err := r.db.Query(ctx, selectCandidates, class, studentID, maxCandidates)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	candidates := []domain.Candidate{}
+	for rows.Next() {
+		var candidate domain.Candidate
+		err = studentRepository.ScanStudent(rows, &candidate.Student, &candidate.PositiveTags, &candidate.NegativeTags)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// GetWeights returns the weights configured in the db by name. Weights that aren't there aren't configured
+func (r *recommendationRepository) GetWeights(ctx context.Context) (map[string]float64, error) {
+	rows, err := r.db.Query(ctx, selectWeights)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	weights := make(map[string]float64)
+	for rows.Next() {
+		var name string
+		var weight float64
+		err = rows.Scan(&name, &weight)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
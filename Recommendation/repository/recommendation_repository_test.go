@@ -0,0 +1,89 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/airbenders/profile/Recommendation/repository"
+	"github.com/airbenders/profile/domain"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCandidates(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"id", "first_name", "last_name", "email", "general_info", "school", "current_classes",
+		"classes_taken", "created_at", "updated_at", "program", "year_of_study", "pronouns", "languages", "links",
+		"privacy", "avatar", "school_name", "school_country", "school_domains", "school_email", "school_verified_at",
+		"campus", "campus_name", "faculty", "faculty_name", "positive", "negative"}
+
+	t.Run("success", func(t *testing.T) {
+		schoolID, schoolName, country := "sc", "Concordia University", "Canada"
+		now := time.Now()
+		expected := []domain.Candidate{{
+			Student: domain.Student{ID: "b", FirstName: "c", LastName: "d", Email: "e", GeneralInfo: "f",
+				School: &domain.School{ID: "sc", Name: "Concordia University", Country: "Canada",
+					Domains: []string{"concordia.ca"}},
+				CurrentClasses: []string{"COMP 354"}, CreatedAt: now, UpdatedAt: now},
+			PositiveTags: 3,
+			NegativeTags: 1,
+		}}
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow("b", "c", "d", "e", "f", &schoolID, []string{"COMP 354"}, nil, now, now, nil, nil, nil, nil, nil, nil,
+				nil, &schoolName, &country, []string{"concordia.ca"}, nil, nil, nil, nil, nil, nil, 3, 1).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "COMP 354", "a", gomock.Any()).Return(pgxRows, nil)
+		rr := repository.NewRecommendationRepository(mockPool)
+		candidates, err := rr.GetCandidates(context.Background(), "a", "COMP 354")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, candidates)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("err"))
+		rr := repository.NewRecommendationRepository(mockPool)
+		candidates, err := rr.GetCandidates(context.Background(), "a", "COMP 354")
+
+		assert.Error(t, err)
+		assert.Nil(t, candidates)
+	})
+}
+
+func TestGetWeights(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		pgxRows := pgxpoolmock.NewRows([]string{"name", "weight"}).
+			AddRow("same_school", 2.5).
+			AddRow("negative_tag", 0.0).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any()).Return(pgxRows, nil)
+		rr := repository.NewRecommendationRepository(mockPool)
+		weights, err := rr.GetWeights(context.Background())
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, map[string]float64{"same_school": 2.5, "negative_tag": 0}, weights)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any()).Return(nil, errors.New("err"))
+		rr := repository.NewRecommendationRepository(mockPool)
+		weights, err := rr.GetWeights(context.Background())
+
+		assert.Error(t, err)
+		assert.Nil(t, weights)
+	})
+}
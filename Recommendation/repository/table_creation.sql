@@ -0,0 +1,14 @@
+-- Weights of the teammate recommendation score. Change them here to tune recommendations without a redeploy.
+-- Weights that have no row fall back to the defaults in the code
+CREATE TABLE public.recommendation_weight (
+    name text PRIMARY KEY,
+    weight double precision NOT NULL
+);
+
+INSERT INTO public.recommendation_weight (name, weight) VALUES
+    ('shared_class', 3),
+    ('shared_past_class', 1),
+    ('positive_tag', 0.5),
+    ('negative_tag', 1),
+    ('same_school', 5),
+    ('availability_hour', 0.25);
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+)
+
+const (
+	// weightsTTL is how long configured weights are used before being read again from the db
+	weightsTTL = time.Minute
+	// availabilityWindow is how far ahead availability overlap is counted
+	availabilityWindow = 7 * 24 * time.Hour
+)
+
+// recommendationUseCase struct implements RecommendationUseCase interface
+type recommendationUseCase struct {
+	rr      domain.RecommendationRepository
+	sr      domain.StudentRepository
+	timeout time.Duration
+
+	mu            sync.Mutex
+	weights       domain.RecommendationWeights
+	weightsLoaded time.Time
+	now           func() time.Time
+}
+
+// NewRecommendationUseCase is the constructor
+func NewRecommendationUseCase(rr domain.RecommendationRepository, sr domain.StudentRepository, timeout time.Duration) domain.RecommendationUseCase {
+	return &recommendationUseCase{
+		rr:      rr,
+		sr:      sr,
+		timeout: timeout,
+		now:     time.Now,
+	}
+}
+
+// Recommend ranks the students taking the class as possible teammates for the student, best first.
+// Ties are broken by id so the same data always gives the same recommendations
+func (u *recommendationUseCase) Recommend(c context.Context, studentID string, class string, limit int) ([]domain.Recommendation, error) {
+	ctx, cancel := context.WithTimeout(c, u.timeout)
+	defer cancel()
+
+	class, ok := domain.NormalizeClassCode(class)
+	if !ok {
+		return nil, errors.NewBadRequestError("invalid class code. Expected a subject followed by a number, like COMP 354")
+	}
+
+	student, err := u.sr.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("No such student with ID %s exists", studentID))
+	}
+
+	candidates, err := u.rr.GetCandidates(ctx, studentID, class)
+	if err != nil {
+		return nil, err
+	}
+
+	u.addAvailabilityOverlap(ctx, student, candidates)
+	weights := u.getWeights(ctx)
+	recommendations := make([]domain.Recommendation, 0, len(candidates))
+	for i := range candidates {
+		recommendations = append(recommendations, score(student, class, &candidates[i], weights))
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Student.ID < recommendations[j].Student.ID
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	for i := range recommendations {
+		recommendations[i].Student.HidePrivateFields(studentID)
+	}
+	return recommendations, nil
+}
+
+// getWeights returns the default weights overridden by the ones configured in the db. If the db can't be read the
+// last known weights are kept, so recommendations still work
+func (u *recommendationUseCase) getWeights(ctx context.Context) domain.RecommendationWeights {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !u.weightsLoaded.IsZero() && u.now().Sub(u.weightsLoaded) < weightsTTL {
+		return u.weights
+	}
+
+	configured, err := u.rr.GetWeights(ctx)
+	if err != nil {
+		log.Println("could not load recommendation weights:", err)
+		if u.weightsLoaded.IsZero() {
+			return domain.DefaultRecommendationWeights()
+		}
+		return u.weights
+	}
+
+	weights := domain.DefaultRecommendationWeights()
+	for name, value := range configured {
+		if !weights.Set(name, value) {
+			log.Println("unknown recommendation weight", name)
+		}
+	}
+	u.weights = weights
+	u.weightsLoaded = u.now()
+	return weights
+}
+
+// addAvailabilityOverlap fills in how long each candidate is free at the same time as the student over the next week.
+// Availability only improves the ranking, so recommendations are still made without it if it can't be read
+func (u *recommendationUseCase) addAvailabilityOverlap(ctx context.Context, student *domain.Student, candidates []domain.Candidate) {
+	if len(candidates) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(candidates)+1)
+	ids = append(ids, student.ID)
+	for i := range candidates {
+		ids = append(ids, candidates[i].Student.ID)
+	}
+	availabilities, err := u.sr.GetAvailabilities(ctx, ids)
+	if err != nil {
+		log.Println("could not load availabilities for recommendations:", err)
+		return
+	}
+
+	byStudent := make(map[string]*domain.Availability, len(availabilities))
+	for i := range availabilities {
+		byStudent[availabilities[i].StudentID] = &availabilities[i]
+	}
+	own, ok := byStudent[student.ID]
+	if !ok {
+		return
+	}
+	from := u.now()
+	to := from.Add(availabilityWindow)
+	free := own.FreeIntervals(from, to)
+	for i := range candidates {
+		if availability, ok := byStudent[candidates[i].Student.ID]; ok {
+			candidates[i].AvailabilityOverlap = domain.TotalDuration(
+				domain.IntersectIntervals(free, availability.FreeIntervals(from, to)))
+		}
+	}
+}
+
+// score adds up the weighted signals of the candidate, with a reason for each signal that counted
+func score(student *domain.Student, class string, candidate *domain.Candidate, w domain.RecommendationWeights) domain.Recommendation {
+	recommendation := domain.Recommendation{
+		Student: candidate.Student,
+		Reasons: []string{},
+	}
+
+	sharedClasses := 0
+	for _, shared := range intersect(student.CurrentClasses, candidate.Student.CurrentClasses) {
+		if shared != class {
+			sharedClasses++
+		}
+	}
+	if sharedClasses > 0 {
+		recommendation.Score += w.SharedClass * float64(sharedClasses)
+		recommendation.Reasons = append(recommendation.Reasons,
+			fmt.Sprintf("takes %d other %s with you", sharedClasses, plural(sharedClasses, "class", "classes")))
+	}
+
+	sharedPastClasses := len(intersect(student.ClassesTaken, candidate.Student.ClassesTaken))
+	if sharedPastClasses > 0 {
+		recommendation.Score += w.SharedPastClass * float64(sharedPastClasses)
+		recommendation.Reasons = append(recommendation.Reasons,
+			fmt.Sprintf("took %d of the same %s as you", sharedPastClasses, plural(sharedPastClasses, "class", "classes")))
+	}
+
+	if student.School != nil && candidate.Student.School != nil && student.School.ID == candidate.Student.School.ID {
+		recommendation.Score += w.SameSchool
+		recommendation.Reasons = append(recommendation.Reasons, "goes to your school")
+	}
+
+	if candidate.PositiveTags > 0 {
+		recommendation.Score += w.PositiveTag * float64(candidate.PositiveTags)
+		recommendation.Reasons = append(recommendation.Reasons,
+			fmt.Sprintf("received %d positive %s from teammates", candidate.PositiveTags, plural(candidate.PositiveTags, "tag", "tags")))
+	}
+
+	if candidate.NegativeTags > 0 {
+		recommendation.Score -= w.NegativeTag * float64(candidate.NegativeTags)
+		recommendation.Reasons = append(recommendation.Reasons,
+			fmt.Sprintf("received %d negative %s from teammates", candidate.NegativeTags, plural(candidate.NegativeTags, "tag", "tags")))
+	}
+
+	if hours := candidate.AvailabilityOverlap.Hours(); hours > 0 {
+		recommendation.Score += w.AvailabilityHour * hours
+		recommendation.Reasons = append(recommendation.Reasons,
+			fmt.Sprintf("is available at the same time as you %.1f hours a week", hours))
+	}
+
+	return recommendation
+}
+
+// intersect returns the elements of a that are also in b
+func intersect(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	var result []string
+	for _, s := range a {
+		if inB[s] {
+			result = append(result, s)
+			delete(inB, s)
+		}
+	}
+	return result
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
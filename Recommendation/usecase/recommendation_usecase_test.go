@@ -0,0 +1,131 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/airbenders/profile/Recommendation/usecase"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/domain/mocks"
+	e "github.com/airbenders/profile/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecommend(t *testing.T) {
+	student := &domain.Student{
+		ID:             "me",
+		School:         &domain.School{ID: "sc"},
+		CurrentClasses: []string{"COMP 354", "COMP 352"},
+		ClassesTaken:   []string{"COMP 248"},
+	}
+	candidates := []domain.Candidate{
+		{Student: domain.Student{ID: "c", School: &domain.School{ID: "other"}, CurrentClasses: []string{"COMP 354"}},
+			NegativeTags: 2},
+		{Student: domain.Student{ID: "d", School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 354"}}},
+		{Student: domain.Student{ID: "b", School: &domain.School{ID: "sc"},
+			CurrentClasses: []string{"COMP 354", "COMP 352"}, ClassesTaken: []string{"COMP 248"}},
+			PositiveTags: 2},
+		{Student: domain.Student{ID: "a", School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 354"}}},
+	}
+
+	// a week always has 4 hours where both are free, whenever it starts
+	availabilities := []domain.Availability{
+		{StudentID: "me", TimeZone: "UTC", Weekly: []domain.WeeklySlot{{Day: time.Monday, Start: 9 * 60, End: 17 * 60}}},
+		// 13:00 to 20:00 UTC
+		{StudentID: "b", TimeZone: "Etc/GMT+4", Weekly: []domain.WeeklySlot{{Day: time.Monday, Start: 9 * 60, End: 16 * 60}}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockRecommendationRepo := new(mocks.RecommendationRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "me").Return(student, nil).Twice()
+		mockRecommendationRepo.On("GetCandidates", mock.Anything, "me", "COMP 354").Return(candidates, nil).Twice()
+		mockStudentRepo.On("GetAvailabilities", mock.Anything, []string{"me", "c", "d", "b", "a"}).
+			Return(availabilities, nil).Twice()
+		// weights are cached, so they are only read once
+		mockRecommendationRepo.On("GetWeights", mock.Anything).
+			Return(map[string]float64{"same_school": 4, "unknown": 1}, nil).
+			Once()
+
+		u := usecase.NewRecommendationUseCase(mockRecommendationRepo, mockStudentRepo, time.Second)
+		recommendations, err := u.Recommend(context.TODO(), "me", "comp354", 3)
+
+		assert.NoError(t, err)
+		assert.Len(t, recommendations, 3)
+		assert.Equal(t, "b", recommendations[0].Student.ID)
+		assert.Equal(t, 3+1+4+2*0.5+4*0.25, recommendations[0].Score)
+		assert.Equal(t, []string{
+			"takes 1 other class with you",
+			"took 1 of the same class as you",
+			"goes to your school",
+			"received 2 positive tags from teammates",
+			"is available at the same time as you 4.0 hours a week",
+		}, recommendations[0].Reasons)
+		// ties are ordered by id
+		assert.Equal(t, "a", recommendations[1].Student.ID)
+		assert.Equal(t, "d", recommendations[2].Student.ID)
+		assert.Equal(t, 4.0, recommendations[2].Score)
+
+		again, err := u.Recommend(context.TODO(), "me", "COMP 354", 10)
+		assert.NoError(t, err)
+		assert.Len(t, again, 4)
+		assert.Equal(t, recommendations, again[:3])
+		assert.Equal(t, -2.0, again[3].Score)
+		assert.Equal(t, []string{"received 2 negative tags from teammates"}, again[3].Reasons)
+		mockStudentRepo.AssertExpectations(t)
+		mockRecommendationRepo.AssertExpectations(t)
+	})
+
+	t.Run("weights-error-uses-defaults", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockRecommendationRepo := new(mocks.RecommendationRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "me").Return(student, nil).Once()
+		mockRecommendationRepo.On("GetCandidates", mock.Anything, "me", "COMP 354").Return(candidates[1:2], nil).Once()
+		mockStudentRepo.On("GetAvailabilities", mock.Anything, mock.Anything).Return(nil, errors.New("error")).Once()
+		mockRecommendationRepo.On("GetWeights", mock.Anything).Return(nil, errors.New("error")).Once()
+
+		u := usecase.NewRecommendationUseCase(mockRecommendationRepo, mockStudentRepo, time.Second)
+		recommendations, err := u.Recommend(context.TODO(), "me", "COMP 354", 10)
+
+		assert.NoError(t, err)
+		assert.Len(t, recommendations, 1)
+		assert.Equal(t, domain.DefaultRecommendationWeights().SameSchool, recommendations[0].Score)
+	})
+
+	t.Run("invalid-class", func(t *testing.T) {
+		u := usecase.NewRecommendationUseCase(new(mocks.RecommendationRepositoryMock), new(mocks.StudentRepositoryMock), time.Second)
+		recommendations, err := u.Recommend(context.TODO(), "me", "not a class", 10)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		assert.Nil(t, recommendations)
+	})
+
+	t.Run("student-not-found", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "me").Return(&domain.Student{}, nil).Once()
+
+		u := usecase.NewRecommendationUseCase(new(mocks.RecommendationRepositoryMock), mockStudentRepo, time.Second)
+		recommendations, err := u.Recommend(context.TODO(), "me", "COMP 354", 10)
+
+		assert.Error(t, err)
+		assert.Equal(t, 404, err.(*e.RestError).Code)
+		assert.Nil(t, recommendations)
+	})
+
+	t.Run("candidates-error", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockRecommendationRepo := new(mocks.RecommendationRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "me").Return(student, nil).Once()
+		mockRecommendationRepo.On("GetCandidates", mock.Anything, "me", "COMP 354").Return(nil, errors.New("error")).Once()
+
+		u := usecase.NewRecommendationUseCase(mockRecommendationRepo, mockStudentRepo, time.Second)
+		recommendations, err := u.Recommend(context.TODO(), "me", "COMP 354", 10)
+
+		assert.Error(t, err)
+		assert.Nil(t, recommendations)
+	})
+}
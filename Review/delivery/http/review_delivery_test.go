@@ -30,7 +30,7 @@ func TestReviewHandlerAddReview(t *testing.T) {
 	h := http.NewReviewHandler(mockUseCase)
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	server := httptest.NewServer(app.Server(nil, nil, nil, h, mw, mw, parser))
+	server := httptest.NewServer(app.Server(nil, nil, nil, h, nil, nil, mw, mw, parser))
 	defer server.Close()
 
 	var mockReview domain.Review
@@ -162,7 +162,7 @@ func TestReviewHandlerGetReviewsBy(t *testing.T) {
 	h := http.NewReviewHandler(mockUseCase)
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(nil, nil, nil, h, mw, mw, parser)
+	r := app.Server(nil, nil, nil, h, nil, nil, mw, mw, parser)
 
 	var mockReviews []domain.Review
 	err := faker.FakeData(&mockReviews)
@@ -204,7 +204,7 @@ func TestReviewHandlerEditReview(t *testing.T) {
 	h := http.NewReviewHandler(mockUseCase)
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(nil, nil, nil, h, mw, mw, parser)
+	r := app.Server(nil, nil, nil, h, nil, nil, mw, mw, parser)
 
 	var mockReview domain.Review
 	err := faker.FakeData(&mockReview)
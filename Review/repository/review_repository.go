@@ -27,6 +27,12 @@ const (
 	getReviewsBy       = `SELECT * FROM review WHERE reviewer=$1`
 	deleteExistingTags = `DELETE FROM review_tag WHERE review_id=$1`
 	getTagsFor         = `SELECT tag_name FROM review_tag WHERE review_id=$1`
+	// getReviewsForAll loads the reviews of many students with their tags in one query
+	getReviewsForAll = `SELECT r.id, r.reviewer, r.reviewed, r.created_at,
+	COALESCE(array_agg(rt.tag_name ORDER BY rt.tag_name) FILTER (WHERE rt.tag_name IS NOT NULL), '{}')
+	FROM review r LEFT JOIN review_tag rt ON rt.review_id = r.id
+	WHERE r.reviewed = ANY($1)
+	GROUP BY r.id ORDER BY r.reviewed, r.created_at`
 )
 
 // AddReview adds the review to the review table as well as joins the tags
@@ -177,6 +183,31 @@ func (r *reviewRepository) GetReviewsFor(ctx context.Context, reviewed string) (
 	return reviews, nil
 }
 
+// GetReviewsForAll returns the reviews of all the reviewed students, grouped by student, in one query. Reviews
+// without tags have nil Tags, like the ones of GetReviewsFor
+func (r *reviewRepository) GetReviewsForAll(ctx context.Context, reviewed []string) ([]domain.Review, error) {
+	rows, err := r.db.Query(ctx, getReviewsForAll, reviewed)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	var reviews []domain.Review
+	for rows.Next() {
+		var review domain.Review
+		var tagNames []string
+		err = rows.Scan(&review.ID, &review.Reviewer.ID, &review.Reviewed.ID, &review.CreatedAt, &tagNames)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		for _, name := range tagNames {
+			review.Tags = append(review.Tags, &domain.Tag{Name: name})
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
+
 func (r *reviewRepository) GetReviewsBy(ctx context.Context, reviewer string) ([]domain.Review, error) {
 	rows, err := r.db.Query(ctx, getReviewsBy, reviewer)
 	if err != nil {
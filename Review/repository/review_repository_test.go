@@ -151,6 +151,44 @@ func TestGetReviewsBy(t *testing.T) {
 
 
 
+func TestGetReviewsForAll(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	columns := []string{"id", "reviewer", "reviewed", "created_at", "tags"}
+	now := time.Now()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		expected := []domain.Review{
+			{ID: "r", Reviewer: domain.Student{ID: "c"}, Reviewed: domain.Student{ID: "a"}, CreatedAt: now,
+				Tags: []*domain.Tag{{Name: "helpful"}, {Name: "on-time"}}},
+			{ID: "s", Reviewer: domain.Student{ID: "c"}, Reviewed: domain.Student{ID: "b"}, CreatedAt: now},
+		}
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow("r", "c", "a", now, []string{"helpful", "on-time"}).
+			AddRow("s", "c", "b", now, []string{}).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), []string{"a", "b"}).Return(pgxRows, nil)
+		rr := repository.NewReviewRepository(mockPool)
+
+		reviews, err := rr.GetReviewsForAll(context.Background(), []string{"a", "b"})
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, reviews)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("err"))
+		rr := repository.NewReviewRepository(mockPool)
+
+		_, err := rr.GetReviewsForAll(context.Background(), []string{"a"})
+
+		assert.Error(t, err)
+	})
+}
+
 func TestGetReviewsFor(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
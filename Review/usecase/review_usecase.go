@@ -26,7 +26,7 @@ func NewReviewUseCase(rr domain.ReviewRepository, sr domain.StudentRepository, t
 	}
 }
 
-// AddReview first checks if the person being reviewed exists.
+// AddReview first checks if the person being reviewed exists and isn't blocked from the reviewer.
 func (u *reviewUseCase) AddReview(c context.Context, review *domain.Review, reviewerID string) (*domain.Review, error) {
 	ctx, cancel := context.WithTimeout(c, u.timeout)
 	defer cancel()
@@ -38,6 +38,14 @@ func (u *reviewUseCase) AddReview(c context.Context, review *domain.Review, revi
 	if reflect.DeepEqual(student, &domain.Student{}) {
 		return nil, errors.NewBadRequestError("the person being reviewed doesn't exist")
 	}
+	// students blocked from each other can't see each other, so they can't tell the other one exists
+	blocked, err := u.sr.IsBlocked(ctx, reviewerID, review.Reviewed.ID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, errors.NewBadRequestError("the person being reviewed doesn't exist")
+	}
 
 	anyExistingReview, err := u.rr.GetReviewByAndFor(ctx, reviewerID, review.Reviewed.ID)
 	if err != nil {
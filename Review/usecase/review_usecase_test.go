@@ -154,6 +154,10 @@ func TestAddReview(t *testing.T) {
 			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
 			Return(&mockStudent, nil).
 			Once()
+		mockStudentRepo.
+			On("IsBlocked", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+			Return(false, nil).
+			Once()
 		mockReviewRepo.
 			On("GetReviewByAndFor", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
 			Return(nil, nil).
@@ -176,6 +180,10 @@ func TestAddReview(t *testing.T) {
 			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
 			Return(&mockStudent, nil).
 			Once()
+		mockStudentRepo.
+			On("IsBlocked", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+			Return(false, nil).
+			Once()
 		mockReviewRepo.
 			On("GetReviewByAndFor", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
 			Return(&mockReview, nil).
@@ -203,4 +211,23 @@ func TestAddReview(t *testing.T) {
 		assert.Nil(t, review)
 		mockReviewRepo.AssertExpectations(t)
 	})
+	t.Run("case reviewed blocked the reviewer", func(t *testing.T) {
+		mockStudentRepo.
+			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
+			Return(&mockStudent, nil).
+			Once()
+		mockStudentRepo.
+			On("IsBlocked", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+			Return(true, nil).
+			Once()
+
+		u := usecase.NewReviewUseCase(mockReviewRepo, mockStudentRepo, time.Second)
+
+		review, err := u.AddReview(context.TODO(), &mockReview, mockStudent.ID)
+
+		assert.Error(t, err)
+		assert.Nil(t, review)
+		mockStudentRepo.AssertExpectations(t)
+		mockReviewRepo.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/static"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/gin-gonic/gin"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// defaultAppLink opens the app when APP_LINK isn't set
+const defaultAppLink = "smarties://app"
+
+// serverErrorDetails replaces the message of 5xx errors, which can be raw database errors
+const serverErrorDetails = "Please try again in a few minutes."
+
+var confirmationPage = template.Must(template.ParseFS(static.Files, "email_confirmed_screen.html"))
+
+// confirmationPageData fills the page shown when a confirmation link is opened in a browser
+type confirmationPageData struct {
+	Title   string
+	Message string
+	Details string
+	AppLink template.URL
+}
+
+// confirmationPages are the pages of the statuses of a link, with their HTTP status
+var confirmationPages = map[domain.ConfirmationStatus]struct {
+	code int
+	data confirmationPageData
+}{
+	domain.ConfirmationSucceeded: {http.StatusOK, confirmationPageData{
+		Title:   "Thanks!",
+		Message: "Your email is confirmed.",
+		Details: "You can head back to the app.",
+	}},
+	domain.ConfirmationAlreadyConfirmed: {http.StatusOK, confirmationPageData{
+		Title:   "All set!",
+		Message: "Your school is already confirmed.",
+		Details: "There is nothing else to do. You can head back to the app.",
+	}},
+	domain.ConfirmationExpired: {http.StatusGone, confirmationPageData{
+		Title:   "This link expired.",
+		Message: "It was too old or replaced by a newer email.",
+		Details: "Use the latest email we sent you, or ask for a new one in the app.",
+	}},
+	domain.ConfirmationInvalid: {http.StatusNotFound, confirmationPageData{
+		Title:   "This link doesn't work.",
+		Message: "We couldn't find your confirmation.",
+		Details: "Make sure you opened the whole link from the email, or ask for a new one in the app.",
+	}},
+}
+
+// renderConfirmationPage writes the page of the status, or of the error if there's one. Only the messages of client
+// errors are shown on the page, server errors are logged
+func renderConfirmationPage(c *gin.Context, status domain.ConfirmationStatus, err error) {
+	page, ok := confirmationPages[status]
+	if err != nil || !ok {
+		restErr, isRestErr := err.(*errors.RestError)
+		if !isRestErr {
+			restErr = errors.NewInternalServerError("")
+		}
+		page.code = restErr.Code
+		page.data = confirmationPageData{
+			Title:   "Something went wrong.",
+			Message: "Your school isn't confirmed yet.",
+			Details: restErr.Message,
+		}
+		if restErr.Code >= http.StatusInternalServerError {
+			log.Println("failed to confirm a school from a browser:", err)
+			page.data.Details = serverErrorDetails
+		}
+		status = "error"
+	}
+	page.data.AppLink = template.URL(appLink(status))
+
+	var body bytes.Buffer
+	if err = confirmationPage.Execute(&body, page.data); err != nil {
+		c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+		return
+	}
+	c.Data(page.code, "text/html; charset=utf-8", body.Bytes())
+}
+
+// appLink is the deep link back into the app, telling it how the confirmation went. APP_LINK is the base of the link
+func appLink(status domain.ConfirmationStatus) string {
+	base := os.Getenv("APP_LINK")
+	if base == "" {
+		base = defaultAppLink
+	}
+	return fmt.Sprintf("%s/school/confirmation?status=%s", strings.TrimSuffix(base, "/"), status)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -44,6 +45,36 @@ func (h *SchoolHandler) SearchStudentSchool(c *gin.Context) {
 	c.JSON(http.StatusOK, schools)
 }
 
+// SearchSchools is an endpoint that returns the schools matching a name, optionally in a country. Paginated with page
+// and limit
+func (h *SchoolHandler) SearchSchools(c *gin.Context) {
+	query, ok := c.GetQuery("q")
+	if !ok || strings.TrimSpace(query) == "" {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("please provide a query"))
+		return
+	}
+	page, err := httputils.ParsePage(c, 20, 50)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	schools, err := h.u.SearchSchools(ctx, query, c.Query("country"), page)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, schools)
+}
+
 func isEmailValid(e string) bool {
 	emailRegex := regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
 	return emailRegex.MatchString(e)
@@ -56,7 +87,9 @@ func extractSchool(email string) (bool, string) {
 	return true, strings.Split(email, "@")[1]
 }
 
-// SendConfirmationMail sends email to the client for school confirmation
+// SendConfirmationMail sends email to the client for school confirmation. When several schools use the domain of the
+// email, they are returned instead so the client can ask again with the chosen school. The email is in the language
+// of the Accept-Language header
 func (h *SchoolHandler) SendConfirmationMail(c *gin.Context) {
 	ctx := c.Request.Context()
 	key, _ := c.Get("loggedID")
@@ -69,7 +102,7 @@ func (h *SchoolHandler) SendConfirmationMail(c *gin.Context) {
 		return
 	}
 
-	schools, err := h.u.SearchSchoolByDomain(ctx, domainName)
+	schools, err := h.u.ResolveSchool(ctx, domainName, c.Query("school"))
 	if err != nil {
 		switch v := err.(type) {
 		case *errors.RestError:
@@ -80,17 +113,23 @@ func (h *SchoolHandler) SendConfirmationMail(c *gin.Context) {
 			return
 		}
 	}
-
-	if len(schools) == 0 {
-		c.JSON(http.StatusBadRequest, errors.NewNotFoundError("no school matching the domain found"))
+	if len(schools) > 1 {
+		c.JSON(http.StatusMultipleChoices, domain.SchoolChoices{
+			Message: "several schools use this email domain. Send the id of yours as school",
+			Schools: schools,
+		})
 		return
 	}
 
 	school := schools[0]
-	err = h.u.SendConfirmation(ctx, &domain.Student{ID: loggedID}, email, &school)
+	err = h.u.SendConfirmation(ctx, &domain.Student{ID: loggedID}, email, &school,
+		domain.MatchLocale(c.GetHeader("Accept-Language")))
 	if err != nil {
 		switch v := err.(type) {
 		case *errors.RestError:
+			if v.RetryAfter > 0 {
+				c.Header("Retry-After", strconv.Itoa(v.RetryAfter))
+			}
 			c.JSON(v.Code, v)
 			return
 		default:
@@ -102,16 +141,86 @@ func (h *SchoolHandler) SendConfirmationMail(c *gin.Context) {
 	c.JSON(200, httputils.NewResponse("email sent"))
 }
 
-// ConfirmSchoolRegistration is an internal endpoint (not accessible from the app) that is embedded in the email
+// ResendConfirmationMail sends the latest pending confirmation email of the logged in student again
+func (h *SchoolHandler) ResendConfirmationMail(c *gin.Context) {
+	ctx := c.Request.Context()
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	err := h.u.ResendConfirmation(ctx, loggedID, domain.MatchLocale(c.GetHeader("Accept-Language")))
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			if v.RetryAfter > 0 {
+				c.Header("Retry-After", strconv.Itoa(v.RetryAfter))
+			}
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, httputils.NewResponse("email sent"))
+}
+
+// ConfirmSchoolRegistration is an internal endpoint (not accessible from the app) that is embedded in the email.
+// Browsers get a page explaining how it went, with a link back into the app. Other clients get JSON
 func (h *SchoolHandler) ConfirmSchoolRegistration(c *gin.Context) {
 	ctx := c.Request.Context()
 	token := c.Query("token")
+	wantsPage := c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML
 	if token == "" {
+		if wantsPage {
+			renderConfirmationPage(c, domain.ConfirmationInvalid, nil)
+			return
+		}
 		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("must provide a valid email"))
 		return
 	}
 
-	err := h.u.ConfirmSchoolEnrollment(ctx, token)
+	status, err := h.u.ConfirmSchoolEnrollment(ctx, token)
+	if wantsPage {
+		renderConfirmationPage(c, status, err)
+		return
+	}
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	switch status {
+	case domain.ConfirmationSucceeded:
+		c.JSON(http.StatusOK, httputils.NewResponse("school confirmed"))
+	case domain.ConfirmationAlreadyConfirmed:
+		c.JSON(http.StatusOK, httputils.NewResponse("school already confirmed"))
+	case domain.ConfirmationExpired:
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("token already expired or replaced by a newer one"))
+	default:
+		c.JSON(http.StatusNotFound, errors.NewNotFoundError("invalid token"))
+	}
+}
+
+// ConfirmSchoolWithCode confirms the logged in student's school with the code of the confirmation email
+func (h *SchoolHandler) ConfirmSchoolWithCode(c *gin.Context) {
+	var request domain.ConfirmationCodeRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("invalid data"))
+		return
+	}
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	ctx := c.Request.Context()
+	err = h.u.ConfirmSchoolWithCode(ctx, loggedID, request.Code)
 	if err != nil {
 		switch v := err.(type) {
 		case *errors.RestError:
@@ -125,3 +234,135 @@ func (h *SchoolHandler) ConfirmSchoolRegistration(c *gin.Context) {
 
 	c.JSON(http.StatusOK, httputils.NewResponse("school confirmed"))
 }
+
+// LeaveSchool removes the confirmed school of the logged in student
+func (h *SchoolHandler) LeaveSchool(c *gin.Context) {
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	ctx := c.Request.Context()
+	err := h.u.LeaveSchool(ctx, loggedID)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, httputils.NewResponse("school left"))
+}
+
+// GetSchoolHistory returns the schools the logged in student confirmed, newest first
+func (h *SchoolHandler) GetSchoolHistory(c *gin.Context) {
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	ctx := c.Request.Context()
+	history, err := h.u.GetSchoolHistory(ctx, loggedID)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetCampuses returns the campuses of the school
+func (h *SchoolHandler) GetCampuses(c *gin.Context) {
+	ctx := c.Request.Context()
+	campuses, err := h.u.GetCampuses(ctx, c.Param("id"))
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, campuses)
+}
+
+// GetFaculties returns the faculties of the school
+func (h *SchoolHandler) GetFaculties(c *gin.Context) {
+	ctx := c.Request.Context()
+	faculties, err := h.u.GetFaculties(ctx, c.Param("id"))
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, faculties)
+}
+
+// SelectSchoolUnits sets the campus and the faculty of the logged in student in their school
+func (h *SchoolHandler) SelectSchoolUnits(c *gin.Context) {
+	var units domain.SchoolUnits
+	err := c.ShouldBindJSON(&units)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("invalid data"))
+		return
+	}
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	ctx := c.Request.Context()
+	err = h.u.SelectSchoolUnits(ctx, loggedID, units)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, httputils.NewResponse("school units updated"))
+}
+
+// SearchCourses returns the courses of the logged in student's school matching the query
+func (h *SchoolHandler) SearchCourses(c *gin.Context) {
+	query, ok := c.GetQuery("q")
+	if !ok || strings.TrimSpace(query) == "" {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("please provide a query"))
+		return
+	}
+
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	ctx := c.Request.Context()
+	courses, err := h.u.SearchCourses(ctx, loggedID, query)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, courses)
+}
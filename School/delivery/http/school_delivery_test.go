@@ -14,8 +14,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
+	gohttp "net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 const failureMessage = "failed to read from message"
@@ -28,7 +32,7 @@ func TestSchoolHandlerSearchStudentSchool(t *testing.T) {
 	h := http.NewSchoolHandler(mockUseCase)
 	middleware := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	server := httptest.NewServer(app.Server(nil, h, nil, nil,
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
 		middleware, middleware, parser))
 	defer server.Close()
 
@@ -96,12 +100,53 @@ func TestSchoolHandlerSearchStudentSchool(t *testing.T) {
 	})
 }
 
+func TestSchoolHandlerSearchSchools(t *testing.T) {
+	mockUseCase := new(mocks.SchoolUseCase)
+	h := http.NewSchoolHandler(mockUseCase)
+	middleware := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
+		middleware, middleware, parser))
+	defer server.Close()
+
+	t.Run("success", func(t *testing.T) {
+		schools := []domain.School{{ID: "a", Name: "Cégep de Saint-Jérôme", Country: "Canada"}}
+		mockUseCase.On("SearchSchools", mock.Anything, "cegep st", "Canada", domain.Page{Number: 2, Size: 10}).
+			Return(schools, nil).Once()
+		response, err := server.Client().Get(server.URL + "/api/v1/schools?q=cegep+st&country=Canada&page=2&limit=10")
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 200, response.StatusCode)
+		var returned []domain.School
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&returned))
+		assert.Equal(t, schools, returned)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("no-query", func(t *testing.T) {
+		response, err := server.Client().Get(server.URL + "/api/v1/schools?q=+")
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 400, response.StatusCode)
+	})
+
+	t.Run("invalid-page", func(t *testing.T) {
+		response, err := server.Client().Get(server.URL + "/api/v1/schools?q=cegep&limit=500")
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 400, response.StatusCode)
+	})
+}
+
 func TestSchoolHandlerConfirmSchoolRegistration(t *testing.T) {
 	mockUseCase := new(mocks.SchoolUseCase)
 	h := http.NewSchoolHandler(mockUseCase)
 	middleware := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	server := httptest.NewServer(app.Server(nil, h, nil, nil,
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
 		middleware, middleware, parser))
 	defer server.Close()
 
@@ -112,13 +157,23 @@ func TestSchoolHandlerConfirmSchoolRegistration(t *testing.T) {
 	t.Run("case success", func(t *testing.T) {
 		mockUseCase.
 			On("ConfirmSchoolEnrollment", mock.Anything, mock.AnythingOfType("string")).
-			Return(nil).Once()
+			Return(domain.ConfirmationSucceeded, nil).Once()
 		response, err := server.Client().Get(fmt.Sprintf("%s/school/confirmation/?token=test", server.URL))
 		assert.NoError(t, err)
 		defer response.Body.Close()
 
 		assert.Equal(t, 200, response.StatusCode)
 	})
+	t.Run("case expired", func(t *testing.T) {
+		mockUseCase.
+			On("ConfirmSchoolEnrollment", mock.Anything, mock.AnythingOfType("string")).
+			Return(domain.ConfirmationExpired, nil).Once()
+		response, err := server.Client().Get(fmt.Sprintf("%s/school/confirmation/?token=test", server.URL))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 400, response.StatusCode)
+	})
 	t.Run("case error: no token", func(t *testing.T) {
 		response, err := server.Client().Get(fmt.Sprintf("%s/school/confirmation/?token=", server.URL))
 		assert.NoError(t, err)
@@ -138,7 +193,7 @@ func TestSchoolHandlerConfirmSchoolRegistration(t *testing.T) {
 	t.Run("case error internal error", func(t *testing.T) {
 		mockUseCase.
 			On("ConfirmSchoolEnrollment", mock.Anything, mock.AnythingOfType("string")).
-			Return(errors.New("error")).Once()
+			Return(domain.ConfirmationStatus(""), errors.New("error")).Once()
 		response, err := server.Client().Get(fmt.Sprintf("%s/school/confirmation/?token=test", server.URL))
 		assert.NoError(t, err)
 		defaultErr := errors.New("error")
@@ -155,12 +210,84 @@ func TestSchoolHandlerConfirmSchoolRegistration(t *testing.T) {
 	})
 }
 
+func TestSchoolHandlerConfirmationPage(t *testing.T) {
+	mockUseCase := new(mocks.SchoolUseCase)
+	h := http.NewSchoolHandler(mockUseCase)
+	middleware := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
+		middleware, middleware, parser))
+	defer server.Close()
+	os.Setenv("APP_LINK", "smarties://test/")
+	t.Cleanup(func() { os.Unsetenv("APP_LINK") })
+
+	openInBrowser := func(token string) (int, string) {
+		request, err := gohttp.NewRequest(gohttp.MethodGet, server.URL+"/school/confirmation?token="+token, nil)
+		assert.NoError(t, err)
+		request.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+		response, err := server.Client().Do(request)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, "text/html; charset=utf-8", response.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(response.Body)
+		assert.NoError(t, err)
+		return response.StatusCode, string(body)
+	}
+
+	tests := []struct {
+		status  domain.ConfirmationStatus
+		err     error
+		code    int
+		content string
+	}{
+		{domain.ConfirmationSucceeded, nil, 200, "Your email is confirmed."},
+		{domain.ConfirmationAlreadyConfirmed, nil, 200, "Your school is already confirmed."},
+		{domain.ConfirmationExpired, nil, 410, "This link expired."},
+		{domain.ConfirmationInvalid, nil, 404, "This link doesn&#39;t work."},
+		{"", e.NewConflictError("this email already confirmed another student"), 409,
+			"this email already confirmed another student"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.status), func(t *testing.T) {
+			mockUseCase.On("ConfirmSchoolEnrollment", mock.Anything, "test").Return(test.status, test.err).Once()
+
+			code, body := openInBrowser("test")
+
+			assert.Equal(t, test.code, code)
+			assert.Contains(t, body, test.content)
+			status := test.status
+			if test.err != nil {
+				status = "error"
+			}
+			assert.Contains(t, body, `href="smarties://test/school/confirmation?status=`+string(status)+`"`)
+		})
+	}
+
+	t.Run("server-error-hidden", func(t *testing.T) {
+		mockUseCase.On("ConfirmSchoolEnrollment", mock.Anything, "test").
+			Return(domain.ConfirmationStatus(""), e.NewInternalServerError(`relation "confirmation" does not exist`)).Once()
+
+		code, body := openInBrowser("test")
+
+		assert.Equal(t, 500, code)
+		assert.Contains(t, body, "Please try again in a few minutes.")
+		assert.NotContains(t, body, "relation")
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		code, body := openInBrowser("")
+
+		assert.Equal(t, 404, code)
+		assert.Contains(t, body, "This link doesn&#39;t work.")
+	})
+}
+
 func TestSchoolHandlerSendConfirmationMail(t *testing.T) {
 	mockUseCase := new(mocks.SchoolUseCase)
 	h := http.NewSchoolHandler(mockUseCase)
 	middleware := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	server := httptest.NewServer(app.Server(nil, h, nil, nil,
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
 		middleware, middleware, parser))
 	defer server.Close()
 	var mockSchool *domain.School
@@ -172,12 +299,12 @@ func TestSchoolHandlerSendConfirmationMail(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockUseCase.
-			On("SearchSchoolByDomain", mock.Anything, mock.AnythingOfType("string")).
+			On("ResolveSchool", mock.Anything, "gmail.com", "").
 			Return(arrMockSchool, nil).
 			Once()
 		mockUseCase.On("SendConfirmation", mock.Anything,
 			mock.AnythingOfType("*domain.Student"), mock.AnythingOfType("string"),
-			mock.AnythingOfType("*domain.School")).Return(nil).Once()
+			mock.AnythingOfType("*domain.School"), "en").Return(nil).Once()
 		response, err := server.Client().Get(fmt.Sprintf(postSchoolEmailConfirmationPath, server.URL, testEmail))
 		assert.NoError(t, err)
 		defer response.Body.Close()
@@ -211,7 +338,7 @@ func TestSchoolHandlerSendConfirmationMail(t *testing.T) {
 
 	t.Run("can't find school error", func(t *testing.T) {
 		mockUseCase.
-			On("SearchSchoolByDomain", mock.Anything, mock.AnythingOfType("string")).
+			On("ResolveSchool", mock.Anything, "gmail.com", "").
 			Return(nil, errors.New("some error")).
 			Once()
 		response, err := server.Client().Get(fmt.Sprintf(postSchoolEmailConfirmationPath, server.URL, testEmail))
@@ -228,12 +355,12 @@ func TestSchoolHandlerSendConfirmationMail(t *testing.T) {
 
 	t.Run("confirmation-failure", func(t *testing.T) {
 		mockUseCase.
-			On("SearchSchoolByDomain", mock.Anything, mock.AnythingOfType("string")).
+			On("ResolveSchool", mock.Anything, "gmail.com", "").
 			Return(arrMockSchool, nil).
 			Once()
 		mockUseCase.On("SendConfirmation", mock.Anything,
 			mock.AnythingOfType("*domain.Student"), mock.AnythingOfType("string"),
-			mock.AnythingOfType("*domain.School")).Return(errors.New("some error")).Once()
+			mock.AnythingOfType("*domain.School"), "en").Return(errors.New("some error")).Once()
 		assert.NoError(t, err)
 		response, err := server.Client().Get(fmt.Sprintf(postSchoolEmailConfirmationPath, server.URL, testEmail))
 		assert.NoError(t, err)
@@ -246,4 +373,337 @@ func TestSchoolHandlerSendConfirmationMail(t *testing.T) {
 		}
 		mockUseCase.AssertExpectations(t)
 	})
+
+	t.Run("rate-limited", func(t *testing.T) {
+		mockUseCase.
+			On("ResolveSchool", mock.Anything, "gmail.com", "").
+			Return(arrMockSchool, nil).
+			Once()
+		mockUseCase.On("SendConfirmation", mock.Anything,
+			mock.AnythingOfType("*domain.Student"), mock.AnythingOfType("string"),
+			mock.AnythingOfType("*domain.School"), "en").
+			Return(e.NewTooManyRequestsError("slow down", 42*time.Second)).Once()
+		response, err := server.Client().Get(fmt.Sprintf(postSchoolEmailConfirmationPath, server.URL, testEmail))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 429, response.StatusCode)
+		assert.Equal(t, "42", response.Header.Get("Retry-After"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("several-schools", func(t *testing.T) {
+		schools := []domain.School{{ID: "a", Name: "Université de Montréal"}, {ID: "b", Name: "Polytechnique Montréal"}}
+		mockUseCase.On("ResolveSchool", mock.Anything, "umontreal.ca", "").Return(schools, nil).Once()
+		response, err := server.Client().Get(fmt.Sprintf(postSchoolEmailConfirmationPath, server.URL, "ada@umontreal.ca"))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 300, response.StatusCode)
+		var choices domain.SchoolChoices
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&choices))
+		assert.Equal(t, schools, choices.Schools)
+		mockUseCase.AssertNotCalled(t, "SendConfirmation", mock.Anything, mock.Anything, "ada@umontreal.ca", mock.Anything,
+			mock.Anything)
+	})
+
+	t.Run("chosen-school", func(t *testing.T) {
+		school := domain.School{ID: "b", Name: "Polytechnique Montréal"}
+		mockUseCase.On("ResolveSchool", mock.Anything, "umontreal.ca", "b").Return([]domain.School{school}, nil).Once()
+		mockUseCase.On("SendConfirmation", mock.Anything, mock.AnythingOfType("*domain.Student"), "ada@umontreal.ca",
+			&school, "fr").Return(nil).Once()
+		request, err := gohttp.NewRequest(gohttp.MethodGet, fmt.Sprintf(postSchoolEmailConfirmationPath+"&school=b",
+			server.URL, "ada@umontreal.ca"), nil)
+		assert.NoError(t, err)
+		request.Header.Set("Accept-Language", "fr-CA,fr;q=0.9,en;q=0.8")
+		response, err := server.Client().Do(request)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 200, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestSchoolHandlerResendConfirmationMail(t *testing.T) {
+	mockUseCase := new(mocks.SchoolUseCase)
+	h := http.NewSchoolHandler(mockUseCase)
+	middleware := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
+		middleware, middleware, parser))
+	defer server.Close()
+	path := server.URL + "/api/school/confirm/resend"
+
+	t.Run("success", func(t *testing.T) {
+		mockUseCase.On("ResendConfirmation", mock.Anything, mock.AnythingOfType("string"), "fr").Return(nil).Once()
+		request, err := gohttp.NewRequest(gohttp.MethodGet, path, nil)
+		assert.NoError(t, err)
+		request.Header.Set("Accept-Language", "fr")
+		response, err := server.Client().Do(request)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 200, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("nothing-to-resend", func(t *testing.T) {
+		mockUseCase.On("ResendConfirmation", mock.Anything, mock.AnythingOfType("string"), "en").
+			Return(e.NewNotFoundError("no pending confirmation")).Once()
+		response, err := server.Client().Get(path)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 404, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("rate-limited", func(t *testing.T) {
+		mockUseCase.On("ResendConfirmation", mock.Anything, mock.AnythingOfType("string"), "en").
+			Return(e.NewTooManyRequestsError("slow down", 1500*time.Millisecond)).Once()
+		response, err := server.Client().Get(path)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 429, response.StatusCode)
+		assert.Equal(t, "2", response.Header.Get("Retry-After"))
+		var restError e.RestError
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&restError))
+		assert.Equal(t, 2, restError.RetryAfter)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestSchoolHandlerConfirmSchoolWithCode(t *testing.T) {
+	mockUseCase := new(mocks.SchoolUseCase)
+	h := http.NewSchoolHandler(mockUseCase)
+	middleware := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
+		middleware, middleware, parser))
+	defer server.Close()
+	path := server.URL + "/api/school/confirm/code"
+
+	t.Run("success", func(t *testing.T) {
+		mockUseCase.On("ConfirmSchoolWithCode", mock.Anything, mock.AnythingOfType("string"), "042137").
+			Return(nil).Once()
+		response, err := server.Client().Post(path, applicationJSON, strings.NewReader(`{"code": "042137"}`))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 200, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-body", func(t *testing.T) {
+		response, err := server.Client().Post(path, applicationJSON, strings.NewReader(`{"code": 42`))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 400, response.StatusCode)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		mockUseCase.On("ConfirmSchoolWithCode", mock.Anything, mock.AnythingOfType("string"), "000000").
+			Return(e.NewForbiddenError("too many wrong codes")).Once()
+		response, err := server.Client().Post(path, applicationJSON, strings.NewReader(`{"code": "000000"}`))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 403, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestSchoolHandlerLeaveSchool(t *testing.T) {
+	mockUseCase := new(mocks.SchoolUseCase)
+	h := http.NewSchoolHandler(mockUseCase)
+	middleware := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
+		middleware, middleware, parser))
+	defer server.Close()
+	leave := func() *gohttp.Response {
+		request, err := gohttp.NewRequest(gohttp.MethodDelete, server.URL+"/api/school", nil)
+		assert.NoError(t, err)
+		response, err := server.Client().Do(request)
+		assert.NoError(t, err)
+		return response
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUseCase.On("LeaveSchool", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
+		response := leave()
+		defer response.Body.Close()
+
+		assert.Equal(t, 200, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("no-school", func(t *testing.T) {
+		mockUseCase.On("LeaveSchool", mock.Anything, mock.AnythingOfType("string")).
+			Return(e.NewBadRequestError("no confirmed school to leave")).Once()
+		response := leave()
+		defer response.Body.Close()
+
+		assert.Equal(t, 400, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestSchoolHandlerGetSchoolHistory(t *testing.T) {
+	mockUseCase := new(mocks.SchoolUseCase)
+	h := http.NewSchoolHandler(mockUseCase)
+	middleware := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
+		middleware, middleware, parser))
+	defer server.Close()
+	path := server.URL + "/api/school-history"
+
+	t.Run("success", func(t *testing.T) {
+		history := []domain.SchoolAffiliation{{School: domain.School{ID: "sc", Name: "Concordia University"},
+			JoinedAt: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)}}
+		mockUseCase.On("GetSchoolHistory", mock.Anything, mock.AnythingOfType("string")).Return(history, nil).Once()
+		response, err := server.Client().Get(path)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 200, response.StatusCode)
+		var returned []domain.SchoolAffiliation
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&returned))
+		assert.Equal(t, history, returned)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockUseCase.On("GetSchoolHistory", mock.Anything, mock.AnythingOfType("string")).
+			Return(nil, errors.New("error")).Once()
+		response, err := server.Client().Get(path)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 500, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestSchoolHandlerSchoolUnits(t *testing.T) {
+	mockUseCase := new(mocks.SchoolUseCase)
+	h := http.NewSchoolHandler(mockUseCase)
+	middleware := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
+		middleware, middleware, parser))
+	defer server.Close()
+	selectUnits := func(body string) *gohttp.Response {
+		request, err := gohttp.NewRequest(gohttp.MethodPut, server.URL+"/api/school-units", strings.NewReader(body))
+		assert.NoError(t, err)
+		response, err := server.Client().Do(request)
+		assert.NoError(t, err)
+		return response
+	}
+
+	t.Run("campuses", func(t *testing.T) {
+		campuses := []domain.Campus{{ID: "sgw", SchoolID: "sc", Name: "Sir George Williams"}}
+		mockUseCase.On("GetCampuses", mock.Anything, "sc").Return(campuses, nil).Once()
+		response, err := server.Client().Get(server.URL + "/api/schools/sc/campuses")
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 200, response.StatusCode)
+		var returned []domain.Campus
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&returned))
+		assert.Equal(t, campuses, returned)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("faculties-error", func(t *testing.T) {
+		mockUseCase.On("GetFaculties", mock.Anything, "sc").Return(nil, errors.New("error")).Once()
+		response, err := server.Client().Get(server.URL + "/api/schools/sc/faculties")
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 500, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("select-success", func(t *testing.T) {
+		units := domain.SchoolUnits{CampusID: "sgw", FacultyID: "eng"}
+		mockUseCase.On("SelectSchoolUnits", mock.Anything, mock.AnythingOfType("string"), units).Return(nil).Once()
+		response := selectUnits(`{"campus_id": "sgw", "faculty_id": "eng"}`)
+		defer response.Body.Close()
+
+		assert.Equal(t, 200, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("select-other-school", func(t *testing.T) {
+		units := domain.SchoolUnits{CampusID: "downtown"}
+		mockUseCase.On("SelectSchoolUnits", mock.Anything, mock.AnythingOfType("string"), units).
+			Return(e.NewBadRequestError("this campus is not in your school")).Once()
+		response := selectUnits(`{"campus_id": "downtown"}`)
+		defer response.Body.Close()
+
+		assert.Equal(t, 400, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("select-invalid-body", func(t *testing.T) {
+		response := selectUnits(`{"campus_id": 1}`)
+		defer response.Body.Close()
+
+		assert.Equal(t, 400, response.StatusCode)
+	})
+}
+
+func TestSchoolHandlerSearchCourses(t *testing.T) {
+	mockUseCase := new(mocks.SchoolUseCase)
+	h := http.NewSchoolHandler(mockUseCase)
+	middleware := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	server := httptest.NewServer(app.Server(nil, h, nil, nil, nil, nil,
+		middleware, middleware, parser))
+	defer server.Close()
+
+	courses := []domain.Course{{ID: "a", SchoolID: "sc", Subject: "COMP", Number: "354"}}
+
+	t.Run("case success", func(t *testing.T) {
+		mockUseCase.
+			On("SearchCourses", mock.Anything, mock.AnythingOfType("string"), "comp 354").
+			Return(courses, nil).
+			Once()
+		response, err := server.Client().Get(fmt.Sprintf("%s/api/v1/courses?q=comp+354", server.URL))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, 200, response.StatusCode)
+
+		responseBody, err := ioutil.ReadAll(response.Body)
+		assert.NoError(t, err)
+		var receivedCourses []domain.Course
+		err = json.Unmarshal(responseBody, &receivedCourses)
+		assert.NoError(t, err)
+		assert.EqualValues(t, courses, receivedCourses)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("case no query", func(t *testing.T) {
+		response, err := server.Client().Get(fmt.Sprintf("%s/api/v1/courses?q=", server.URL))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, 400, response.StatusCode)
+	})
+
+	t.Run("case school not confirmed", func(t *testing.T) {
+		mockUseCase.
+			On("SearchCourses", mock.Anything, mock.AnythingOfType("string"), "comp").
+			Return(nil, e.NewBadRequestError("confirm your school before searching its courses")).
+			Once()
+		response, err := server.Client().Get(fmt.Sprintf("%s/api/v1/courses?q=comp", server.URL))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, 400, response.StatusCode)
+		mockUseCase.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,43 @@
+-- One-off data migrations, applied after the schema files. Each is recorded by name once it ran, so applying this
+-- file again skips it
+CREATE TABLE IF NOT EXISTS public.schema_migration (
+    name text PRIMARY KEY,
+    applied_at timestamp NOT NULL
+);
+
+-- classes were stored as typed by students. Bring them to the normalized "COMP 354" form
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM public.schema_migration WHERE name = 'normalize_class_codes') THEN
+        UPDATE public.student SET
+            current_classes = ARRAY(SELECT DISTINCT upper(regexp_replace(c, '^\s*([A-Za-z]{2,5})[\s\-_.]*([0-9]{3,4}[A-Za-z]?)\s*$', '\1 \2'))
+                                    FROM unnest(current_classes) AS c),
+            classes_taken = ARRAY(SELECT DISTINCT upper(regexp_replace(c, '^\s*([A-Za-z]{2,5})[\s\-_.]*([0-9]{3,4}[A-Za-z]?)\s*$', '\1 \2'))
+                                  FROM unnest(classes_taken) AS c);
+        INSERT INTO public.schema_migration (name, applied_at) VALUES ('normalize_class_codes', now());
+    END IF;
+END
+$$;
+
+-- classes added before enrollments were recorded have no enrollment, so they would never be rolled over to the
+-- classes taken. They are enrolled in the term of the migration, computed in UTC like domain.TermOf
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM public.schema_migration WHERE name = 'enroll_current_classes') THEN
+        INSERT INTO public.enrollment (st_id, class, term, term_start, term_end, status, updated_at)
+        SELECT s.id, c.class, t.term, t.term_start, t.term_end, 'enrolled', now() AT TIME ZONE 'UTC'
+        FROM public.student s, unnest(s.current_classes) AS c(class), (
+            SELECT CASE WHEN m >= 9 THEN 'Fall' WHEN m >= 5 THEN 'Summer' ELSE 'Winter' END || ' ' || y AS term,
+                make_timestamp(y, CASE WHEN m >= 9 THEN 9 WHEN m >= 5 THEN 5 ELSE 1 END, 1, 0, 0, 0) AS term_start,
+                CASE WHEN m >= 9 THEN make_timestamp(y + 1, 1, 1, 0, 0, 0)
+                    ELSE make_timestamp(y, CASE WHEN m >= 5 THEN 9 ELSE 5 END, 1, 0, 0, 0) END AS term_end
+            FROM (SELECT extract(year FROM now() AT TIME ZONE 'UTC')::int AS y,
+                extract(month FROM now() AT TIME ZONE 'UTC')::int AS m) today
+        ) t
+        WHERE NOT EXISTS (SELECT 1 FROM public.enrollment e
+            WHERE e.st_id = s.id AND e.class = c.class AND e.status = 'enrolled')
+        ON CONFLICT (st_id, class, term) DO NOTHING;
+        INSERT INTO public.schema_migration (name, applied_at) VALUES ('enroll_current_classes', now());
+    END IF;
+END
+$$;
@@ -16,4 +16,97 @@ CREATE TABLE public.confirmation (
     st_id text not null REFERENCES student(id),
     sc_id text not null REFERENCES school(id),
     created_at timestamp
-);
\ No newline at end of file
+);
+
+CREATE TABLE IF NOT EXISTS public.course (
+    id text PRIMARY KEY NOT NULL,
+    school text NOT NULL REFERENCES school(id),
+    subject text NOT NULL,
+    number text NOT NULL,
+    title text,
+    term text,
+    UNIQUE (school, subject, number, term)
+);
+
+CREATE INDEX IF NOT EXISTS course_school_code_idx ON public.course (school, (subject || ' ' || number) text_pattern_ops);
+
+ALTER TABLE public.confirmation ADD COLUMN IF NOT EXISTS email text NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS confirmation_st_id_created_at ON public.confirmation (st_id, created_at);
+
+CREATE TABLE IF NOT EXISTS public.confirmation_send (
+    token text NOT NULL REFERENCES confirmation(token) ON DELETE CASCADE,
+    st_id text NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+    email text NOT NULL,
+    sent_at timestamp NOT NULL
+);
+CREATE INDEX IF NOT EXISTS confirmation_send_st_id_sent_at ON public.confirmation_send (st_id, sent_at);
+CREATE INDEX IF NOT EXISTS confirmation_send_email_sent_at ON public.confirmation_send (email, sent_at);
+
+-- tokens are stored as their SHA-256 and can only be used once
+ALTER TABLE public.confirmation ADD COLUMN IF NOT EXISTS consumed_at timestamp;
+ALTER TABLE public.confirmation_send DROP CONSTRAINT IF EXISTS confirmation_send_token_fkey;
+UPDATE public.confirmation SET token = encode(sha256(token::bytea), 'hex') WHERE length(token) <> 64;
+UPDATE public.confirmation_send SET token = encode(sha256(token::bytea), 'hex') WHERE length(token) <> 64;
+ALTER TABLE public.confirmation_send ADD CONSTRAINT confirmation_send_token_fkey
+    FOREIGN KEY (token) REFERENCES confirmation(token) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS confirmation_created_at ON public.confirmation (created_at);
+
+-- the school email that confirmed the student. An address can only confirm one student per school
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS school_email text;
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS school_verified_at timestamp;
+CREATE UNIQUE INDEX IF NOT EXISTS student_school_email ON public.student (school, lower(school_email))
+    WHERE school_email IS NOT NULL;
+
+-- codes students can type in the app instead of opening the link
+ALTER TABLE public.confirmation ADD COLUMN IF NOT EXISTS code_hash text NOT NULL DEFAULT '';
+ALTER TABLE public.confirmation ADD COLUMN IF NOT EXISTS code_attempts int NOT NULL DEFAULT 0;
+
+-- the schools each student confirmed. left_at is null for the current one
+CREATE TABLE IF NOT EXISTS public.school_affiliation (
+    st_id text NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+    sc_id text NOT NULL REFERENCES school(id),
+    joined_at timestamp NOT NULL,
+    left_at timestamp
+);
+CREATE INDEX IF NOT EXISTS school_affiliation_st_id ON public.school_affiliation (st_id, joined_at);
+CREATE UNIQUE INDEX IF NOT EXISTS school_affiliation_current ON public.school_affiliation (st_id)
+    WHERE left_at IS NULL;
+INSERT INTO public.school_affiliation (st_id, sc_id, joined_at)
+SELECT id, school, coalesce(school_verified_at, now()) FROM public.student
+WHERE school IS NOT NULL AND NOT EXISTS (SELECT 1 FROM public.school_affiliation a WHERE a.st_id = student.id);
+
+-- the domains of the schools, one per row so emails can be matched on an index
+CREATE TABLE IF NOT EXISTS public.school_domain (
+    domain text NOT NULL,
+    sc_id text NOT NULL REFERENCES school(id) ON DELETE CASCADE,
+    PRIMARY KEY (domain, sc_id)
+);
+INSERT INTO public.school_domain (domain, sc_id)
+SELECT DISTINCT lower(trim(trailing '.' from d)), id FROM public.school, unnest(domains) AS d
+ON CONFLICT DO NOTHING;
+
+-- school names are searched without case and accents, and with typos
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+-- unaccent can't be used in an index since it isn't immutable
+CREATE OR REPLACE FUNCTION public.f_unaccent(text) RETURNS text AS
+$$ SELECT public.unaccent('public.unaccent', $1) $$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+CREATE INDEX IF NOT EXISTS school_name_trgm ON public.school USING gin (public.f_unaccent(lower(name)) gin_trgm_ops);
+
+-- the campuses and the faculties of the schools, students can pick one of each in their school
+CREATE TABLE IF NOT EXISTS public.campus (
+    id text PRIMARY KEY,
+    school text NOT NULL REFERENCES school(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    UNIQUE (school, name)
+);
+CREATE TABLE IF NOT EXISTS public.faculty (
+    id text PRIMARY KEY,
+    school text NOT NULL REFERENCES school(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    UNIQUE (school, name)
+);
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS campus text REFERENCES campus(id) ON DELETE SET NULL;
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS faculty text REFERENCES faculty(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS student_campus ON public.student (campus);
+CREATE INDEX IF NOT EXISTS student_faculty ON public.student (faculty);
@@ -5,7 +5,11 @@ import (
 	"github.com/airbenders/profile/domain"
 	"github.com/airbenders/profile/utils/errors"
 	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"log"
+	"strings"
+	"time"
 )
 
 type schoolRepository struct {
@@ -20,19 +24,82 @@ func NewSchoolRepository(db pgxpoolmock.PgxPool) domain.SchoolRepository {
 }
 
 const (
-	findByDomain = `SELECT s.id, s.name, s.country FROM (select id, name, country, unnest(domains) as domain from 
-	school) as s WHERE s.domain SIMILAR TO ($1)`
+	// a school matching several of the domains is found once, with the most specific one
+	findByDomain = `SELECT id, name, country, domains, domain FROM (
+		SELECT DISTINCT ON (s.id) s.id, s.name, s.country, s.domains, d.domain,
+		array_position($1::text[], d.domain) AS position
+		FROM public.school_domain d JOIN public.school s ON s.id = d.sc_id WHERE d.domain = ANY($1::text[])
+		ORDER BY s.id, array_position($1::text[], d.domain)) found
+	ORDER BY position, name`
+	// names are compared without case and accents. Names starting with the query come first, then names with a word
+	// starting with it, then names only close to it
+	findByName = `WITH q AS (SELECT public.f_unaccent(lower($1)) AS text, public.f_unaccent(lower($2)) AS pattern)
+	SELECT s.id, s.name, s.country, s.domains FROM public.school s, q
+	WHERE (public.f_unaccent(lower(s.name)) LIKE q.pattern || '%'
+		OR public.f_unaccent(lower(s.name)) LIKE '% ' || q.pattern || '%'
+		OR q.text <% public.f_unaccent(lower(s.name)))
+	AND ($3 = '' OR lower(s.country) = lower($3))
+	ORDER BY CASE WHEN public.f_unaccent(lower(s.name)) LIKE q.pattern || '%' THEN 0
+		WHEN public.f_unaccent(lower(s.name)) LIKE '% ' || q.pattern || '%' THEN 1 ELSE 2 END,
+		word_similarity(q.text, public.f_unaccent(lower(s.name))) DESC, s.name
+	LIMIT $4 OFFSET $5`
 	insertConfirmation = `INSERT INTO public.confirmation(
-	token, sc_id, st_id, created_at)
+	token, email, sc_id, st_id, created_at, code_hash)
+	VALUES ($1, $2, $3, $4, $5, $6);`
+	invalidateConfirmations = `UPDATE public.confirmation SET consumed_at=$2 WHERE st_id=$1 AND consumed_at IS NULL;`
+	getConfirmationByToken  = `SELECT token, email, sc_id, st_id, created_at, consumed_at, code_hash, code_attempts
+	FROM confirmation WHERE token=$1`
+	selectPendingConfirmations = `SELECT c.token, c.email, c.sc_id, sc.name, c.st_id, c.created_at, c.code_hash,
+	c.code_attempts FROM public.confirmation c JOIN public.school sc ON sc.id = c.sc_id
+	WHERE c.st_id=$1 AND c.created_at > $2 AND c.consumed_at IS NULL ORDER BY c.created_at DESC;`
+	consumeConfirmation = `UPDATE public.confirmation SET consumed_at=$2
+	WHERE token=$1 AND consumed_at IS NULL AND created_at > $3;`
+	addCodeAttempt = `UPDATE public.confirmation SET code_attempts = code_attempts + 1
+	WHERE token=$1 AND consumed_at IS NULL AND code_attempts < $2;`
+	deleteExpiredConfirmations = `DELETE FROM public.confirmation WHERE created_at < $1;`
+	// the sends of a student and of an address are locked until the transaction ends, so they are counted and
+	// recorded by one request at a time. The student is always locked first
+	lockConfirmationSends = `SELECT pg_advisory_xact_lock(hashtext('confirmation_send st_id ' || $1)),
+	pg_advisory_xact_lock(hashtext('confirmation_send email ' || $2));`
+	insertConfirmationSend = `INSERT INTO public.confirmation_send (token, st_id, email, sent_at)
 	VALUES ($1, $2, $3, $4);`
-	getConfirmationByToken  = `SELECT token, sc_id, st_id, created_at FROM confirmation WHERE token=$1`
+	selectConfirmationSends = `SELECT token, st_id, email, sent_at FROM public.confirmation_send
+	WHERE (st_id=$1 OR email=$2) AND sent_at > $3 ORDER BY sent_at;`
+	// the campus and the faculty only stay when the student confirms the same school again
 	updateStudentWithSchool = `UPDATE public.student
-	SET school=$1 WHERE id=$2;`
+	SET campus = CASE WHEN school = $1 THEN campus END, faculty = CASE WHEN school = $1 THEN faculty END,
+	school=$1, school_email=$3, school_verified_at=$4 WHERE id=$2;`
+	closeSchoolAffiliation = `UPDATE public.school_affiliation SET left_at=$3
+	WHERE st_id=$1 AND sc_id <> $2 AND left_at IS NULL;`
+	insertSchoolAffiliation = `INSERT INTO public.school_affiliation (st_id, sc_id, joined_at) SELECT $1, $2, $3
+	WHERE NOT EXISTS (SELECT 1 FROM public.school_affiliation WHERE st_id=$1 AND sc_id=$2 AND left_at IS NULL);`
+	removeStudentSchool = `UPDATE public.student SET school=NULL, school_email=NULL, school_verified_at=NULL,
+	campus=NULL, faculty=NULL WHERE id=$1 AND school IS NOT NULL;`
+	leaveSchoolAffiliation = `UPDATE public.school_affiliation SET left_at=$2 WHERE st_id=$1 AND left_at IS NULL;`
+	selectSchoolHistory    = `SELECT sc.id, sc.name, sc.country, a.joined_at, a.left_at FROM public.school_affiliation a
+	JOIN public.school sc ON sc.id = a.sc_id WHERE a.st_id=$1 ORDER BY a.joined_at DESC;`
+	selectSchoolEmailTaken = `SELECT EXISTS (SELECT 1 FROM public.student
+	WHERE school=$1 AND lower(school_email)=lower($2) AND id <> $3);`
+	selectEmailSuppression = `SELECT email, reason, diagnostic, created_at FROM public.mail_suppression
+	WHERE email=lower($1);`
+	selectCampuses    = `SELECT id, school, name FROM public.campus WHERE school=$1 ORDER BY name;`
+	selectFaculties   = `SELECT id, school, name FROM public.faculty WHERE school=$1 ORDER BY name;`
+	updateSchoolUnits = `UPDATE public.student SET campus=NULLIF($3, ''), faculty=NULLIF($4, '')
+	WHERE id=$1 AND school=$2;`
+	searchCourses = `SELECT id, school, subject, number, title, term FROM course WHERE school=$1
+	AND (subject || ' ' || number LIKE $2 || '%' OR title ILIKE '%' || $3 || '%')
+	ORDER BY subject, number, term LIMIT 50`
 )
 
-// SearchByDomain finds the schools matching the domain name pattern. Otherwise, returns an empty slice
-func (r *schoolRepository) SearchByDomain(ctx context.Context, domainName string) ([]domain.School, error) {
-	rows, err := r.db.Query(ctx, findByDomain, domainName)
+// uniqueViolation is the Postgres error code of a duplicate key
+const uniqueViolation = "23505"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchByDomain finds the schools with exactly one of the domains, in the order of the domains. The domain a school
+// matched is in MatchedDomain. Otherwise, returns an empty slice
+func (r *schoolRepository) SearchByDomain(ctx context.Context, domains []string) ([]domain.School, error) {
+	rows, err := r.db.Query(ctx, findByDomain, domains)
 	if err != nil {
 		err = errors.NewInternalServerError(err.Error())
 		return nil, err
@@ -42,7 +109,7 @@ func (r *schoolRepository) SearchByDomain(ctx context.Context, domainName string
 	var schools []domain.School
 	for rows.Next() {
 		var school domain.School
-		err = rows.Scan(&school.ID, &school.Name, &school.Country)
+		err = rows.Scan(&school.ID, &school.Name, &school.Country, &school.Domains, &school.MatchedDomain)
 		if err != nil {
 			err = errors.NewInternalServerError(err.Error())
 			return nil, err
@@ -54,15 +121,61 @@ func (r *schoolRepository) SearchByDomain(ctx context.Context, domainName string
 	return schools, nil
 }
 
-// SaveConfirmationToken saves the token which will be used to confirm student's school. Returns nil if can't save
-func (r *schoolRepository) SaveConfirmationToken(ctx context.Context, confirmation *domain.Confirmation) error {
+// SearchByName finds the schools whose name starts with, contains a word starting with, or is close to the name, the
+// best matches first. country filters the schools when it isn't empty. Returns an empty slice if nothing matches
+func (r *schoolRepository) SearchByName(ctx context.Context, name string, country string, page domain.Page) ([]domain.School, error) {
+	rows, err := r.db.Query(ctx, findByName, name, likeEscaper.Replace(name), country, page.Size, page.Offset())
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	schools := []domain.School{}
+	for rows.Next() {
+		var school domain.School
+		err = rows.Scan(&school.ID, &school.Name, &school.Country, &school.Domains)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		schools = append(schools, school)
+	}
+	return schools, nil
+}
+
+// SaveConfirmationToken stores the confirmation and records its email as sent, in one transaction. The previous
+// confirmations of the student stop working. The emails sent after since by the student or to the address are locked
+// and given to checkSends first, and nothing is saved if it returns an error, so parallel requests can't go past the
+// limits
+func (r *schoolRepository) SaveConfirmationToken(ctx context.Context, confirmation *domain.Confirmation, since time.Time, checkSends func([]domain.ConfirmationSend) error) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
 	}
 	defer tx.Rollback(ctx)
 
-	_, err = tx.Exec(ctx, insertConfirmation, confirmation.Token, confirmation.School.ID, confirmation.Student.ID, confirmation.CreatedAt)
+	_, err = tx.Exec(ctx, lockConfirmationSends, confirmation.Student.ID, confirmation.Email)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	sends, err := getConfirmationSends(ctx, tx, confirmation.Student.ID, confirmation.Email, since)
+	if err != nil {
+		return err
+	}
+	if err = checkSends(sends); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, invalidateConfirmations, confirmation.Student.ID, confirmation.CreatedAt)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	_, err = tx.Exec(ctx, insertConfirmation, confirmation.TokenHash, confirmation.Email, confirmation.School.ID,
+		confirmation.Student.ID, confirmation.CreatedAt, confirmation.CodeHash)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	_, err = tx.Exec(ctx, insertConfirmationSend, confirmation.TokenHash, confirmation.Student.ID, confirmation.Email,
+		confirmation.CreatedAt)
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
 	}
@@ -76,10 +189,10 @@ func (r *schoolRepository) SaveConfirmationToken(ctx context.Context, confirmati
 	return nil
 }
 
-// GetConfirmationByToken returns a Confirmation with verifiable token and the student and school info.
+// GetConfirmationByToken returns the Confirmation of the token hash with the student and school info.
 // Return empty confirmation if no such record found
-func (r *schoolRepository) GetConfirmationByToken(ctx context.Context, token string) (*domain.Confirmation, error) {
-	rows, err := r.db.Query(ctx, getConfirmationByToken, token)
+func (r *schoolRepository) GetConfirmationByToken(ctx context.Context, tokenHash string) (*domain.Confirmation, error) {
+	rows, err := r.db.Query(ctx, getConfirmationByToken, tokenHash)
 	if err != nil {
 		err = errors.NewInternalServerError(err.Error())
 		return nil, err
@@ -88,7 +201,9 @@ func (r *schoolRepository) GetConfirmationByToken(ctx context.Context, token str
 
 	var confirmation domain.Confirmation
 	for rows.Next() {
-		err = rows.Scan(&confirmation.Token, &confirmation.School.ID, &confirmation.Student.ID, &confirmation.CreatedAt)
+		err = rows.Scan(&confirmation.TokenHash, &confirmation.Email, &confirmation.School.ID,
+			&confirmation.Student.ID, &confirmation.CreatedAt, &confirmation.ConsumedAt, &confirmation.CodeHash,
+			&confirmation.CodeAttempts)
 		if err != nil {
 			err = errors.NewInternalServerError(err.Error())
 			return nil, err
@@ -98,15 +213,138 @@ func (r *schoolRepository) GetConfirmationByToken(ctx context.Context, token str
 	return &confirmation, nil
 }
 
-// AddSchoolForStudent is invoked when the student's school is confirmed. Student table is altered to store the school
-// ID now
-func (r *schoolRepository) AddSchoolForStudent(ctx context.Context, stID string, scID string) error {
+// SchoolEmailTaken is true if a student other than studentID verified the email for the school
+func (r *schoolRepository) SchoolEmailTaken(ctx context.Context, schoolID string, email string, studentID string) (bool, error) {
+	rows, err := r.db.Query(ctx, selectSchoolEmailTaken, schoolID, email, studentID)
+	if err != nil {
+		return false, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	taken := false
+	for rows.Next() {
+		if err = rows.Scan(&taken); err != nil {
+			return false, errors.NewInternalServerError(err.Error())
+		}
+	}
+	return taken, nil
+}
+
+// GetEmailSuppression returns the suppression of the address, or nil if emails can be sent to it
+func (r *schoolRepository) GetEmailSuppression(ctx context.Context, email string) (*domain.MailSuppression, error) {
+	rows, err := r.db.Query(ctx, selectEmailSuppression, email)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	var suppression *domain.MailSuppression
+	for rows.Next() {
+		var reason string
+		suppression = &domain.MailSuppression{}
+		err = rows.Scan(&suppression.Email, &reason, &suppression.Diagnostic, &suppression.CreatedAt)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		suppression.Reason = domain.MailEventType(reason)
+	}
+	return suppression, nil
+}
+
+// GetPendingConfirmations returns the confirmations of the student created after since, newest first, with the name
+// of their school
+func (r *schoolRepository) GetPendingConfirmations(ctx context.Context, studentID string, since time.Time) ([]domain.Confirmation, error) {
+	rows, err := r.db.Query(ctx, selectPendingConfirmations, studentID, since)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	confirmations := []domain.Confirmation{}
+	for rows.Next() {
+		var confirmation domain.Confirmation
+		err = rows.Scan(&confirmation.TokenHash, &confirmation.Email, &confirmation.School.ID, &confirmation.School.Name,
+			&confirmation.Student.ID, &confirmation.CreatedAt, &confirmation.CodeHash, &confirmation.CodeAttempts)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		confirmations = append(confirmations, confirmation)
+	}
+	return confirmations, nil
+}
+
+// getConfirmationSends returns the confirmation emails sent after since by the student or to the email address,
+// oldest first
+func getConfirmationSends(ctx context.Context, tx pgx.Tx, studentID string, email string, since time.Time) ([]domain.ConfirmationSend, error) {
+	rows, err := tx.Query(ctx, selectConfirmationSends, studentID, email, since)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	sends := []domain.ConfirmationSend{}
+	for rows.Next() {
+		var send domain.ConfirmationSend
+		err = rows.Scan(&send.TokenHash, &send.StudentID, &send.Email, &send.SentAt)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		sends = append(sends, send)
+	}
+	return sends, nil
+}
+
+// AddCodeAttempt counts a code tried for the confirmation. Returns false without counting it if the confirmation was
+// used or has no attempts left, so codes can't be tried in parallel past the limit
+func (r *schoolRepository) AddCodeAttempt(ctx context.Context, tokenHash string) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, addCodeAttempt, tokenHash, domain.MaxConfirmationCodeAttempts)
+	if err != nil {
+		return false, errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, errors.NewInternalServerError(err.Error())
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ConsumeConfirmation uses the token of the confirmation and stores the school and the verified email for the student,
+// in one transaction. A new school ends the affiliation with the previous one in the history. Returns a 400 if the
+// token was used or expired in the meantime, and a 409 if another student of the school verified the email first
+func (r *schoolRepository) ConsumeConfirmation(ctx context.Context, confirmation *domain.Confirmation, at time.Time) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
 	}
 	defer tx.Rollback(ctx)
-	_, err = tx.Exec(ctx, updateStudentWithSchool, scID, stID)
+
+	tag, err := tx.Exec(ctx, consumeConfirmation, confirmation.TokenHash, at, at.Add(-domain.ConfirmationTTL))
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NewBadRequestError("token already used or expired")
+	}
+	_, err = tx.Exec(ctx, updateStudentWithSchool, confirmation.School.ID, confirmation.Student.ID,
+		confirmation.Email, at)
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == uniqueViolation {
+		return errors.NewConflictError("this email already confirmed another student of the school")
+	}
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	_, err = tx.Exec(ctx, closeSchoolAffiliation, confirmation.Student.ID, confirmation.School.ID, at)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	_, err = tx.Exec(ctx, insertSchoolAffiliation, confirmation.Student.ID, confirmation.School.ID, at)
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
 	}
@@ -117,3 +355,167 @@ func (r *schoolRepository) AddSchoolForStudent(ctx context.Context, stID string,
 	}
 	return nil
 }
+
+// RemoveSchool removes the school and the verified email of the student, and ends the affiliation in the history.
+// Returns a 400 if the student has no school
+func (r *schoolRepository) RemoveSchool(ctx context.Context, studentID string, at time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, removeStudentSchool, studentID)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NewBadRequestError("no confirmed school to leave")
+	}
+	_, err = tx.Exec(ctx, leaveSchoolAffiliation, studentID, at)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// GetSchoolHistory returns the schools the student confirmed, newest first. Returns an empty slice if there are none
+func (r *schoolRepository) GetSchoolHistory(ctx context.Context, studentID string) ([]domain.SchoolAffiliation, error) {
+	rows, err := r.db.Query(ctx, selectSchoolHistory, studentID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	history := []domain.SchoolAffiliation{}
+	for rows.Next() {
+		var affiliation domain.SchoolAffiliation
+		err = rows.Scan(&affiliation.School.ID, &affiliation.School.Name, &affiliation.School.Country,
+			&affiliation.JoinedAt, &affiliation.LeftAt)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		history = append(history, affiliation)
+	}
+	return history, nil
+}
+
+// GetCampuses returns the campuses of the school by name. Returns an empty slice if it has none
+func (r *schoolRepository) GetCampuses(ctx context.Context, schoolID string) ([]domain.Campus, error) {
+	rows, err := r.db.Query(ctx, selectCampuses, schoolID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	campuses := []domain.Campus{}
+	for rows.Next() {
+		var campus domain.Campus
+		err = rows.Scan(&campus.ID, &campus.SchoolID, &campus.Name)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		campuses = append(campuses, campus)
+	}
+	return campuses, nil
+}
+
+// GetFaculties returns the faculties of the school by name. Returns an empty slice if it has none
+func (r *schoolRepository) GetFaculties(ctx context.Context, schoolID string) ([]domain.Faculty, error) {
+	rows, err := r.db.Query(ctx, selectFaculties, schoolID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	faculties := []domain.Faculty{}
+	for rows.Next() {
+		var faculty domain.Faculty
+		err = rows.Scan(&faculty.ID, &faculty.SchoolID, &faculty.Name)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		faculties = append(faculties, faculty)
+	}
+	return faculties, nil
+}
+
+// UpdateSchoolUnits sets the campus and the faculty of the student. Returns a 400 if the student is no longer in the
+// school
+func (r *schoolRepository) UpdateSchoolUnits(ctx context.Context, studentID string, schoolID string, units domain.SchoolUnits) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, updateSchoolUnits, studentID, schoolID, units.CampusID, units.FacultyID)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.NewBadRequestError("the student is not in this school")
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// DeleteExpiredConfirmations deletes the confirmations created before the given time, with their sends. Returns how
+// many were deleted
+func (r *schoolRepository) DeleteExpiredConfirmations(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, deleteExpiredConfirmations, before)
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+	return tag.RowsAffected(), nil
+}
+
+// SearchCourses returns the courses of the school whose normalized code starts with codePrefix or whose title
+// contains title. Returns an empty slice if nothing matches
+func (r *schoolRepository) SearchCourses(ctx context.Context, schoolID string, codePrefix string, title string) ([]domain.Course, error) {
+	rows, err := r.db.Query(ctx, searchCourses, schoolID, likeEscaper.Replace(codePrefix), likeEscaper.Replace(title))
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	courses := []domain.Course{}
+	for rows.Next() {
+		var course domain.Course
+		// title and term are nullable, the course is listed without them
+		var title, term *string
+		err = rows.Scan(&course.ID, &course.SchoolID, &course.Subject, &course.Number, &title, &term)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		if title != nil {
+			course.Title = *title
+		}
+		if term != nil {
+			course.Term = *term
+		}
+		courses = append(courses, course)
+	}
+
+	return courses, nil
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"github.com/airbenders/profile/School/repository"
 	"github.com/airbenders/profile/domain"
+	e "github.com/airbenders/profile/utils/errors"
 	"github.com/airbenders/profile/utils/pgxmocks"
 	"github.com/driftprogramming/pgxpoolmock"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -21,13 +23,18 @@ func TestGetConfirmationByToken(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
-	columns := []string{"token", "sc_id", "st_id", "created_at"}
-	expectedToken := domain.Confirmation{Token: "123", School: domain.School{ID: "abc"}, Student: domain.Student{ID: "def"}, CreatedAt: time.Now()}
+	columns := []string{"token", "email", "sc_id", "st_id", "created_at", "consumed_at", "code_hash", "code_attempts"}
+	consumedAt := time.Now()
+	expectedToken := domain.Confirmation{TokenHash: "123", Email: "a@concordia.ca", School: domain.School{ID: "abc"}, Student: domain.Student{ID: "def"}, CreatedAt: time.Now(), ConsumedAt: &consumedAt, CodeHash: "456", CodeAttempts: 2}
 	pgxRows := pgxpoolmock.NewRows(columns).AddRow(
-		expectedToken.Token,
+		expectedToken.TokenHash,
+		expectedToken.Email,
 		expectedToken.School.ID,
 		expectedToken.Student.ID,
-		expectedToken.CreatedAt).ToPgxRows()
+		expectedToken.CreatedAt,
+		expectedToken.ConsumedAt,
+		expectedToken.CodeHash,
+		expectedToken.CodeAttempts).ToPgxRows()
 
 	t.Run("success", func(t *testing.T) {
 		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf("string")).Return(pgxRows, nil)
@@ -49,23 +56,131 @@ func TestGetConfirmationByToken(t *testing.T) {
 	})
 }
 
+func TestSchoolEmailTaken(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		pgxRows := pgxpoolmock.NewRows([]string{"exists"}).AddRow(true).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "sc", "a@concordia.ca", "st").Return(pgxRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		taken, err := sr.SchoolEmailTaken(context.Background(), "sc", "a@concordia.ca", "st")
+
+		assert.NoError(t, err)
+		assert.True(t, taken)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "sc", "a@concordia.ca", "st").Return(nil, errors.New("err"))
+		sr := repository.NewSchoolRepository(mockPool)
+		taken, err := sr.SchoolEmailTaken(context.Background(), "sc", "a@concordia.ca", "st")
+
+		assert.Error(t, err)
+		assert.False(t, taken)
+	})
+}
+
+func TestGetEmailSuppression(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"email", "reason", "diagnostic", "created_at"}
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Now()
+		expected := &domain.MailSuppression{Email: "a@concordia.ca", Reason: domain.MailBounce,
+			Diagnostic: "550 5.1.1 user unknown", CreatedAt: now}
+		pgxRows := pgxpoolmock.NewRows(columns).AddRow("a@concordia.ca", "bounce", "550 5.1.1 user unknown", now).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "A@Concordia.ca").Return(pgxRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		suppression, err := sr.GetEmailSuppression(context.Background(), "A@Concordia.ca")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, suppression)
+	})
+
+	t.Run("not-suppressed", func(t *testing.T) {
+		pgxRows := pgxpoolmock.NewRows(columns).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a@concordia.ca").Return(pgxRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		suppression, err := sr.GetEmailSuppression(context.Background(), "a@concordia.ca")
+
+		assert.NoError(t, err)
+		assert.Nil(t, suppression)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a@concordia.ca").Return(nil, errors.New("err"))
+		sr := repository.NewSchoolRepository(mockPool)
+		suppression, err := sr.GetEmailSuppression(context.Background(), "a@concordia.ca")
+
+		assert.Error(t, err)
+		assert.Nil(t, suppression)
+	})
+}
+
+func TestGetPendingConfirmations(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"token", "email", "sc_id", "name", "st_id", "created_at", "code_hash", "code_attempts"}
+	since := time.Now().Add(-domain.ConfirmationTTL)
+
+	t.Run("success", func(t *testing.T) {
+		expected := []domain.Confirmation{{TokenHash: "123", Email: "a@concordia.ca",
+			School: domain.School{ID: "abc", Name: "Concordia University"}, Student: domain.Student{ID: "def"},
+			CreatedAt: time.Now(), CodeHash: "456", CodeAttempts: 1}}
+		pgxRows := pgxpoolmock.NewRows(columns).AddRow(expected[0].TokenHash, expected[0].Email, expected[0].School.ID,
+			expected[0].School.Name, expected[0].Student.ID, expected[0].CreatedAt, expected[0].CodeHash,
+			expected[0].CodeAttempts).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "def", since).Return(pgxRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		confirmations, err := sr.GetPendingConfirmations(context.Background(), "def", since)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, confirmations)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "def", since).Return(nil, errors.New("err"))
+		sr := repository.NewSchoolRepository(mockPool)
+		confirmations, err := sr.GetPendingConfirmations(context.Background(), "def", since)
+
+		assert.Error(t, err)
+		assert.Nil(t, confirmations)
+	})
+}
+
 func TestSearchByDomain(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
-	columns := []string{"s.id", "s.name", "s.country"}
-	schools := []domain.School{{"a", "b", "c", nil}, {"d", "e", "f", nil}}
+	columns := []string{"s.id", "s.name", "s.country", "s.domains", "d.domain"}
+	schools := []domain.School{
+		{ID: "a", Name: "b", Country: "c", Domains: []string{"concordia.ca", "live.concordia.ca"},
+			MatchedDomain: "live.concordia.ca"},
+		{ID: "d", Name: "e", Country: "f", Domains: []string{"concordia.ca"}, MatchedDomain: "concordia.ca"},
+	}
 	pgRows := pgxpoolmock.NewRows(columns).
-		AddRow(schools[0].ID, schools[0].Name, schools[0].Country).
-		AddRow(schools[1].ID, schools[1].Name, schools[1].Country).
+		AddRow(schools[0].ID, schools[0].Name, schools[0].Country, schools[0].Domains, schools[0].MatchedDomain).
+		AddRow(schools[1].ID, schools[1].Name, schools[1].Country, schools[1].Domains, schools[1].MatchedDomain).
 		ToPgxRows()
+	domains := []string{"live.concordia.ca", "concordia.ca"}
 
 	t.Run("success", func(t *testing.T) {
-		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).Return(pgRows, nil)
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), domains).Return(pgRows, nil)
 		sr := repository.NewSchoolRepository(mockPool)
-		returnedSchools, err := sr.SearchByDomain(context.Background(), "sth")
+		returnedSchools, err := sr.SearchByDomain(context.Background(), domains)
 
 		assert.NoError(t, err)
 		assert.EqualValues(t, schools, returnedSchools)
@@ -74,13 +189,48 @@ func TestSearchByDomain(t *testing.T) {
 	t.Run("failure", func(t *testing.T) {
 		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("some error"))
 		sr := repository.NewSchoolRepository(mockPool)
-		returnedSchools, err := sr.SearchByDomain(context.Background(), "sth")
+		returnedSchools, err := sr.SearchByDomain(context.Background(), domains)
 
 		assert.Error(t, err)
 		assert.Nil(t, returnedSchools)
 	})
 }
 
+func TestSearchByName(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"id", "name", "country", "domains"}
+	page := domain.Page{Number: 2, Size: 10}
+
+	t.Run("success", func(t *testing.T) {
+		schools := []domain.School{{ID: "a", Name: "Cégep de Saint-Jérôme", Country: "Canada",
+			Domains: []string{"cstj.qc.ca"}}}
+		pgRows := pgxpoolmock.NewRows(columns).
+			AddRow(schools[0].ID, schools[0].Name, schools[0].Country, schools[0].Domains).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "cegep 100%", `cegep 100\%`, "Canada", 10, 10).
+			Return(pgRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		returned, err := sr.SearchByName(context.Background(), "cegep 100%", "Canada", page)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, schools, returned)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).Return(nil, errors.New("some error"))
+		sr := repository.NewSchoolRepository(mockPool)
+		returned, err := sr.SearchByName(context.Background(), "cegep", "", page)
+
+		assert.Error(t, err)
+		assert.Nil(t, returned)
+	})
+}
+
 func TestSaveConfirmationToken(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
@@ -88,18 +238,52 @@ func TestSaveConfirmationToken(t *testing.T) {
 
 	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
 	txMock := new(pgxmocks.TxMock)
+	since := time.Now().Add(-time.Hour)
+	confirmation := &domain.Confirmation{TokenHash: "123", Email: "a@concordia.ca", Student: domain.Student{ID: "def"}}
+	sendRows := func() pgx.Rows {
+		return pgxpoolmock.NewRows([]string{"token", "st_id", "email", "sent_at"}).
+			AddRow("456", "def", "a@concordia.ca", since.Add(time.Minute)).ToPgxRows()
+	}
+	noLimits := func([]domain.ConfirmationSend) error { return nil }
 
 	t.Run("success", func(t *testing.T) {
+		var checked []domain.ConfirmationSend
 		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
-		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"def", "a@concordia.ca"}).
 			Return(pgconn.CommandTag{}, nil).Once()
+		txMock.On("Query", mock.Anything, mock.Anything, []interface{}{"def", "a@concordia.ca", since}).
+			Return(sendRows(), nil).Once()
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, nil).Times(3)
 		txMock.On("Rollback", mock.Anything).Return(nil).Once()
 		txMock.On("Commit", mock.Anything).Return(nil).Once()
 
 		sr := repository.NewSchoolRepository(mockPool)
-		err := sr.SaveConfirmationToken(context.Background(), &domain.Confirmation{})
+		err := sr.SaveConfirmationToken(context.Background(), confirmation, since, func(sends []domain.ConfirmationSend) error {
+			checked = sends
+			return nil
+		})
 
 		assert.NoError(t, err)
+		assert.Equal(t, []domain.ConfirmationSend{{TokenHash: "456", StudentID: "def", Email: "a@concordia.ca",
+			SentAt: since.Add(time.Minute)}}, checked)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("limits-reached", func(t *testing.T) {
+		limitErr := errors.New("too many")
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"def", "a@concordia.ca"}).
+			Return(pgconn.CommandTag{}, nil).Once()
+		txMock.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(sendRows(), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		err := sr.SaveConfirmationToken(context.Background(), confirmation, since, func([]domain.ConfirmationSend) error {
+			return limitErr
+		})
+
+		assert.Equal(t, limitErr, err)
 		txMock.AssertExpectations(t)
 	})
 
@@ -107,7 +291,7 @@ func TestSaveConfirmationToken(t *testing.T) {
 		mockPool.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("err"))
 
 		sr := repository.NewSchoolRepository(mockPool)
-		err := sr.SaveConfirmationToken(context.Background(), &domain.Confirmation{})
+		err := sr.SaveConfirmationToken(context.Background(), confirmation, since, noLimits)
 
 		assert.Error(t, err)
 	})
@@ -119,7 +303,7 @@ func TestSaveConfirmationToken(t *testing.T) {
 		txMock.On("Rollback", mock.Anything).Return(nil).Once()
 
 		sr := repository.NewSchoolRepository(mockPool)
-		err := sr.SaveConfirmationToken(context.Background(), &domain.Confirmation{})
+		err := sr.SaveConfirmationToken(context.Background(), confirmation, since, noLimits)
 
 		assert.Error(t, err)
 		txMock.AssertExpectations(t)
@@ -128,45 +312,85 @@ func TestSaveConfirmationToken(t *testing.T) {
 	t.Run("can't commit transaction", func(t *testing.T) {
 		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
 		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
-			Return(pgconn.CommandTag{}, nil).Once()
+			Return(pgconn.CommandTag{}, nil).Times(4)
+		txMock.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(sendRows(), nil).Once()
 		txMock.On("Commit", mock.Anything).Return(errors.New("err")).Once()
 		txMock.On("Rollback", mock.Anything).Return(nil).Once()
 
 		sr := repository.NewSchoolRepository(mockPool)
-		err := sr.SaveConfirmationToken(context.Background(), &domain.Confirmation{})
+		err := sr.SaveConfirmationToken(context.Background(), confirmation, since, noLimits)
 
 		assert.Error(t, err)
 		txMock.AssertExpectations(t)
 	})
 }
 
-func TestAddSchoolForStudent(t *testing.T) {
+func TestConsumeConfirmation(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
 	txMock := new(pgxmocks.TxMock)
+	confirmation := &domain.Confirmation{TokenHash: "123", Email: "a@concordia.ca", School: domain.School{ID: "sc"},
+		Student: domain.Student{ID: "st"}}
+	now := time.Now()
 
 	t.Run("success", func(t *testing.T) {
 		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
-		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
-			Return(pgconn.CommandTag{}, nil).Once()
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"123", now, now.Add(-domain.ConfirmationTTL)}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"sc", "st", "a@concordia.ca", now}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"st", "sc", now}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"st", "sc", now}).
+			Return(pgconn.CommandTag("INSERT 0 1"), nil).Once()
 		txMock.On("Rollback", mock.Anything).Return(nil).Once()
 		txMock.On("Commit", mock.Anything).Return(nil).Once()
 
 		sr := repository.NewSchoolRepository(mockPool)
-		err := sr.AddSchoolForStudent(context.Background(), "a", "b")
+		err := sr.ConsumeConfirmation(context.Background(), confirmation, now)
 
 		assert.NoError(t, err)
 		txMock.AssertExpectations(t)
 	})
 
+	t.Run("already-consumed", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag("UPDATE 0"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		err := sr.ConsumeConfirmation(context.Background(), confirmation, now)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("email-taken", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"123", now, now.Add(-domain.ConfirmationTTL)}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"sc", "st", "a@concordia.ca", now}).
+			Return(nil, &pgconn.PgError{Code: "23505"}).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		err := sr.ConsumeConfirmation(context.Background(), confirmation, now)
+
+		assert.Error(t, err)
+		assert.Equal(t, 409, err.(*e.RestError).Code)
+		txMock.AssertExpectations(t)
+	})
+
 	t.Run("can't begin transaction", func(t *testing.T) {
 		mockPool.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("err"))
 
 		sr := repository.NewSchoolRepository(mockPool)
-		err := sr.AddSchoolForStudent(context.Background(), "a", "b")
+		err := sr.ConsumeConfirmation(context.Background(), confirmation, now)
 
 		assert.Error(t, err)
 	})
@@ -178,7 +402,7 @@ func TestAddSchoolForStudent(t *testing.T) {
 		txMock.On("Rollback", mock.Anything).Return(nil).Once()
 
 		sr := repository.NewSchoolRepository(mockPool)
-		err := sr.AddSchoolForStudent(context.Background(), "a", "b")
+		err := sr.ConsumeConfirmation(context.Background(), confirmation, now)
 
 		assert.Error(t, err)
 		txMock.AssertExpectations(t)
@@ -187,14 +411,336 @@ func TestAddSchoolForStudent(t *testing.T) {
 	t.Run("can't commit transaction", func(t *testing.T) {
 		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
 		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
-			Return(pgconn.CommandTag{}, nil).Once()
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Times(4)
 		txMock.On("Commit", mock.Anything).Return(errors.New("err")).Once()
 		txMock.On("Rollback", mock.Anything).Return(nil).Once()
 
 		sr := repository.NewSchoolRepository(mockPool)
-		err := sr.AddSchoolForStudent(context.Background(), "a", "b")
+		err := sr.ConsumeConfirmation(context.Background(), confirmation, now)
+
+		assert.Error(t, err)
+		txMock.AssertExpectations(t)
+	})
+}
+
+func TestRemoveSchool(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"st"}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"st", now}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		err := sr.RemoveSchool(context.Background(), "st", now)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("no-school", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"st"}).
+			Return(pgconn.CommandTag("UPDATE 0"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		err := sr.RemoveSchool(context.Background(), "st", now)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't exec transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("err")).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		err := sr.RemoveSchool(context.Background(), "st", now)
+
+		assert.Error(t, err)
+		txMock.AssertExpectations(t)
+	})
+}
+
+func TestGetSchoolHistory(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"id", "name", "country", "joined_at", "left_at"}
+
+	t.Run("success", func(t *testing.T) {
+		left := time.Now().Add(-time.Hour)
+		expected := []domain.SchoolAffiliation{
+			{School: domain.School{ID: "mcgill", Name: "McGill University", Country: "Canada"}, JoinedAt: left},
+			{School: domain.School{ID: "concordia", Name: "Concordia University", Country: "Canada"},
+				JoinedAt: left.Add(-time.Hour), LeftAt: &left},
+		}
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow(expected[0].School.ID, expected[0].School.Name, expected[0].School.Country, expected[0].JoinedAt,
+				expected[0].LeftAt).
+			AddRow(expected[1].School.ID, expected[1].School.Name, expected[1].School.Country, expected[1].JoinedAt,
+				expected[1].LeftAt).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "st").Return(pgxRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		history, err := sr.GetSchoolHistory(context.Background(), "st")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, history)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "st").Return(nil, errors.New("err"))
+		sr := repository.NewSchoolRepository(mockPool)
+		history, err := sr.GetSchoolHistory(context.Background(), "st")
+
+		assert.Error(t, err)
+		assert.Nil(t, history)
+	})
+}
+
+func TestGetSchoolUnits(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"id", "school", "name"}
+
+	t.Run("campuses", func(t *testing.T) {
+		expected := []domain.Campus{{ID: "loy", SchoolID: "sc", Name: "Loyola"},
+			{ID: "sgw", SchoolID: "sc", Name: "Sir George Williams"}}
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow(expected[0].ID, expected[0].SchoolID, expected[0].Name).
+			AddRow(expected[1].ID, expected[1].SchoolID, expected[1].Name).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "sc").Return(pgxRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		campuses, err := sr.GetCampuses(context.Background(), "sc")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, campuses)
+	})
+
+	t.Run("faculties", func(t *testing.T) {
+		expected := []domain.Faculty{{ID: "eng", SchoolID: "sc", Name: "Engineering and Computer Science"}}
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow(expected[0].ID, expected[0].SchoolID, expected[0].Name).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "sc").Return(pgxRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		faculties, err := sr.GetFaculties(context.Background(), "sc")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, faculties)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "sc").Return(nil, errors.New("err"))
+		sr := repository.NewSchoolRepository(mockPool)
+		campuses, err := sr.GetCampuses(context.Background(), "sc")
+
+		assert.Error(t, err)
+		assert.Nil(t, campuses)
+	})
+}
+
+func TestUpdateSchoolUnits(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+	units := domain.SchoolUnits{CampusID: "sgw"}
+
+	t.Run("success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"st", "sc", "sgw", ""}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		err := sr.UpdateSchoolUnits(context.Background(), "st", "sc", units)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("not-in-school", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"st", "sc", "sgw", ""}).
+			Return(pgconn.CommandTag("UPDATE 0"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		err := sr.UpdateSchoolUnits(context.Background(), "st", "sc", units)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't-begin-transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("err"))
+
+		sr := repository.NewSchoolRepository(mockPool)
+		err := sr.UpdateSchoolUnits(context.Background(), "st", "sc", units)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAddCodeAttempt(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+
+	t.Run("success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"123", domain.MaxConfirmationCodeAttempts}).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		ok, err := sr.AddCodeAttempt(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag("UPDATE 0"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		ok, err := sr.AddCodeAttempt(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't exec transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("err")).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		ok, err := sr.AddCodeAttempt(context.Background(), "123")
 
 		assert.Error(t, err)
+		assert.False(t, ok)
 		txMock.AssertExpectations(t)
 	})
 }
+
+func TestDeleteExpiredConfirmations(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+	before := time.Now().Add(-domain.ConfirmationTTL)
+
+	t.Run("success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{before}).
+			Return(pgconn.CommandTag("DELETE 4"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewSchoolRepository(mockPool)
+		count, err := sr.DeleteExpiredConfirmations(context.Background(), before)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 4, count)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't begin transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("err"))
+
+		sr := repository.NewSchoolRepository(mockPool)
+		_, err := sr.DeleteExpiredConfirmations(context.Background(), before)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchCourses(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"id", "school", "subject", "number", "title", "term"}
+	courses := []domain.Course{
+		{ID: "a", SchoolID: "sc", Subject: "COMP", Number: "352", Title: "Data Structures", Term: "Fall 2026"},
+		{ID: "b", SchoolID: "sc", Subject: "COMP", Number: "354", Title: "Software Engineering", Term: "Fall 2026"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		pgRows := pgxpoolmock.NewRows(columns).
+			AddRow(courses[0].ID, courses[0].SchoolID, courses[0].Subject, courses[0].Number, &courses[0].Title, &courses[0].Term).
+			AddRow(courses[1].ID, courses[1].SchoolID, courses[1].Subject, courses[1].Number, &courses[1].Title, &courses[1].Term).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "sc", `COMP 3\_`, `comp3\_`).Return(pgRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		returned, err := sr.SearchCourses(context.Background(), "sc", "COMP 3_", "comp3_")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, courses, returned)
+	})
+
+	t.Run("null-title-and-term", func(t *testing.T) {
+		pgRows := pgxpoolmock.NewRows(columns).
+			AddRow("c", "sc", "COMP", "490", (*string)(nil), (*string)(nil)).
+			AddRow(courses[0].ID, courses[0].SchoolID, courses[0].Subject, courses[0].Number, &courses[0].Title, &courses[0].Term).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "sc", "COMP", "comp").Return(pgRows, nil)
+		sr := repository.NewSchoolRepository(mockPool)
+		returned, err := sr.SearchCourses(context.Background(), "sc", "COMP", "comp")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, []domain.Course{{ID: "c", SchoolID: "sc", Subject: "COMP", Number: "490"}, courses[0]},
+			returned)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("some error"))
+		sr := repository.NewSchoolRepository(mockPool)
+		returned, err := sr.SearchCourses(context.Background(), "sc", "COMP", "comp")
+
+		assert.Error(t, err)
+		assert.Nil(t, returned)
+	})
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/streadway/amqp"
+	"log"
+	"reflect"
+	"time"
+)
+
+// consumeConfirmation confirms the school of the confirmation for its student. A school other than the student's
+// current one is sent to the other services as a school change
+func (s *schoolUseCase) consumeConfirmation(ctx context.Context, confirmation *domain.Confirmation, now time.Time) error {
+	student, err := s.str.GetByID(ctx, confirmation.Student.ID)
+	if err != nil {
+		return err
+	}
+	if err = s.r.ConsumeConfirmation(ctx, confirmation, now); err != nil {
+		return err
+	}
+
+	var previousSchoolID string
+	if student.School != nil {
+		previousSchoolID = student.School.ID
+	}
+	if previousSchoolID != confirmation.School.ID {
+		s.schoolChanged <- domain.SchoolChange{
+			StudentID:        confirmation.Student.ID,
+			PreviousSchoolID: previousSchoolID,
+			SchoolID:         confirmation.School.ID,
+			ChangedAt:        now,
+		}
+	}
+	return nil
+}
+
+// LeaveSchool removes the confirmed school of the student. It stays in their school history.
+// Returns a 400 if the student has no school
+func (s *schoolUseCase) LeaveSchool(c context.Context, studentID string) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	student, err := s.str.GetByID(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return errors.NewNotFoundError("student not found")
+	}
+	if student.School == nil {
+		return errors.NewBadRequestError("no confirmed school to leave")
+	}
+
+	now := time.Now()
+	if err = s.r.RemoveSchool(ctx, studentID, now); err != nil {
+		return err
+	}
+	s.schoolChanged <- domain.SchoolChange{
+		StudentID:        studentID,
+		PreviousSchoolID: student.School.ID,
+		ChangedAt:        now,
+	}
+	return nil
+}
+
+// GetSchoolHistory returns the schools the student confirmed, newest first
+func (s *schoolUseCase) GetSchoolHistory(c context.Context, studentID string) ([]domain.SchoolAffiliation, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	return s.r.GetSchoolHistory(ctx, studentID)
+}
+
+// SchoolChangedTopic sends messages for students confirming a new school or leaving theirs
+func (s *schoolUseCase) SchoolChangedTopic() {
+	for change := range s.schoolChanged {
+		body, err := json.Marshal(change)
+		if err != nil {
+			log.Println(err.Error())
+			continue
+		}
+		err = s.ch.Publish(
+			"profile",
+			"profile.school_changed",
+			false,
+			false,
+			amqp.Publishing{
+				ContentType: "text/plain",
+				Body:        body,
+			})
+		if err != nil {
+			log.Println("failed to publish ", err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"log"
+	"math"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// The limits on confirmation emails, so nobody can spam an address at a university
+const (
+	// resendCooldown is the least time between two emails asked by a student
+	resendCooldown = time.Minute
+	// confirmationWindow is the period over which emails are counted
+	confirmationWindow = time.Hour
+	// maxSendsPerStudent is the most emails a student can ask for in the window
+	maxSendsPerStudent = 5
+	// maxSendsPerEmail is the most emails an address can receive in the window, whoever asks for them
+	maxSendsPerEmail = 3
+)
+
+const lockedMessage = "too many wrong codes. Ask for a new confirmation email"
+
+// ResendConfirmation sends the latest pending confirmation of the student again. Only the hash of its token is kept,
+// so the email has a new token and the previous link stops working
+func (s *schoolUseCase) ResendConfirmation(c context.Context, studentID string, locale string) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	student, err := s.str.GetByID(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return errors.NewNotFoundError("student not found")
+	}
+	now := time.Now()
+	pending, err := s.r.GetPendingConfirmations(ctx, studentID, now.Add(-domain.ConfirmationTTL))
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return errors.NewNotFoundError("no pending confirmation to resend. Ask for a new one")
+	}
+	latest := pending[0]
+	if isConfirmed(student, latest.School.ID, now) {
+		return errors.NewBadRequestError("school already confirmed")
+	}
+	confirmation, err := s.newConfirmation(ctx, student, latest.Email, &latest.School, now)
+	if err != nil {
+		return err
+	}
+
+	return s.sendConfirmationEmail(ctx, student, confirmation, locale)
+}
+
+// ConfirmSchoolWithCode confirms the school of the student with the code of their latest confirmation email. Codes
+// expire sooner than links, and the confirmation is locked after too many wrong codes
+func (s *schoolUseCase) ConfirmSchoolWithCode(c context.Context, studentID string, code string) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	code = strings.TrimSpace(code)
+	if !isConfirmationCode(code) {
+		return errors.NewBadRequestError(fmt.Sprintf("the code has %d digits", domain.ConfirmationCodeLength))
+	}
+	now := time.Now()
+	pending, err := s.r.GetPendingConfirmations(ctx, studentID, now.Add(-domain.ConfirmationTTL))
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return errors.NewNotFoundError("no pending confirmation. Ask for a new one")
+	}
+	confirmation := &pending[0]
+	if confirmation.CodeAttempts >= domain.MaxConfirmationCodeAttempts {
+		return errors.NewForbiddenError(lockedMessage)
+	}
+	if confirmation.CreatedAt.Add(domain.ConfirmationCodeTTL).Before(now) {
+		return errors.NewBadRequestError("code already expired. Ask for a new one or use the link in the email")
+	}
+
+	ok, err := s.r.AddCodeAttempt(ctx, confirmation.TokenHash)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.NewForbiddenError(lockedMessage)
+	}
+	hash := domain.HashConfirmationCode(confirmation.TokenHash, code)
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(confirmation.CodeHash)) != 1 {
+		left := domain.MaxConfirmationCodeAttempts - confirmation.CodeAttempts - 1
+		if left <= 0 {
+			return errors.NewForbiddenError(lockedMessage)
+		}
+		return errors.NewBadRequestError(fmt.Sprintf("wrong code. %d attempts left", left))
+	}
+
+	return s.consumeConfirmation(ctx, confirmation, now)
+}
+
+// newConfirmationCode returns a random code of domain.ConfirmationCodeLength digits
+func newConfirmationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(math.Pow10(domain.ConfirmationCodeLength))))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", domain.ConfirmationCodeLength, n), nil
+}
+
+func isConfirmationCode(code string) bool {
+	if len(code) != domain.ConfirmationCodeLength {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isConfirmed is true if the student confirmed the school and doesn't have to confirm it again yet
+func isConfirmed(student *domain.Student, schoolID string, now time.Time) bool {
+	return student.School != nil && student.School.ID == schoolID && !student.NeedsSchoolVerification(now)
+}
+
+// checkCanVerify returns a 400 if the student can't confirm the school yet, and a 409 if another student of the school
+// verified the email. Another school than the confirmed one can always be confirmed
+func (s *schoolUseCase) checkCanVerify(ctx context.Context, student *domain.Student, email string, school *domain.School, now time.Time) error {
+	if isConfirmed(student, school.ID, now) {
+		return errors.NewBadRequestError("school already confirmed")
+	}
+	taken, err := s.r.SchoolEmailTaken(ctx, school.ID, email, student.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.NewConflictError("this email already confirmed another student of the school")
+	}
+	return nil
+}
+
+// checkSendLimits returns a 429 if the student or the email address had too many of the confirmation emails sent in
+// the window, with how long to wait before the next one is allowed
+func checkSendLimits(sends []domain.ConfirmationSend, studentID string, email string, now time.Time) error {
+	var byStudent, byEmail []time.Time
+	for _, send := range sends {
+		if send.StudentID == studentID {
+			byStudent = append(byStudent, send.SentAt)
+		}
+		if send.Email == email {
+			byEmail = append(byEmail, send.SentAt)
+		}
+	}
+
+	if n := len(byStudent); n > 0 && now.Sub(byStudent[n-1]) < resendCooldown {
+		return errors.NewTooManyRequestsError("a confirmation email was just sent. Check your inbox",
+			byStudent[n-1].Add(resendCooldown).Sub(now))
+	}
+	// the next email is allowed once enough of the counted ones are out of the window
+	if n := len(byStudent); n >= maxSendsPerStudent {
+		return errors.NewTooManyRequestsError(fmt.Sprintf("at most %d confirmation emails can be sent per hour",
+			maxSendsPerStudent), byStudent[n-maxSendsPerStudent].Add(confirmationWindow).Sub(now))
+	}
+	if n := len(byEmail); n >= maxSendsPerEmail {
+		return errors.NewTooManyRequestsError(fmt.Sprintf("at most %d confirmation emails can be sent to %s per hour",
+			maxSendsPerEmail, email), byEmail[n-maxSendsPerEmail].Add(confirmationWindow).Sub(now))
+	}
+	return nil
+}
+
+// sendConfirmationEmail emails the link of the confirmation to its address. The send was recorded with the
+// confirmation, so failed emails still count towards the limits
+func (s *schoolUseCase) sendConfirmationEmail(ctx context.Context, student *domain.Student, confirmation *domain.Confirmation, locale string) error {
+	domainName := os.Getenv("DOMAIN")
+	if domainName == "" {
+		log.Fatalln("Domain name not provided")
+	}
+	confirmationURL := fmt.Sprintf("%s/school/confirmation", domainName)
+	url := fmt.Sprintf("%s?token=%s", confirmationURL, confirmation.Token)
+
+	body, err := confirmationEmail(student, confirmation, url, locale)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return s.mailer.SendSimpleMail(ctx, confirmation.Email, body)
+}
@@ -1,42 +1,52 @@
 package usecase
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	_ "github.com/airbenders/profile/School/utils"
 	"github.com/airbenders/profile/domain"
-	"github.com/airbenders/profile/utils"
+	"github.com/airbenders/profile/static"
+	mocks "github.com/airbenders/profile/utils/channelmocks"
 	"github.com/airbenders/profile/utils/errors"
+	"github.com/airbenders/profile/utils/mailer"
 	"github.com/google/uuid"
 	"log"
+	"net/mail"
 	"os"
 	"reflect"
 	"strings"
-	"text/template"
 	"time"
+	"unicode/utf8"
 )
 
+// minSchoolQueryLength is the shortest school name that can be searched
+const minSchoolQueryLength = 2
+
 type schoolUseCase struct {
-	r       domain.SchoolRepository
-	str     domain.StudentRepository
-	mailer  utils.Mailer
-	timeout time.Duration
+	ch            mocks.Channel
+	schoolChanged chan domain.SchoolChange
+	r             domain.SchoolRepository
+	str           domain.StudentRepository
+	mailer        domain.Mailer
+	timeout       time.Duration
 }
 
-// NewSchoolUseCase is the constructor
-func NewSchoolUseCase(r domain.SchoolRepository, str domain.StudentRepository, mailer utils.Mailer, timeout time.Duration) domain.SchoolUseCase {
-	return &schoolUseCase{r, str, mailer, timeout}
+// NewSchoolUseCase is the constructor. School changes are published on ch by SchoolChangedTopic
+func NewSchoolUseCase(ch mocks.Channel, r domain.SchoolRepository, str domain.StudentRepository, mailer domain.Mailer, timeout time.Duration) domain.SchoolUseCase {
+	return &schoolUseCase{ch, make(chan domain.SchoolChange), r, str, mailer, timeout}
 }
 
-// SearchSchoolByDomain doesn't do much processing. just returns the school slice if received from the repository.
-// returns nil and 404 error if there are no schools
+// SearchSchoolByDomain returns the schools with the domain or one of its parent domains, the ones with the most
+// specific domain first. Returns nil and 404 error if there are no schools
 func (s *schoolUseCase) SearchSchoolByDomain(c context.Context, domainName string) ([]domain.School, error) {
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
 
-	domainNameLike := parseDomainName(domainName)
-	schools, err := s.r.SearchByDomain(ctx, domainNameLike)
+	domains := parentDomains(domainName)
+	if len(domains) == 0 {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("%s is not a valid domain name", domainName))
+	}
+	schools, err := s.r.SearchByDomain(ctx, domains)
 	if err != nil {
 		return nil, errors.NewInternalServerError(err.Error())
 	}
@@ -47,36 +57,68 @@ func (s *schoolUseCase) SearchSchoolByDomain(c context.Context, domainName strin
 	return schools, nil
 }
 
-func parseDomainName(name string) string {
-	var domains []string
-	// first add the same domain as we got: e.g. live.concordia.ca to it
-	domains = append(domains, name)
-	count := strings.Count(name, ".")
-	// live.concordia.ca has 2
-	if count > 1 {
-		if first := strings.Index(name, "."); first != len(name) {
-			// extract the latter half like concordia.ca and add it to domains
-			name = name[first+1:]
-			domains = append(domains, name)
+// ResolveSchool returns the school of an email domain. When several schools share the most specific domain, they are
+// all returned so the student can pick theirs with schoolID. schoolID must be a school of the domain
+func (s *schoolUseCase) ResolveSchool(c context.Context, domainName string, schoolID string) ([]domain.School, error) {
+	schools, err := s.SearchSchoolByDomain(c, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	if schoolID != "" {
+		for _, school := range schools {
+			if school.ID == schoolID {
+				return []domain.School{school}, nil
+			}
 		}
+		return nil, errors.NewBadRequestError(fmt.Sprintf("the school %s doesn't use the domain %s", schoolID, domainName))
 	}
-	// string builder
-	var domainNameLike bytes.Buffer
-	// write the first one. This shouldn't be an error
-	_, err := domainNameLike.WriteString(domains[0])
-	if err != nil {
-		log.Println("ISSUE!")
+	best := 1
+	for best < len(schools) && schools[best].MatchedDomain == schools[0].MatchedDomain {
+		best++
+	}
+	return schools[:best], nil
+}
+
+// SearchSchools looks up schools by name, optionally in a country only. The query needs at least
+// minSchoolQueryLength characters
+func (s *schoolUseCase) SearchSchools(c context.Context, query string, country string, page domain.Page) ([]domain.School, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	query = strings.Join(strings.Fields(query), " ")
+	if utf8.RuneCountInString(query) < minSchoolQueryLength {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("type at least %d characters of the school name",
+			minSchoolQueryLength))
+	}
+	return s.r.SearchByName(ctx, query, strings.TrimSpace(country), page)
+}
+
+// parentDomains returns the domain followed by its parent domains, like live.concordia.ca then concordia.ca.
+// Top level domains are left out since no school owns one. Returns nil for a malformed domain
+func parentDomains(name string) []string {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+	labels := strings.Split(name, ".")
+	for _, label := range labels {
+		if label == "" {
+			return nil
+		}
 	}
-	// for the rest, add them with an 'or' operator
-	for _, domainName := range domains[1:] {
-		domainNameLike.WriteString(fmt.Sprintf("|%s", domainName))
+
+	var domains []string
+	for i := 0; i < len(labels)-1; i++ {
+		domains = append(domains, strings.Join(labels[i:], "."))
 	}
-	return domainNameLike.String()
+	return domains
 }
 
 // SendConfirmation sends an email to the student's school email address with a generated token
-// also stores the token in the repository with the student's and school's IDs for confirmation later
-func (s *schoolUseCase) SendConfirmation(c context.Context, st *domain.Student, email string, school *domain.School) error {
+// also stores the hash of the token in the repository with the student's and school's IDs for confirmation later.
+// The tokens sent to the student before stop working. A confirmed school can only be confirmed again once its
+// verification expired, with an email no other student of the school verified. Confirming another school is a
+// transfer: the student keeps their school until the new one is confirmed. The email is written in the locale when
+// there is a translation
+func (s *schoolUseCase) SendConfirmation(c context.Context, st *domain.Student, email string, school *domain.School, locale string) error {
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
 
@@ -87,81 +129,165 @@ func (s *schoolUseCase) SendConfirmation(c context.Context, st *domain.Student,
 	if reflect.DeepEqual(student, &domain.Student{}) {
 		return errors.NewNotFoundError("student not found")
 	}
-	if student.School != nil {
-		return errors.NewBadRequestError("school already confirmed")
+	now := time.Now()
+	if err = s.checkCanVerify(ctx, student, email, school, now); err != nil {
+		return err
+	}
+	confirmation, err := s.newConfirmation(ctx, st, email, school, now)
+	if err != nil {
+		return err
 	}
 
-	token := uuid.New().String()
-	domainName := os.Getenv("DOMAIN")
-	if domainName == "" {
-		log.Fatalln("Domain name not provided")
+	return s.sendConfirmationEmail(ctx, student, confirmation, locale)
+}
+
+// newConfirmation generates a token and a code for the student and saves their hashes with the email as sent. Returns
+// a 400 if the address can't get emails, and a 429 if too many confirmation emails were sent to the student or the
+// address lately. Nothing is saved then, so the pending confirmations keep working
+func (s *schoolUseCase) newConfirmation(ctx context.Context, st *domain.Student, email string, school *domain.School, now time.Time) (*domain.Confirmation, error) {
+	suppression, err := s.r.GetEmailSuppression(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if suppression != nil {
+		return nil, errors.NewBadRequestError(suppression.Message())
 	}
-	confirmationURL := fmt.Sprintf("%s/school/confirmation", domainName)
-	url := fmt.Sprintf("%s?token=%s", confirmationURL, token)
 
+	token := uuid.New().String()
+	code, err := newConfirmationCode()
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
 	confirmation := &domain.Confirmation{
 		Token:     token,
+		TokenHash: domain.HashConfirmationToken(token),
+		Code:      code,
+		Email:     email,
 		School:    *school,
 		Student:   *st,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
 	}
-
-	err = s.r.SaveConfirmationToken(ctx, confirmation)
+	confirmation.CodeHash = domain.HashConfirmationCode(confirmation.TokenHash, code)
+	err = s.r.SaveConfirmationToken(ctx, confirmation, now.Add(-confirmationWindow), func(sends []domain.ConfirmationSend) error {
+		return checkSendLimits(sends, st.ID, email, now)
+	})
 	if err != nil {
 		log.Println("error in sendconfirmation, usecase: received from repo")
 		log.Println(err.Error())
-		return errors.NewInternalServerError(err.Error())
+		return nil, err
 	}
-
-	body := createEmailBody(student.FirstName, school.Name, url)
-	return s.mailer.SendSimpleMail(email, body)
+	return confirmation, nil
 }
 
-func createEmailBody(name, school, url string) []byte {
-	t, err := template.ParseFiles("static/confirmation_template.html")
+// emailTemplates are the emails sent to the students, in every locale
+var emailTemplates = mailer.MustParseTemplates(static.Files, "emails", domain.DefaultLocale)
+
+// confirmationEmail is the message asking the student to confirm their school, from the EMAIL_FROM address
+func confirmationEmail(student *domain.Student, confirmation *domain.Confirmation, url string, locale string) ([]byte, error) {
+	from, err := mail.ParseAddress(os.Getenv("EMAIL_FROM"))
 	if err != nil {
-		t, err = template.ParseFiles("../../static/confirmation_template.html")
-		if err != nil {
-			log.Fatal("no email confirmation template")
-		}
+		return nil, fmt.Errorf("invalid EMAIL_FROM: %w", err)
 	}
-	var body bytes.Buffer
-	mimeHeaders := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
-	body.Write([]byte(fmt.Sprintf("Subject: Smarties app confirmation\n%s\n\n", mimeHeaders)))
-	t.Execute(&body, struct {
+	subject, text, html, err := emailTemplates.Render("confirmation", locale, struct {
 		Name   string
 		School string
-		Email  string
+		URL    string
+		Code   string
 	}{
-		Name:   name,
-		School: school,
-		Email:  url,
+		Name:   student.FirstName,
+		School: confirmation.School.Name,
+		URL:    url,
+		Code:   confirmation.Code,
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return body.Bytes()
+	return mailer.Email{
+		From:    *from,
+		To:      mail.Address{Name: strings.TrimSpace(student.FirstName + " " + student.LastName), Address: confirmation.Email},
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+	}.Bytes()
 }
 
 // ConfirmSchoolEnrollment checks if the record for the token exists in the repository.
-// if it does, it checks to ensure it's more than 24 hours old
-func (s *schoolUseCase) ConfirmSchoolEnrollment(c context.Context, token string) error {
+// if it does, it checks to ensure it isn't expired or used, then uses it up. Links that can't be used return their
+// status without an error, so they can be explained to the student
+func (s *schoolUseCase) ConfirmSchoolEnrollment(c context.Context, token string) (domain.ConfirmationStatus, error) {
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
 
-	confirmation, err := s.r.GetConfirmationByToken(ctx, token)
+	confirmation, err := s.r.GetConfirmationByToken(ctx, domain.HashConfirmationToken(token))
 	if err != nil {
-		return errors.NewInternalServerError(err.Error())
+		return "", errors.NewInternalServerError(err.Error())
 	}
 	if reflect.DeepEqual(*confirmation, domain.Confirmation{}) {
-		return errors.NewNotFoundError("invalid token")
+		return domain.ConfirmationInvalid, nil
 	}
-	if confirmation.CreatedAt.Add(time.Hour * 24).Before(time.Now()) {
-		return errors.NewBadRequestError("token already expired")
+	now := time.Now()
+	if confirmation.ConsumedAt != nil {
+		student, err := s.str.GetByID(ctx, confirmation.Student.ID)
+		if err != nil {
+			return "", err
+		}
+		if isConfirmed(student, confirmation.School.ID, now) {
+			return domain.ConfirmationAlreadyConfirmed, nil
+		}
+		return domain.ConfirmationExpired, nil
+	}
+	if confirmation.CreatedAt.Add(domain.ConfirmationTTL).Before(now) {
+		return domain.ConfirmationExpired, nil
 	}
 
-	err = s.r.AddSchoolForStudent(ctx, confirmation.Student.ID, confirmation.School.ID)
+	if err = s.consumeConfirmation(ctx, confirmation, now); err != nil {
+		return "", err
+	}
+	return domain.ConfirmationSucceeded, nil
+}
+
+// PurgeExpiredConfirmations deletes the expired confirmations, then again every interval.
+// Meant to be run in its own goroutine
+func (s *schoolUseCase) PurgeExpiredConfirmations(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		s.deleteExpiredConfirmations()
+	}
+}
+
+func (s *schoolUseCase) deleteExpiredConfirmations() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	count, err := s.r.DeleteExpiredConfirmations(ctx, time.Now().Add(-domain.ConfirmationTTL))
 	if err != nil {
-		return errors.NewInternalServerError(err.Error())
+		log.Println("failed to purge confirmations", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("purged %d expired confirmations\n", count)
+	}
+}
+
+// SearchCourses looks up courses in the student's confirmed school by class code prefix or title.
+// Returns a 400 if the student hasn't confirmed a school yet
+func (s *schoolUseCase) SearchCourses(c context.Context, studentID string, query string) ([]domain.Course, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	student, err := s.str.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return nil, errors.NewNotFoundError("student not found")
+	}
+	if student.School == nil {
+		return nil, errors.NewBadRequestError("confirm your school before searching its courses")
 	}
 
-	return nil
+	query = strings.TrimSpace(query)
+	return s.r.SearchCourses(ctx, student.School.ID, domain.NormalizeCoursePrefix(query), query)
 }
@@ -1,15 +1,23 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/airbenders/profile/School/usecase"
 	"github.com/airbenders/profile/domain"
 	"github.com/airbenders/profile/domain/mocks"
+	mocks2 "github.com/airbenders/profile/utils/channelmocks"
+	e "github.com/airbenders/profile/utils/errors"
+	"github.com/airbenders/profile/utils/mailer"
 	"github.com/bxcodec/faker"
+	"github.com/streadway/amqp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"mime"
+	"net/mail"
 	"os"
 	"testing"
 	"time"
@@ -24,10 +32,10 @@ func TestSearchSchoolByDomain(t *testing.T) {
 
 	t.Run("case failure empty slice", func(t *testing.T) {
 		mockSchoolRepo.
-			On("SearchByDomain", mock.Anything, mock.AnythingOfType("string")).
+			On("SearchByDomain", mock.Anything, []string{"ocw.mit.edu", "mit.edu"}).
 			Return([]domain.School{}, nil).
 			Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, mockStudentRepo, nil, time.Second)
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
 
 		school, err := u.SearchSchoolByDomain(context.TODO(), mockSchool.Name)
 
@@ -39,10 +47,10 @@ func TestSearchSchoolByDomain(t *testing.T) {
 
 	t.Run("case success", func(t *testing.T) {
 		mockSchoolRepo.
-			On("SearchByDomain", mock.Anything, mock.AnythingOfType("string")).
+			On("SearchByDomain", mock.Anything, []string{"ocw.mit.edu", "mit.edu"}).
 			Return([]domain.School{domain.School{}}, nil).
 			Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, mockStudentRepo, nil, time.Second)
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
 
 		school, err := u.SearchSchoolByDomain(context.TODO(), mockSchool.Name)
 
@@ -54,10 +62,10 @@ func TestSearchSchoolByDomain(t *testing.T) {
 
 	t.Run("case error", func(t *testing.T) {
 		mockSchoolRepo.
-			On("SearchByDomain", mock.Anything, mock.AnythingOfType("string")).
+			On("SearchByDomain", mock.Anything, []string{"ocw.mit.edu", "mit.edu"}).
 			Return(nil, errors.New("error")).
 			Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, mockStudentRepo, nil, time.Second)
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
 
 		school, err := u.SearchSchoolByDomain(context.TODO(), mockSchool.Name)
 
@@ -68,46 +76,155 @@ func TestSearchSchoolByDomain(t *testing.T) {
 	})
 }
 
+func TestResolveSchool(t *testing.T) {
+	umontreal := domain.School{ID: "a", Name: "Université de Montréal", Domains: []string{"umontreal.ca"},
+		MatchedDomain: "umontreal.ca"}
+	polytechnique := domain.School{ID: "b", Name: "Polytechnique Montréal",
+		Domains: []string{"polymtl.ca", "umontreal.ca"}, MatchedDomain: "umontreal.ca"}
+	hec := domain.School{ID: "c", Name: "HEC Montréal", Domains: []string{"hec.ca", "hec.umontreal.ca"},
+		MatchedDomain: "hec.umontreal.ca"}
+
+	tests := []struct {
+		name     string
+		host     string
+		schoolID string
+		domains  []string
+		found    []domain.School
+		expected []domain.School
+		code     int
+	}{
+		{"most specific domain", "HEC.UMontreal.ca.", "", []string{"hec.umontreal.ca", "umontreal.ca"},
+			[]domain.School{hec, umontreal, polytechnique}, []domain.School{hec}, 0},
+		{"shared domain", "umontreal.ca", "", []string{"umontreal.ca"},
+			[]domain.School{umontreal, polytechnique}, []domain.School{umontreal, polytechnique}, 0},
+		{"chosen school", "umontreal.ca", "b", []string{"umontreal.ca"},
+			[]domain.School{umontreal, polytechnique}, []domain.School{polytechnique}, 0},
+		{"chosen school of another domain", "umontreal.ca", "mcgill", []string{"umontreal.ca"},
+			[]domain.School{umontreal, polytechnique}, nil, 400},
+		{"no school", "example.com", "", []string{"example.com"}, []domain.School{}, nil, 404},
+		{"malformed domain", "umontreal..ca", "", nil, nil, nil, 400},
+		{"top level domain", "ca", "", nil, nil, nil, 400},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+			if test.domains != nil {
+				mockSchoolRepo.On("SearchByDomain", mock.Anything, test.domains).Return(test.found, nil).Once()
+			}
+			u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+			schools, err := u.ResolveSchool(context.TODO(), test.host, test.schoolID)
+
+			if test.code != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, test.code, err.(*e.RestError).Code)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expected, schools)
+			mockSchoolRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchSchools(t *testing.T) {
+	page := domain.Page{Number: 1, Size: 20}
+
+	t.Run("case success", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		schools := []domain.School{{ID: "a", Name: "Cégep de Saint-Jérôme"}}
+		mockSchoolRepo.On("SearchByName", mock.Anything, "cegep saint", "Canada", page).Return(schools, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+		returned, err := u.SearchSchools(context.TODO(), "  cegep   saint ", " Canada", page)
+
+		assert.NoError(t, err)
+		assert.Equal(t, schools, returned)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("case error-too-short", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+		_, err := u.SearchSchools(context.TODO(), " é ", "", page)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockSchoolRepo.AssertNotCalled(t, "SearchByName", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func TestSendConfirmation(t *testing.T) {
 	mockSchoolRepo := new(mocks.SchoolRepositoryMock)
 	mockStudentRepo := new(mocks.StudentRepositoryMock)
 	var mockSchool domain.School
 	var mockStudent domain.Student
-	var mockMailer mocks.SimpleMail
+	transport := mailer.NewMemoryTransport()
 	os.Setenv("DOMAIN", "localhost")
 	env := os.Getenv("DOMAIN")
 	t.Cleanup(func() { os.Setenv("DOMAIN", env) })
+	t.Setenv("EMAIL_FROM", "Smarties <no-reply@smarties.ca>")
 	faker.FakeData(&mockStudent)
 	fmt.Println(mockStudent.School.ID)
 
 	t.Run("case-success", func(t *testing.T) {
-		faker.FakeData(&mockMailer)
 		mockStudent.School = nil
 		mockStudentRepo.
 			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
 			Return(&mockStudent, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, mockSchool.ID, mockStudent.Email, mockStudent.ID).
+			Return(false, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, mockStudent.Email).Return(nil, nil).Once()
 		mockSchoolRepo.
-			On("SaveConfirmationToken", mock.Anything, mock.AnythingOfType("*domain.Confirmation")).
-			Return(nil).Once()
-		mockMailer.On("SendSimpleMail", mock.AnythingOfType("string"), mock.Anything).
-			Return(nil).Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, mockStudentRepo, mockMailer, time.Second)
+			On("SaveConfirmationToken", mock.Anything, mock.MatchedBy(func(confirmation *domain.Confirmation) bool {
+				return confirmation.Token != "" && confirmation.TokenHash == domain.HashConfirmationToken(confirmation.Token) &&
+					len(confirmation.Code) == domain.ConfirmationCodeLength &&
+					confirmation.CodeHash == domain.HashConfirmationCode(confirmation.TokenHash, confirmation.Code)
+			}), mock.Anything).
+			Return(nil, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, transport, time.Second)
 
-		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool)
+		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool, "en")
 
 		assert.NoError(t, err)
+		assert.Len(t, transport.Messages(), 1)
 		mockStudentRepo.AssertExpectations(t)
 		mockSchoolRepo.AssertExpectations(t)
 	})
 
+	t.Run("case error-email-from", func(t *testing.T) {
+		t.Setenv("EMAIL_FROM", "")
+		mockStudent.School = nil
+		transport.Reset()
+		mockStudentRepo.
+			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
+			Return(&mockStudent, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, mockSchool.ID, mockStudent.Email, mockStudent.ID).
+			Return(false, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, mockStudent.Email).Return(nil, nil).Once()
+		mockSchoolRepo.On("SaveConfirmationToken", mock.Anything, mock.AnythingOfType("*domain.Confirmation"), mock.Anything).
+			Return(nil, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, transport, time.Second)
+
+		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool, "en")
+
+		assert.Error(t, err)
+		assert.Equal(t, 500, err.(*e.RestError).Code)
+		assert.Empty(t, transport.Messages())
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
 	t.Run("case error: School-already-confirmed", func(t *testing.T) {
 		faker.FakeData(&mockStudent)
+		mockStudent.School = &mockSchool
+		mockStudent.SchoolVerification = &domain.SchoolVerification{Email: mockStudent.Email, VerifiedAt: time.Now()}
 		mockStudentRepo.
 			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
 			Return(&mockStudent, nil).Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, mockStudentRepo, mockMailer, time.Second)
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, transport, time.Second)
 
-		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool)
+		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool, "en")
 
 		assert.Error(t, err)
 	})
@@ -117,9 +234,9 @@ func TestSendConfirmation(t *testing.T) {
 			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
 			Return(&domain.Student{}, nil).Once()
 
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, mockStudentRepo, mockMailer, time.Second)
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, transport, time.Second)
 
-		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool)
+		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool, "en")
 		assert.Error(t, err)
 		mockStudentRepo.AssertExpectations(t)
 	})
@@ -129,36 +246,283 @@ func TestSendConfirmation(t *testing.T) {
 		mockStudentRepo.
 			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
 			Return(&mockStudent, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, mockSchool.ID, mockStudent.Email, mockStudent.ID).
+			Return(false, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, mockStudent.Email).Return(nil, nil).Once()
 		mockSchoolRepo.
-			On("SaveConfirmationToken", mock.Anything, mock.AnythingOfType("*domain.Confirmation")).
-			Return(errors.New("error")).Once()
-		mockMailer.On("SendSimpleMail", mock.AnythingOfType("string"), mock.Anything).
-			Return(nil).Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, mockStudentRepo, mockMailer, time.Second)
-		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool)
+			On("SaveConfirmationToken", mock.Anything, mock.AnythingOfType("*domain.Confirmation"), mock.Anything).
+			Return(nil, errors.New("error")).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, transport, time.Second)
+		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool, "en")
 
 		assert.Error(t, err)
 		mockStudentRepo.AssertExpectations(t)
 		mockSchoolRepo.AssertExpectations(t)
 	})
+
+	t.Run("case error-send", func(t *testing.T) {
+		mockStudent.School = nil
+		transport.Reset()
+		transport.FailWith(errors.New("smtp: can't connect"))
+		t.Cleanup(func() { transport.FailWith(nil) })
+		mockStudentRepo.
+			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
+			Return(&mockStudent, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, mockSchool.ID, mockStudent.Email, mockStudent.ID).
+			Return(false, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, mockStudent.Email).Return(nil, nil).Once()
+		mockSchoolRepo.On("SaveConfirmationToken", mock.Anything, mock.AnythingOfType("*domain.Confirmation"), mock.Anything).
+			Return(nil, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, transport, time.Second)
+		err := u.SendConfirmation(context.TODO(), &mockStudent, mockStudent.Email, &mockSchool, "en")
+
+		assert.Error(t, err)
+		assert.Empty(t, transport.Messages())
+		mockSchoolRepo.AssertExpectations(t)
+	})
+}
+
+func TestConfirmationLimits(t *testing.T) {
+	os.Setenv("DOMAIN", "localhost")
+	env := os.Getenv("DOMAIN")
+	t.Cleanup(func() { os.Setenv("DOMAIN", env) })
+	t.Setenv("EMAIL_FROM", "Smarties <no-reply@smarties.ca>")
+	student := &domain.Student{ID: "st", FirstName: "Ada"}
+	school := &domain.School{ID: "sc", Name: "Concordia University"}
+	const email = "ada@concordia.ca"
+
+	t.Run("case error-cooldown", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, "sc", email, "st").Return(false, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, email).Return(nil, nil).Once()
+		mockSchoolRepo.On("SaveConfirmationToken", mock.Anything, mock.AnythingOfType("*domain.Confirmation"), mock.Anything).
+			Return([]domain.ConfirmationSend{{StudentID: "st", Email: email, SentAt: time.Now().Add(-20 * time.Second)}}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SendConfirmation(context.TODO(), student, email, school, "en")
+
+		assert.Error(t, err)
+		assert.Equal(t, 429, err.(*e.RestError).Code)
+		assert.InDelta(t, 40, err.(*e.RestError).RetryAfter, 1)
+	})
+
+	t.Run("case error-too-many-to-email", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		now := time.Now()
+		sends := []domain.ConfirmationSend{
+			{StudentID: "other", Email: email, SentAt: now.Add(-50 * time.Minute)},
+			{StudentID: "other", Email: email, SentAt: now.Add(-40 * time.Minute)},
+			{StudentID: "someone", Email: email, SentAt: now.Add(-30 * time.Minute)},
+		}
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, "sc", email, "st").Return(false, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, email).Return(nil, nil).Once()
+		mockSchoolRepo.On("SaveConfirmationToken", mock.Anything, mock.AnythingOfType("*domain.Confirmation"), mock.Anything).
+			Return(sends, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SendConfirmation(context.TODO(), student, email, school, "en")
+
+		assert.Error(t, err)
+		assert.Equal(t, 429, err.(*e.RestError).Code)
+		assert.InDelta(t, 10*60, err.(*e.RestError).RetryAfter, 1)
+	})
+
+	t.Run("case resend-success", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		transport := mailer.NewMemoryTransport()
+		pending := []domain.Confirmation{{TokenHash: "new", Email: email, School: *school}, {TokenHash: "old", Email: "x@y.ca"}}
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).Return(pending, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, email).Return(nil, nil).Once()
+		mockSchoolRepo.On("SaveConfirmationToken", mock.Anything, mock.MatchedBy(func(confirmation *domain.Confirmation) bool {
+			return confirmation.Email == email && confirmation.School.ID == school.ID && confirmation.TokenHash != "new"
+		}), mock.Anything).Return(nil, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, transport, time.Second)
+
+		err := u.ResendConfirmation(context.TODO(), "st", "fr")
+
+		assert.NoError(t, err)
+		mockSchoolRepo.AssertExpectations(t)
+		sent, ok := transport.Last(email)
+		assert.True(t, ok)
+		message, err := mail.ReadMessage(bytes.NewReader(sent.Body))
+		assert.NoError(t, err)
+		subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Confirmation de l’application Smarties", subject)
+		from, err := message.Header.AddressList("From")
+		assert.NoError(t, err)
+		assert.Equal(t, []*mail.Address{{Name: "Smarties", Address: "no-reply@smarties.ca"}}, from)
+		assert.Contains(t, message.Header.Get("Content-Type"), "multipart/alternative")
+	})
+
+	t.Run("case error-suppressed", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, "sc", email, "st").Return(false, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, email).
+			Return(&domain.MailSuppression{Email: email, Reason: domain.MailBounce}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SendConfirmation(context.TODO(), student, email, school, "en")
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockSchoolRepo.AssertNotCalled(t, "SaveConfirmationToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("case resend-error-suppressed", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		pending := []domain.Confirmation{{TokenHash: "new", Email: email, School: *school}}
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).Return(pending, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, email).
+			Return(&domain.MailSuppression{Email: email, Reason: domain.MailComplaint}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.ResendConfirmation(context.TODO(), "st", "en")
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockSchoolRepo.AssertNotCalled(t, "SaveConfirmationToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("case resend-error-nothing-pending", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).
+			Return([]domain.Confirmation{}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.ResendConfirmation(context.TODO(), "st", "en")
+
+		assert.Error(t, err)
+		assert.Equal(t, 404, err.(*e.RestError).Code)
+	})
+}
+
+func TestSchoolVerification(t *testing.T) {
+	os.Setenv("DOMAIN", "localhost")
+	env := os.Getenv("DOMAIN")
+	t.Cleanup(func() { os.Setenv("DOMAIN", env) })
+	t.Setenv("EMAIL_FROM", "Smarties <no-reply@smarties.ca>")
+	school := &domain.School{ID: "sc", Name: "Concordia University"}
+	const email = "ada@concordia.ca"
+
+	t.Run("case error-already-verified", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		student := &domain.Student{ID: "st", School: school,
+			SchoolVerification: &domain.SchoolVerification{Email: email, VerifiedAt: time.Now().Add(-24 * time.Hour)}}
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Twice()
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).
+			Return([]domain.Confirmation{{Email: email, School: *school}}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SendConfirmation(context.TODO(), student, email, school, "en")
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+
+		err = u.ResendConfirmation(context.TODO(), "st", "en")
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+	})
+
+	t.Run("case transfer", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		transport := mailer.NewMemoryTransport()
+		student := &domain.Student{ID: "st", School: &domain.School{ID: "other", Name: "McGill University"},
+			SchoolVerification: &domain.SchoolVerification{Email: "ada@mcgill.ca", VerifiedAt: time.Now()}}
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, "sc", email, "st").Return(false, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, email).Return(nil, nil).Once()
+		mockSchoolRepo.On("SaveConfirmationToken", mock.Anything, mock.AnythingOfType("*domain.Confirmation"), mock.Anything).
+			Return(nil, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, transport, time.Second)
+
+		err := u.SendConfirmation(context.TODO(), student, email, school, "en")
+
+		assert.NoError(t, err)
+		mockSchoolRepo.AssertExpectations(t)
+		_, sent := transport.Last(email)
+		assert.True(t, sent)
+	})
+
+	t.Run("case error-email-taken", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		student := &domain.Student{ID: "st"}
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, "sc", email, "st").Return(true, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SendConfirmation(context.TODO(), student, email, school, "en")
+
+		assert.Error(t, err)
+		assert.Equal(t, 409, err.(*e.RestError).Code)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("case reverify-expired", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		transport := mailer.NewMemoryTransport()
+		student := &domain.Student{ID: "st", School: school, SchoolVerification: &domain.SchoolVerification{
+			Email: email, VerifiedAt: time.Now().Add(-domain.SchoolVerificationTTL - time.Hour)}}
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("SchoolEmailTaken", mock.Anything, "sc", email, "st").Return(false, nil).Once()
+		mockSchoolRepo.On("GetEmailSuppression", mock.Anything, email).Return(nil, nil).Once()
+		mockSchoolRepo.On("SaveConfirmationToken", mock.Anything, mock.AnythingOfType("*domain.Confirmation"), mock.Anything).
+			Return(nil, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, transport, time.Second)
+
+		err := u.SendConfirmation(context.TODO(), student, email, school, "en")
+
+		assert.NoError(t, err)
+		mockSchoolRepo.AssertExpectations(t)
+		_, sent := transport.Last(email)
+		assert.True(t, sent)
+	})
 }
 
 func TestConfirmSchoolEnrollment(t *testing.T) {
 	mockSchoolRepo := new(mocks.SchoolRepositoryMock)
 	var mockConfirmation domain.Confirmation
 	faker.FakeData(&mockConfirmation)
+	mockConfirmation.CreatedAt = time.Now().Add(-time.Hour)
+	mockConfirmation.ConsumedAt = nil
+	tokenHash := domain.HashConfirmationToken(mockConfirmation.Token)
 
 	t.Run("case success", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		ch, changes := publishedChanges()
 		mockSchoolRepo.
-			On("GetConfirmationByToken", mock.Anything, mock.AnythingOfType("string")).
+			On("GetConfirmationByToken", mock.Anything, tokenHash).
 			Return(&mockConfirmation, nil).Once()
+		mockStudentRepo.On("GetByID", mock.Anything, mockConfirmation.Student.ID).
+			Return(&domain.Student{ID: mockConfirmation.Student.ID}, nil).Once()
 		mockSchoolRepo.
-			On("AddSchoolForStudent", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+			On("ConsumeConfirmation", mock.Anything, &mockConfirmation, mock.AnythingOfType("time.Time")).
 			Return(nil).Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, nil, nil, time.Second)
-		err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
+		u := usecase.NewSchoolUseCase(ch, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+		go u.SchoolChangedTopic()
+		status, err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
 
 		assert.Nil(t, err)
+		assert.Equal(t, domain.ConfirmationSucceeded, status)
+		change := <-changes
+		assert.Equal(t, mockConfirmation.Student.ID, change.StudentID)
+		assert.Equal(t, "", change.PreviousSchoolID)
+		assert.Equal(t, mockConfirmation.School.ID, change.SchoolID)
 		mockSchoolRepo.AssertExpectations(t)
 	})
 
@@ -166,36 +530,392 @@ func TestConfirmSchoolEnrollment(t *testing.T) {
 		mockSchoolRepo.
 			On("GetConfirmationByToken", mock.Anything, mock.AnythingOfType("string")).
 			Return(nil, errors.New("error no token")).Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, nil, nil, time.Second)
-		err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+		_, err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
 
 		assert.Error(t, err)
 		mockSchoolRepo.AssertExpectations(t)
 	})
 
-	t.Run("case error-invalid-token", func(t *testing.T) {
+	t.Run("case invalid-token", func(t *testing.T) {
 
 		mockSchoolRepo.
 			On("GetConfirmationByToken", mock.Anything, mock.AnythingOfType("string")).
 			Return(&domain.Confirmation{}, nil).Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, nil, nil, time.Second)
-		err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+		status, err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
 
-		assert.Error(t, err)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ConfirmationInvalid, status)
 		mockSchoolRepo.AssertExpectations(t)
 	})
 
-	t.Run("case error-expired-token", func(t *testing.T) {
+	t.Run("case expired-token", func(t *testing.T) {
 		now := time.Now()
 		then := now.Add(-25 * time.Hour)
 		mockSchoolRepo.
 			On("GetConfirmationByToken", mock.Anything, mock.AnythingOfType("string")).
 			Return(&domain.Confirmation{CreatedAt: then}, nil).Once()
-		u := usecase.NewSchoolUseCase(mockSchoolRepo, nil, nil, time.Second)
-		err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+		status, err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ConfirmationExpired, status)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("case replaced-token", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		consumedAt := time.Now().Add(-time.Minute)
+		mockSchoolRepo.
+			On("GetConfirmationByToken", mock.Anything, mock.AnythingOfType("string")).
+			Return(&domain.Confirmation{Student: domain.Student{ID: "st"}, School: domain.School{ID: "sc"},
+				CreatedAt: time.Now().Add(-time.Hour), ConsumedAt: &consumedAt}, nil).Once()
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(&domain.Student{ID: "st"}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+		status, err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ConfirmationExpired, status)
+		mockSchoolRepo.AssertExpectations(t)
+		mockSchoolRepo.AssertNotCalled(t, "ConsumeConfirmation", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("case already-confirmed", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		consumedAt := time.Now().Add(-time.Minute)
+		student := &domain.Student{ID: "st", School: &domain.School{ID: "sc"},
+			SchoolVerification: &domain.SchoolVerification{VerifiedAt: consumedAt}}
+		mockSchoolRepo.
+			On("GetConfirmationByToken", mock.Anything, mock.AnythingOfType("string")).
+			Return(&domain.Confirmation{Student: domain.Student{ID: "st"}, School: domain.School{ID: "sc"},
+				CreatedAt: time.Now().Add(-time.Hour), ConsumedAt: &consumedAt}, nil).Once()
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+		status, err := u.ConfirmSchoolEnrollment(context.TODO(), mockConfirmation.Token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ConfirmationAlreadyConfirmed, status)
+	})
+}
+
+func TestConfirmSchoolWithCode(t *testing.T) {
+	const code = "042137"
+	pendingWith := func(attempts int, age time.Duration) []domain.Confirmation {
+		return []domain.Confirmation{{TokenHash: "tok", CodeHash: domain.HashConfirmationCode("tok", code),
+			CodeAttempts: attempts, Student: domain.Student{ID: "st"}, School: domain.School{ID: "sc"},
+			CreatedAt: time.Now().Add(-age)}}
+	}
+
+	t.Run("case success", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		pending := pendingWith(1, time.Minute)
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).Return(pending, nil).Once()
+		mockSchoolRepo.On("AddCodeAttempt", mock.Anything, "tok").Return(true, nil).Once()
+		// confirming the same school again doesn't change it
+		mockStudentRepo.On("GetByID", mock.Anything, "st").
+			Return(&domain.Student{ID: "st", School: &domain.School{ID: "sc"}}, nil).Once()
+		mockSchoolRepo.On("ConsumeConfirmation", mock.Anything, &pending[0], mock.AnythingOfType("time.Time")).
+			Return(nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.ConfirmSchoolWithCode(context.TODO(), "st", " "+code+" ")
+
+		assert.NoError(t, err)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("case error-not-a-code", func(t *testing.T) {
+		u := usecase.NewSchoolUseCase(nil, new(mocks.SchoolRepositoryMock), nil, nil, time.Second)
+
+		for _, invalid := range []string{"", "12345", "1234567", "12a456"} {
+			err := u.ConfirmSchoolWithCode(context.TODO(), "st", invalid)
+			assert.Error(t, err)
+			assert.Equal(t, 400, err.(*e.RestError).Code)
+		}
+	})
+
+	t.Run("case error-nothing-pending", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).
+			Return([]domain.Confirmation{}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+		err := u.ConfirmSchoolWithCode(context.TODO(), "st", code)
+
+		assert.Error(t, err)
+		assert.Equal(t, 404, err.(*e.RestError).Code)
+	})
+
+	t.Run("case error-expired", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).
+			Return(pendingWith(0, domain.ConfirmationCodeTTL+time.Minute), nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+		err := u.ConfirmSchoolWithCode(context.TODO(), "st", code)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockSchoolRepo.AssertNotCalled(t, "AddCodeAttempt", mock.Anything, mock.Anything)
+	})
+
+	t.Run("case error-locked", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).
+			Return(pendingWith(domain.MaxConfirmationCodeAttempts, time.Minute), nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+		err := u.ConfirmSchoolWithCode(context.TODO(), "st", code)
+
+		assert.Error(t, err)
+		assert.Equal(t, 403, err.(*e.RestError).Code)
+		mockSchoolRepo.AssertNotCalled(t, "AddCodeAttempt", mock.Anything, mock.Anything)
+	})
+
+	t.Run("case error-locked-meanwhile", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).
+			Return(pendingWith(3, time.Minute), nil).Once()
+		mockSchoolRepo.On("AddCodeAttempt", mock.Anything, "tok").Return(false, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+		err := u.ConfirmSchoolWithCode(context.TODO(), "st", code)
+
+		assert.Error(t, err)
+		assert.Equal(t, 403, err.(*e.RestError).Code)
+		mockSchoolRepo.AssertNotCalled(t, "ConsumeConfirmation", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("case error-wrong-code", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).
+			Return(pendingWith(1, time.Minute), nil).Once()
+		mockSchoolRepo.On("AddCodeAttempt", mock.Anything, "tok").Return(true, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+		err := u.ConfirmSchoolWithCode(context.TODO(), "st", "000000")
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		assert.Contains(t, err.Error(), "3 attempts left")
+		mockSchoolRepo.AssertNotCalled(t, "ConsumeConfirmation", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("case error-wrong-last-code", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockSchoolRepo.On("GetPendingConfirmations", mock.Anything, "st", mock.Anything).
+			Return(pendingWith(domain.MaxConfirmationCodeAttempts-1, time.Minute), nil).Once()
+		mockSchoolRepo.On("AddCodeAttempt", mock.Anything, "tok").Return(true, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+		err := u.ConfirmSchoolWithCode(context.TODO(), "st", "000000")
+
+		assert.Error(t, err)
+		assert.Equal(t, 403, err.(*e.RestError).Code)
+	})
+}
+
+func TestLeaveSchool(t *testing.T) {
+	t.Run("case success", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		ch, changes := publishedChanges()
+		mockStudentRepo.On("GetByID", mock.Anything, "st").
+			Return(&domain.Student{ID: "st", School: &domain.School{ID: "sc"}}, nil).Once()
+		mockSchoolRepo.On("RemoveSchool", mock.Anything, "st", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		u := usecase.NewSchoolUseCase(ch, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+		go u.SchoolChangedTopic()
+
+		err := u.LeaveSchool(context.TODO(), "st")
+
+		assert.NoError(t, err)
+		change := <-changes
+		assert.Equal(t, domain.SchoolChange{StudentID: "st", PreviousSchoolID: "sc", ChangedAt: change.ChangedAt}, change)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("case error-no-school", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(&domain.Student{ID: "st"}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.LeaveSchool(context.TODO(), "st")
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockSchoolRepo.AssertNotCalled(t, "RemoveSchool", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("case error-repo", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "st").
+			Return(&domain.Student{ID: "st", School: &domain.School{ID: "sc"}}, nil).Once()
+		mockSchoolRepo.On("RemoveSchool", mock.Anything, "st", mock.AnythingOfType("time.Time")).
+			Return(e.NewInternalServerError("error")).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.LeaveSchool(context.TODO(), "st")
+
+		assert.Error(t, err)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+}
+
+func TestSelectSchoolUnits(t *testing.T) {
+	student := &domain.Student{ID: "st", School: &domain.School{ID: "sc"}}
+	campuses := []domain.Campus{{ID: "loy", SchoolID: "sc"}, {ID: "sgw", SchoolID: "sc"}}
+	faculties := []domain.Faculty{{ID: "eng", SchoolID: "sc"}}
+
+	t.Run("case success", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		units := domain.SchoolUnits{CampusID: "sgw", FacultyID: "eng"}
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("GetCampuses", mock.Anything, "sc").Return(campuses, nil).Once()
+		mockSchoolRepo.On("GetFaculties", mock.Anything, "sc").Return(faculties, nil).Once()
+		mockSchoolRepo.On("UpdateSchoolUnits", mock.Anything, "st", "sc", units).Return(nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SelectSchoolUnits(context.TODO(), "st", units)
+
+		assert.NoError(t, err)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("case success-unset", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("UpdateSchoolUnits", mock.Anything, "st", "sc", domain.SchoolUnits{}).Return(nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SelectSchoolUnits(context.TODO(), "st", domain.SchoolUnits{})
+
+		assert.NoError(t, err)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("case error-other-school-campus", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("GetCampuses", mock.Anything, "sc").Return(campuses, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SelectSchoolUnits(context.TODO(), "st", domain.SchoolUnits{CampusID: "downtown"})
 
 		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockSchoolRepo.AssertNotCalled(t, "UpdateSchoolUnits", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything)
+	})
+
+	t.Run("case error-no-school", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(&domain.Student{ID: "st"}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SelectSchoolUnits(context.TODO(), "st", domain.SchoolUnits{FacultyID: "eng"})
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
 		mockSchoolRepo.AssertExpectations(t)
 	})
 
+	t.Run("case error-student-not-found", func(t *testing.T) {
+		mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(&domain.Student{}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		err := u.SelectSchoolUnits(context.TODO(), "st", domain.SchoolUnits{})
+
+		assert.Error(t, err)
+		assert.Equal(t, 404, err.(*e.RestError).Code)
+	})
+}
+
+// publishedChanges is a channel that sends the school changes published on it to changes
+func publishedChanges() (*mocks2.ChannelMock, chan domain.SchoolChange) {
+	channelMock := new(mocks2.ChannelMock)
+	changes := make(chan domain.SchoolChange, 1)
+	channelMock.On("Publish", "profile", "profile.school_changed", false, false, mock.Anything).
+		Run(func(args mock.Arguments) {
+			var change domain.SchoolChange
+			json.Unmarshal(args.Get(4).(amqp.Publishing).Body, &change)
+			changes <- change
+		}).
+		Return(nil)
+	return channelMock, changes
+}
+
+func TestPurgeExpiredConfirmations(t *testing.T) {
+	mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+	purged := make(chan time.Time, 1)
+	mockSchoolRepo.On("DeleteExpiredConfirmations", mock.Anything, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			select {
+			case purged <- args.Get(1).(time.Time):
+			default:
+			}
+		}).Return(int64(2), nil)
+	u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, nil, nil, time.Second)
+
+	go u.PurgeExpiredConfirmations(time.Millisecond)
+
+	select {
+	case before := <-purged:
+		assert.WithinDuration(t, time.Now().Add(-domain.ConfirmationTTL), before, time.Minute)
+	case <-time.After(time.Second):
+		assert.Fail(t, "expired confirmations were not purged")
+	}
+}
+
+func TestSearchCourses(t *testing.T) {
+	mockSchoolRepo := new(mocks.SchoolRepositoryMock)
+	mockStudentRepo := new(mocks.StudentRepositoryMock)
+	student := &domain.Student{ID: "st", School: &domain.School{ID: "sc"}}
+	courses := []domain.Course{{ID: "a", SchoolID: "sc", Subject: "COMP", Number: "354", Title: "Software Engineering"}}
+
+	t.Run("case success", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(student, nil).Once()
+		mockSchoolRepo.On("SearchCourses", mock.Anything, "sc", "COMP 35", "comp35").Return(courses, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		returned, err := u.SearchCourses(context.TODO(), "st", " comp35 ")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, courses, returned)
+		mockStudentRepo.AssertExpectations(t)
+		mockSchoolRepo.AssertExpectations(t)
+	})
+
+	t.Run("case error-school-not-confirmed", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(&domain.Student{ID: "st"}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		returned, err := u.SearchCourses(context.TODO(), "st", "comp")
+
+		assert.Error(t, err)
+		assert.Nil(t, returned)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("case error-empty-student", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "st").Return(&domain.Student{}, nil).Once()
+		u := usecase.NewSchoolUseCase(nil, mockSchoolRepo, mockStudentRepo, nil, time.Second)
+
+		returned, err := u.SearchCourses(context.TODO(), "st", "comp")
+
+		assert.Error(t, err)
+		assert.Nil(t, returned)
+		mockStudentRepo.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"reflect"
+)
+
+// GetCampuses returns the campuses of the school by name
+func (s *schoolUseCase) GetCampuses(c context.Context, schoolID string) ([]domain.Campus, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	return s.r.GetCampuses(ctx, schoolID)
+}
+
+// GetFaculties returns the faculties of the school by name
+func (s *schoolUseCase) GetFaculties(c context.Context, schoolID string) ([]domain.Faculty, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	return s.r.GetFaculties(ctx, schoolID)
+}
+
+// SelectSchoolUnits sets the campus and the faculty of the student in their confirmed school. Both must belong to
+// that school, an empty ID unsets it
+func (s *schoolUseCase) SelectSchoolUnits(c context.Context, studentID string, units domain.SchoolUnits) error {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	student, err := s.str.GetByID(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return errors.NewNotFoundError("student not found")
+	}
+	if student.School == nil {
+		return errors.NewBadRequestError("confirm your school first")
+	}
+	schoolID := student.School.ID
+
+	if units.CampusID != "" {
+		campuses, err := s.r.GetCampuses(ctx, schoolID)
+		if err != nil {
+			return err
+		}
+		if !hasCampus(campuses, units.CampusID) {
+			return errors.NewBadRequestError("this campus is not in your school")
+		}
+	}
+	if units.FacultyID != "" {
+		faculties, err := s.r.GetFaculties(ctx, schoolID)
+		if err != nil {
+			return err
+		}
+		if !hasFaculty(faculties, units.FacultyID) {
+			return errors.NewBadRequestError("this faculty is not in your school")
+		}
+	}
+
+	return s.r.UpdateSchoolUnits(ctx, studentID, schoolID, units)
+}
+
+func hasCampus(campuses []domain.Campus, id string) bool {
+	for _, campus := range campuses {
+		if campus.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFaculty(faculties []domain.Faculty, id string) bool {
+	for _, faculty := range faculties {
+		if faculty.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,8 @@
 // Package utils adds all the schools to the db. Import this package to add all the schools
 // WARNING: DO THIS ONLY ONCE EVER FOR AN APPLICATION
 // OTHERWISE ALL THE IDs OF SCHOOLS WILL BE RANDOM
+// The campuses and the faculties of school_units.json and the courses of courses.json are added on every start, the
+// ones already there are kept
 package utils
 
 import (
@@ -19,14 +21,28 @@ import (
 func init() {
 	if !strings.HasSuffix(os.Args[0], ".test") {
 		addSchoolsToDB()
+		addSchoolUnitsToDB()
+		addCoursesToDB()
 	}
 }
 
 const (
-	insert = `INSERT INTO school (id, name, country, domains) VALUES ($1, $2, $3, $4)`
-	get = `SELECT * FROM school LIMIT 1`
+	insert        = `INSERT INTO school (id, name, country, domains) VALUES ($1, $2, $3, $4)`
+	insertDomain  = `INSERT INTO school_domain (domain, sc_id) VALUES (lower($1), $2) ON CONFLICT DO NOTHING`
+	get           = `SELECT * FROM school LIMIT 1`
+	insertCampus  = `INSERT INTO campus (id, school, name) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	insertFaculty = `INSERT INTO faculty (id, school, name) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	insertCourse  = `INSERT INTO course (id, school, subject, number, title, term)
+	VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, '')) ON CONFLICT DO NOTHING`
 )
 
+// schoolUnits are the campuses and the faculties of a school in school_units.json
+type schoolUnits struct {
+	SchoolID  string           `json:"school_id"`
+	Campuses  []domain.Campus  `json:"campuses"`
+	Faculties []domain.Faculty `json:"faculties"`
+}
+
 func addSchoolsToDB() {
 	pool, err := pgxpool.Connect(context.Background(), os.Getenv("DATABASE_URL"))
 	if err != nil {
@@ -64,11 +80,92 @@ func addSchoolsToDB() {
 		if err != nil {
 			log.Fatalln(err)
 		}
+		for _, domainName := range school.Domains {
+			_, err = tx.Exec(context.Background(), insertDomain, domainName, school.ID)
+			if err != nil {
+				log.Fatalln(err)
+			}
+		}
 
 		tx.Commit(context.Background())
 	}
 }
 
+func addSchoolUnitsToDB() {
+	pool, err := pgxpool.Connect(context.Background(), os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer pool.Close()
+
+	cwd, err := os.Getwd()
+	fatalOnError(err)
+	unitsJSON, err := ioutil.ReadFile(path.Join(cwd, "School", "utils", "school_units.json"))
+	fatalOnError(err)
+	var units []schoolUnits
+	if err = json.Unmarshal(unitsJSON, &units); err != nil {
+		log.Fatalln(err)
+	}
+
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer tx.Rollback(context.Background())
+
+	for _, school := range units {
+		for _, campus := range school.Campuses {
+			_, err = tx.Exec(context.Background(), insertCampus, campus.ID, school.SchoolID, campus.Name)
+			if err != nil {
+				log.Fatalln(err)
+			}
+		}
+		for _, faculty := range school.Faculties {
+			_, err = tx.Exec(context.Background(), insertFaculty, faculty.ID, school.SchoolID, faculty.Name)
+			if err != nil {
+				log.Fatalln(err)
+			}
+		}
+	}
+	if err = tx.Commit(context.Background()); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func addCoursesToDB() {
+	pool, err := pgxpool.Connect(context.Background(), os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer pool.Close()
+
+	cwd, err := os.Getwd()
+	fatalOnError(err)
+	coursesJSON, err := ioutil.ReadFile(path.Join(cwd, "School", "utils", "courses.json"))
+	fatalOnError(err)
+	var courses []domain.Course
+	if err = json.Unmarshal(coursesJSON, &courses); err != nil {
+		log.Fatalln(err)
+	}
+
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer tx.Rollback(context.Background())
+
+	for _, course := range courses {
+		_, err = tx.Exec(context.Background(), insertCourse, course.ID, course.SchoolID, course.Subject, course.Number,
+			course.Title, course.Term)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if err = tx.Commit(context.Background()); err != nil {
+		log.Fatalln(err)
+	}
+}
+
 func getPathToSchoolsFile(err error) string {
 	cwd, err := os.Getwd()
 	fatalOnError(err)
@@ -1,11 +1,17 @@
 package http
 
 import (
+	"fmt"
 	"github.com/airbenders/profile/domain"
 	"github.com/airbenders/profile/utils/errors"
 	"github.com/airbenders/profile/utils/httputils"
 	"github.com/gin-gonic/gin"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // StudentHandler struct
@@ -20,7 +26,8 @@ func NewStudentHandler(u domain.StudentUseCase) *StudentHandler {
 
 const errorMessage = "id must be provided"
 
-// GetByID returns the student's profile with that ID. If it doesn't exist, returns 404
+// GetByID returns the student's profile with that ID. If it doesn't exist, returns 404.
+// ?fields=first_name,last_name only returns those fields and ?include=reviews,school,reputation loads those relations
 func (h *StudentHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -28,8 +35,12 @@ func (h *StudentHandler) GetByID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(errorMessage))
 		return
 	}
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
 	ctx := c.Request.Context()
-	student, err := h.UseCase.GetByID(ctx, id)
+	query := httputils.ParseProfileQuery(c)
+	student, err := h.UseCase.GetByID(ctx, loggedID, id, query)
 	if err != nil {
 		switch v := err.(type) {
 		case *errors.RestError:
@@ -40,7 +51,12 @@ func (h *StudentHandler) GetByID(c *gin.Context) {
 			return
 		}
 	}
-	c.JSON(200, student)
+	response, err := httputils.SelectFields(student, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+		return
+	}
+	c.JSON(200, response)
 }
 
 // Create is hit when the student first creates his account and is asked to set it up.
@@ -72,13 +88,15 @@ func (h *StudentHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, httputils.NewResponse("student created"))
 }
 
-// Update changes the student record. Ensures the student is the same as logged in, and then makes changes as requested
+// Update changes the student record. Ensures the student is the same as logged in, and then makes changes as requested.
+// ?clear=year,links removes optional profile fields
 func (h *StudentHandler) Update(c *gin.Context) {
 	id, student, err, done := isLoggedIDAuthorized(c)
 	if done {
 		return
 	}
 
+	student.Clear = httputils.QueryList(c, "clear")
 	ctx := c.Request.Context()
 	updatedStudent, err := h.UseCase.Update(ctx, id, &student)
 	if err != nil {
@@ -217,14 +235,286 @@ func isLoggedIDAuthorized(c *gin.Context) (string, domain.Student, error, bool)
 	return id, student, err, false
 }
 
+// SearchStudents returns the students matching the query parameters. ?fields=id,first_name only returns those fields
+// and ?groupBy=school or ?groupBy=country groups the students
 func (h *StudentHandler) SearchStudents(c *gin.Context) {
 	ctx := c.Request.Context()
+	query := domain.ProfileQuery{Fields: httputils.QueryList(c, "fields")}
+	if err := query.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
 	var student domain.Student
 	student.FirstName = c.Query("firstName")
 	student.LastName = c.Query("lastName")
 	student.CurrentClasses = c.QueryArray("classes")
+	student.Program = c.Query("program")
+	student.Languages = c.QueryArray("languages")
+	if c.Query("school") != "" || c.Query("country") != "" {
+		student.School = &domain.School{Name: c.Query("school"), Country: c.Query("country")}
+	}
+	if campusID := c.Query("campus"); campusID != "" {
+		student.Campus = &domain.Campus{ID: campusID}
+	}
+	if facultyID := c.Query("faculty"); facultyID != "" {
+		student.Faculty = &domain.Faculty{ID: facultyID}
+	}
+	groupBy := c.Query("groupBy")
+	if err := domain.ValidateGroupBy(groupBy); groupBy != "" && err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+	if raw := c.Query("year"); raw != "" {
+		var err error
+		student.Year, err = strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError("year must be a number"))
+			return
+		}
+	}
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	students, err := h.UseCase.SearchStudents(ctx, loggedID, &student)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+	if groupBy == "" {
+		response, err := httputils.SelectFields(students, query)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+		c.JSON(200, response)
+		return
+	}
+
+	groups, _ := domain.GroupStudents(students, groupBy)
+	response := make([]gin.H, 0, len(groups))
+	for _, group := range groups {
+		selected, err := httputils.SelectFields(group.Students, query)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+		response = append(response, gin.H{"key": group.Key, "students": selected})
+	}
+	c.JSON(200, response)
+}
+
+// GetEnrollments returns the enrollment history of the logged in student
+func (h *StudentHandler) GetEnrollments(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only view enrollments of self"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	enrollments, err := h.UseCase.GetEnrollments(ctx, id)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, enrollments)
+}
+
+// SaveEnrollments records the term, status and section of classes for the logged in student
+func (h *StudentHandler) SaveEnrollments(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only update for self"))
+		return
+	}
+
+	var enrollments []domain.Enrollment
+	err := c.ShouldBindJSON(&enrollments)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("invalid data"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = h.UseCase.SaveEnrollments(ctx, id, enrollments)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, httputils.NewResponse("enrollments saved"))
+}
+
+// GetClassmatesInTerm returns the students who took a class during a term, e.g. ?class=COMP 354&term=Winter 2026
+func (h *StudentHandler) GetClassmatesInTerm(c *gin.Context) {
+	class := c.Query("class")
+	if class == "" {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("please provide a class"))
+		return
+	}
+	term, err := domain.ParseTerm(c.Query("term"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	ctx := c.Request.Context()
+	students, err := h.UseCase.GetClassmatesInTerm(ctx, loggedID, class, term)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, students)
+}
+
+// GetClassmates returns the students sharing current classes with the logged in student. Paginated with page and limit.
+// sameCampus and sameFaculty keep the ones on the student's campus or in their faculty
+func (h *StudentHandler) GetClassmates(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only find classmates for self"))
+		return
+	}
+
+	page, err := httputils.ParsePage(c, 20, 100)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+	var filter domain.ClassmateFilter
+	filter.SameCampus, err = queryBool(c, "sameCampus")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+	filter.SameFaculty, err = queryBool(c, "sameFaculty")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	classmates, err := h.UseCase.GetClassmates(ctx, id, filter, page)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, classmates)
+}
+
+// GetPrivacy returns the privacy settings of the logged in student
+func (h *StudentHandler) GetPrivacy(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only view privacy settings of self"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	privacy, err := h.UseCase.GetPrivacy(ctx, id)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, privacy)
+}
+
+// UpdatePrivacy replaces the privacy settings of the logged in student
+func (h *StudentHandler) UpdatePrivacy(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only update for self"))
+		return
+	}
+
+	var privacy domain.Privacy
+	err := c.ShouldBindJSON(&privacy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("invalid data"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = h.UseCase.UpdatePrivacy(ctx, id, &privacy)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, privacy)
+}
 
-	students, err := h.UseCase.SearchStudents(ctx, &student)
+// GetBlocks returns the students blocked and muted by the logged in student
+func (h *StudentHandler) GetBlocks(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only view blocks of self"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	blocks, err := h.UseCase.GetBlocks(ctx, id)
 	if err != nil {
 		switch v := err.(type) {
 		case *errors.RestError:
@@ -235,5 +525,310 @@ func (h *StudentHandler) SearchStudents(c *gin.Context) {
 			return
 		}
 	}
-	c.JSON(200, students)
+
+	c.JSON(http.StatusOK, blocks)
+}
+
+// Block stops the target from seeing, searching or reviewing the logged in student
+func (h *StudentHandler) Block(c *gin.Context) {
+	h.changeBlock(c, domain.Blocking, true, "student blocked")
+}
+
+// Unblock removes a block made by the logged in student
+func (h *StudentHandler) Unblock(c *gin.Context) {
+	h.changeBlock(c, domain.Blocking, false, "student unblocked")
+}
+
+// Mute hides the target from the logged in student's searches and recommendations
+func (h *StudentHandler) Mute(c *gin.Context) {
+	h.changeBlock(c, domain.Muting, true, "student muted")
+}
+
+// Unmute removes a mute made by the logged in student
+func (h *StudentHandler) Unmute(c *gin.Context) {
+	h.changeBlock(c, domain.Muting, false, "student unmuted")
+}
+
+func (h *StudentHandler) changeBlock(c *gin.Context, kind domain.BlockKind, add bool, message string) {
+	id := c.Param("id")
+	target := c.Param("target")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only update for self"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	var err error
+	if add {
+		err = h.UseCase.Block(ctx, id, target, kind)
+	} else {
+		err = h.UseCase.Unblock(ctx, id, target, kind)
+	}
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, httputils.NewResponse(message))
+}
+
+// GetAvailability returns the weekly availability of the student with that ID
+func (h *StudentHandler) GetAvailability(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	ctx := c.Request.Context()
+	availability, err := h.UseCase.GetAvailability(ctx, loggedID, id)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// UpdateAvailability replaces the weekly availability and exceptions of the logged in student
+func (h *StudentHandler) UpdateAvailability(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only update for self"))
+		return
+	}
+
+	var availability domain.Availability
+	err := c.ShouldBindJSON(&availability)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("invalid data"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = h.UseCase.UpdateAvailability(ctx, id, &availability)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// GetCommonAvailability returns when all the students are free, e.g. ?students=a&students=b&from=...&to=...
+// from and to are RFC 3339 times and default to the next 7 days
+func (h *StudentHandler) GetCommonAvailability(c *gin.Context) {
+	from := time.Now().UTC().Truncate(time.Minute)
+	if raw := c.Query("from"); raw != "" {
+		var err error
+		from, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError("from must be a time like 2026-09-14T09:00:00Z"))
+			return
+		}
+	}
+	to := from.AddDate(0, 0, 7)
+	if raw := c.Query("to"); raw != "" {
+		var err error
+		to, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errors.NewBadRequestError("to must be a time like 2026-09-21T09:00:00Z"))
+			return
+		}
+	}
+
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	ctx := c.Request.Context()
+	intervals, err := h.UseCase.GetCommonAvailability(ctx, loggedID, c.QueryArray("students"), from, to)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, intervals)
+}
+
+// UpdateAvatar sets the profile picture of the logged in student. The image is either the whole body or the "avatar"
+// file of a multipart form
+func (h *StudentHandler) UpdateAvatar(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only update for self"))
+		return
+	}
+
+	image, err := readAvatar(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	avatar, err := h.UseCase.UpdateAvatar(ctx, id, image)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, avatar)
+}
+
+// readAvatar reads the uploaded image, up to a bit more than the largest avatar so the use case can reject it
+func readAvatar(c *gin.Context) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, domain.MaxAvatarSize+1<<20)
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		header, err := c.FormFile("avatar")
+		if err != nil {
+			return nil, fmt.Errorf("expected the image in the avatar field of the form")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+	image, err := ioutil.ReadAll(io.LimitReader(reader, domain.MaxAvatarSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("the image is too large or couldn't be read")
+	}
+	return image, nil
+}
+
+// DeleteAvatar removes the profile picture of the logged in student
+func (h *StudentHandler) DeleteAvatar(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only update for self"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	err := h.UseCase.DeleteAvatar(ctx, id)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, httputils.NewResponse("avatar deleted"))
+}
+
+// GetOnboarding returns how complete the profile of the logged in student is and the steps left
+func (h *StudentHandler) GetOnboarding(c *gin.Context) {
+	id := c.Param("id")
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+	if loggedID != id {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("Can only view onboarding of self"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	onboarding, err := h.UseCase.GetOnboarding(ctx, id)
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, onboarding)
+}
+
+// batchGetMethod is the custom method of POST /students:batchGet. gin reads it as the "method" parameter
+const batchGetMethod = ":batchGet"
+
+// BatchGet returns summaries of the students with the IDs of the body, for services that need many at once.
+// ?include=reviews also returns their reviews
+func (h *StudentHandler) BatchGet(c *gin.Context) {
+	if c.Param("method") != batchGetMethod {
+		c.JSON(http.StatusNotFound, errors.NewNotFoundError("page not found"))
+		return
+	}
+
+	var request domain.BatchGetRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewBadRequestError("invalid data"))
+		return
+	}
+	key, _ := c.Get("loggedID")
+	loggedID, _ := key.(string)
+
+	ctx := c.Request.Context()
+	result, err := h.UseCase.BatchGet(ctx, loggedID, request.IDs, httputils.QueryList(c, "include"))
+	if err != nil {
+		switch v := err.(type) {
+		case *errors.RestError:
+			c.JSON(v.Code, v)
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// queryBool returns the boolean query parameter, false if it isn't given
+func queryBool(c *gin.Context, key string) (bool, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", key)
+	}
+	return value, nil
 }
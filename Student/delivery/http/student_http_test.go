@@ -1,6 +1,7 @@
 package http_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,9 +15,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 const failureMessage = "failed to read from message"
@@ -30,7 +34,7 @@ func TestStudentHandlerGetByID(t *testing.T) {
 	h := http.NewStudentHandler(mockUseCase)
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(h, nil, nil, nil, mw, mw, parser)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
 	server := httptest.NewServer(r)
 	defer server.Close()
 
@@ -39,7 +43,8 @@ func TestStudentHandlerGetByID(t *testing.T) {
 	assert.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
-		mockUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("string")).Return(&mockStudent, nil).Once()
+		withReviews := domain.ProfileQuery{Include: []string{domain.IncludeReviews}}
+		mockUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), withReviews).Return(&mockStudent, nil).Once()
 
 		response, err := server.Client().Get(fmt.Sprintf(getStudentPath, server.URL, mockStudent.ID))
 		assert.NoError(t, err)
@@ -60,8 +65,26 @@ func TestStudentHandlerGetByID(t *testing.T) {
 		mockUseCase.AssertExpectations(t)
 	})
 
+	t.Run("success-with-fields-and-include", func(t *testing.T) {
+		reputation := 4
+		student := &domain.Student{ID: "abc", FirstName: "Ada", LastName: "Lovelace", Reputation: &reputation}
+		query := domain.ProfileQuery{Fields: []string{"first_name"}, Include: []string{domain.IncludeReputation}}
+		mockUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("string"), "abc", query).Return(student, nil).Once()
+
+		response, err := server.Client().Get(fmt.Sprintf(getStudentPath, server.URL, "abc") +
+			"?fields=first_name&include=reputation")
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, 200, response.StatusCode)
+		responseBody, err := ioutil.ReadAll(response.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"first_name":"Ada","reputation":4}`, string(responseBody))
+		mockUseCase.AssertExpectations(t)
+	})
+
 	t.Run("not-found", func(t *testing.T) {
-		mockUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("string")).
+		mockUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).
 			Return(nil, e.NewNotFoundError("student not found")).Once()
 
 		response, err := server.Client().Get(fmt.Sprintf(getStudentPath, server.URL, mockStudent.ID))
@@ -82,7 +105,7 @@ func TestStudentHandlerGetByID(t *testing.T) {
 
 	t.Run("some-internal-error", func(t *testing.T) {
 		defaultErr := errors.New("some error occurred")
-		mockUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("string")).
+		mockUseCase.On("GetByID", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).
 			Return(nil, defaultErr).Once()
 
 		response, err := server.Client().Get(fmt.Sprintf(getStudentPath, server.URL, "asd"))
@@ -121,7 +144,7 @@ func TestStudentHandlerCreate(t *testing.T) {
 	h := &http.StudentHandler{UseCase: mockUseCase}
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(h, nil, nil, nil, mw, mw, parser)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
 	server := httptest.NewServer(r)
 	defer server.Close()
 	var mockStudent domain.Student
@@ -214,7 +237,7 @@ func TestStudentHandlerUpdate(t *testing.T) {
 	h := &http.StudentHandler{UseCase: mockUseCase}
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(h, nil, nil, nil, mw, mw, parser)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
 	var mockStudent domain.Student
 	err := faker.FakeData(&mockStudent)
 	assert.NoError(t, err)
@@ -233,6 +256,19 @@ func TestStudentHandlerUpdate(t *testing.T) {
 		mockUseCase.AssertExpectations(t)
 	})
 
+	t.Run("clear", func(t *testing.T) {
+		mockUseCase.On("Update", mock.Anything, mockStudent.ID, mock.MatchedBy(func(st *domain.Student) bool {
+			return reflect.DeepEqual(st.Clear, []string{domain.FieldYear, domain.FieldLinks})
+		})).Return(&mockStudent, nil).Once()
+		reader := strings.NewReader("{}")
+		reqFound := httptest.NewRequest("PUT", fmt.Sprintf(putStudentPath, mockStudent.ID)+"?clear=year,links", reader)
+		reqFound.Header.Set("id", mockStudent.ID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
 	t.Run("invalid-data-type", func(t *testing.T) {
 		reader := strings.NewReader("invalid body")
 		reqFound := httptest.NewRequest("PUT", fmt.Sprintf(putStudentPath, mockStudent.ID), reader)
@@ -277,7 +313,7 @@ func TestStudentHandlerDelete(t *testing.T) {
 	h := &http.StudentHandler{UseCase: mockUseCase}
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(h, nil, nil, nil, mw, mw, parser)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
 
 	t.Run("success", func(t *testing.T) {
 		mockUseCase.On("Delete", mock.Anything, mock.AnythingOfType("string")).
@@ -326,7 +362,7 @@ func TestStudentHandlerAddClasses(t *testing.T) {
 	h := &http.StudentHandler{UseCase: mockUseCase}
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(h, nil, nil, nil, mw, mw, parser)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
 	var mockStudent domain.Student
 	err := faker.FakeData(&mockStudent)
 	assert.NoError(t, err)
@@ -389,7 +425,7 @@ func TestStudentHandlerRemoveClassesTaken(t *testing.T) {
 	h := &http.StudentHandler{UseCase: mockUseCase}
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(h, nil, nil, nil, mw, mw, parser)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
 	var mockStudent domain.Student
 	err := faker.FakeData(&mockStudent)
 	assert.NoError(t, err)
@@ -452,7 +488,7 @@ func TestStudentHandlerCompleteClass(t *testing.T) {
 	h := &http.StudentHandler{UseCase: mockUseCase}
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(h, nil, nil, nil, mw, mw, parser)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
 	var mockStudent domain.Student
 	err := faker.FakeData(&mockStudent)
 	assert.NoError(t, err)
@@ -515,13 +551,13 @@ func TestStudentHandlerSearchStudents(t *testing.T) {
 	h := http.NewStudentHandler(mockUseCase)
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	r := app.Server(h, nil, nil, nil, mw, mw, parser)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
 	var mockRetrievedStudents []domain.Student
 	err := faker.FakeData(&mockRetrievedStudents)
 	assert.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
-		mockUseCase.On("SearchStudents", mock.Anything, mock.Anything).
+		mockUseCase.On("SearchStudents", mock.Anything, mock.Anything, mock.Anything).
 			Return(mockRetrievedStudents, nil).Once()
 		reqFound := httptest.NewRequest("GET", "/api/search/?firstName=Test&lastname=Smith&classes=class", nil)
 
@@ -532,9 +568,55 @@ func TestStudentHandlerSearchStudents(t *testing.T) {
 
 	})
 
+	t.Run("success-with-fields", func(t *testing.T) {
+		students := []domain.Student{{ID: "a", FirstName: "Ada", LastName: "Lovelace"}}
+		mockUseCase.On("SearchStudents", mock.Anything, mock.Anything, mock.Anything).Return(students, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/search/?firstName=Ada&fields=id,first_name", nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		assert.JSONEq(t, `[{"id":"a","first_name":"Ada"}]`, w.Body.String())
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("success-grouped-by-school", func(t *testing.T) {
+		school := &domain.School{ID: "c", Name: "Concordia University", Country: "Canada"}
+		students := []domain.Student{{ID: "a", School: school}, {ID: "b", School: school}, {ID: "c"}}
+		filters := func(st *domain.Student) bool {
+			return st.School != nil && st.School.Name == "concordia" && st.School.Country == "canada"
+		}
+		mockUseCase.On("SearchStudents", mock.Anything, mock.Anything, mock.MatchedBy(filters)).
+			Return(students, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/search/?school=concordia&country=canada&groupBy=school&fields=id", nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		assert.JSONEq(t, `[{"key":"Concordia University","students":[{"id":"a"},{"id":"b"}]},
+			{"key":"","students":[{"id":"c"}]}]`, w.Body.String())
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("unknown-grouping", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/search/?groupBy=program", nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("unknown-field", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/search/?firstName=Ada&fields=password", nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+
 	t.Run("rest error", func(t *testing.T) {
 		restErr := e.NewConflictError("error occurred")
-		mockUseCase.On("SearchStudents", mock.Anything, mock.Anything).
+		mockUseCase.On("SearchStudents", mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, restErr).Once()
 
 		reqFound := httptest.NewRequest("GET", "/api/search/?firstName=Test&lastname=Smith&classes=class", nil)
@@ -546,7 +628,7 @@ func TestStudentHandlerSearchStudents(t *testing.T) {
 	})
 
 	t.Run("usecase-default-error", func(t *testing.T) {
-		mockUseCase.On("SearchStudents", mock.Anything, mock.Anything).
+		mockUseCase.On("SearchStudents", mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, errors.New("internal error")).Once()
 
 		reqFound := httptest.NewRequest("GET", "/api/search/?firstName=Test&lastname=Smith&classes=class", nil)
@@ -557,4 +639,515 @@ func TestStudentHandlerSearchStudents(t *testing.T) {
 		mockUseCase.AssertExpectations(t)
 	})
 
+	t.Run("profile-filters", func(t *testing.T) {
+		filters := &domain.Student{FirstName: "Test", CurrentClasses: []string{}, Program: "software", Year: 2,
+			Languages: []string{"French", "Arabic"}}
+		mockUseCase.On("SearchStudents", mock.Anything, "abc", filters).Return(mockRetrievedStudents, nil).Once()
+
+		reqFound := httptest.NewRequest("GET",
+			"/api/search/?firstName=Test&program=software&year=2&languages=French&languages=Arabic", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-year", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/search/?year=second", nil)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+}
+
+func TestStudentHandlerEnrollments(t *testing.T) {
+	mockUseCase := new(mocks.StudentUseCase)
+	h := http.NewStudentHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
+	enrollments := []domain.Enrollment{{Class: "COMP 354", Term: domain.Term{Season: domain.Winter, Year: 2026},
+		Status: domain.Completed, Section: "PP"}}
+
+	t.Run("save-success", func(t *testing.T) {
+		mockUseCase.On("SaveEnrollments", mock.Anything, "abc", enrollments).Return(nil).Once()
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/enrollments",
+			strings.NewReader(`[{"class":"COMP 354","term":"Winter 2026","status":"completed","section":"PP"}]`))
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("save-invalid-term", func(t *testing.T) {
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/enrollments",
+			strings.NewReader(`[{"class":"COMP 354","term":"Spring 2026"}]`))
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("get-for-someone-else", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/enrollments", nil)
+		reqFound.Header.Set("id", "def")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 401, w.Code)
+	})
+
+	t.Run("get-success", func(t *testing.T) {
+		mockUseCase.On("GetEnrollments", mock.Anything, "abc").Return(enrollments, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/enrollments", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		assert.Contains(t, w.Body.String(), `"term":"Winter 2026"`)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestStudentHandlerGetClassmatesInTerm(t *testing.T) {
+	mockUseCase := new(mocks.StudentUseCase)
+	h := http.NewStudentHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
+	term := domain.Term{Season: domain.Winter, Year: 2026}
+
+	t.Run("success", func(t *testing.T) {
+		mockUseCase.On("GetClassmatesInTerm", mock.Anything, "abc", "COMP 354", term).
+			Return([]domain.Student{{ID: "def"}}, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/classmates/history?class=COMP+354&term=Winter+2026", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-term", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/v1/classmates/history?class=COMP+354&term=2026", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("usecase-rest-error", func(t *testing.T) {
+		restErr := e.NewBadRequestError("confirm your school to find classmates")
+		mockUseCase.On("GetClassmatesInTerm", mock.Anything, "abc", "COMP 354", term).
+			Return(nil, restErr).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/classmates/history?class=COMP+354&term=Winter+2026", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, restErr.Code, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestStudentHandlerGetClassmates(t *testing.T) {
+	mockUseCase := new(mocks.StudentUseCase)
+	h := http.NewStudentHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
+
+	t.Run("success", func(t *testing.T) {
+		classmates := []domain.Classmate{{Student: domain.Student{ID: "def"}, SharedClasses: []string{"COMP 354"}, Reputation: 2}}
+		mockUseCase.On("GetClassmates", mock.Anything, "abc", domain.ClassmateFilter{}, domain.Page{Number: 2, Size: 5}).
+			Return(classmates, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/classmates?page=2&limit=5", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		var received []domain.Classmate
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &received))
+		assert.Equal(t, 2, received[0].Reputation)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("same-campus-and-faculty", func(t *testing.T) {
+		filter := domain.ClassmateFilter{SameCampus: true, SameFaculty: true}
+		mockUseCase.On("GetClassmates", mock.Anything, "abc", filter, domain.Page{Number: 1, Size: 20}).
+			Return([]domain.Classmate{}, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/classmates?sameCampus=true&sameFaculty=1", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-same-campus", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/classmates?sameCampus=yes", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("invalid-limit", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/classmates?limit=1000", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("not-self", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/classmates", nil)
+		reqFound.Header.Set("id", "def")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 401, w.Code)
+	})
+}
+
+func TestStudentHandlerPrivacy(t *testing.T) {
+	mockUseCase := new(mocks.StudentUseCase)
+	h := http.NewStudentHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
+
+	t.Run("update-success", func(t *testing.T) {
+		mockUseCase.On("UpdatePrivacy", mock.Anything, "abc", &domain.Privacy{HideFromDiscovery: true}).
+			Return(nil).Once()
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/privacy", strings.NewReader(`{"hide_from_discovery":true}`))
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("get-success", func(t *testing.T) {
+		mockUseCase.On("GetPrivacy", mock.Anything, "abc").Return(&domain.Privacy{}, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/privacy", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestStudentHandlerBlocks(t *testing.T) {
+	mockUseCase := new(mocks.StudentUseCase)
+	h := http.NewStudentHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
+
+	t.Run("block-success", func(t *testing.T) {
+		mockUseCase.On("Block", mock.Anything, "abc", "def", domain.Blocking).Return(nil).Once()
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/blocks/def", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("unmute-success", func(t *testing.T) {
+		mockUseCase.On("Unblock", mock.Anything, "abc", "def", domain.Muting).Return(nil).Once()
+		reqFound := httptest.NewRequest("DELETE", "/api/v1/student/abc/mutes/def", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("block-usecase-rest-error", func(t *testing.T) {
+		restErr := e.NewNotFoundError("not found")
+		mockUseCase.On("Block", mock.Anything, "abc", "def", domain.Muting).Return(restErr).Once()
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/mutes/def", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, restErr.Code, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("block-not-self", func(t *testing.T) {
+		reqFound := httptest.NewRequest("DELETE", "/api/v1/student/abc/blocks/def", nil)
+		reqFound.Header.Set("id", "def")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 401, w.Code)
+	})
+
+	t.Run("get-success", func(t *testing.T) {
+		blocks := []domain.Block{{BlockerID: "abc", BlockedID: "def", Kind: domain.Blocking}}
+		mockUseCase.On("GetBlocks", mock.Anything, "abc").Return(blocks, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/blocks", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		var received []domain.Block
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &received))
+		assert.Equal(t, blocks[0].BlockedID, received[0].BlockedID)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("get-error", func(t *testing.T) {
+		mockUseCase.On("GetBlocks", mock.Anything, "abc").Return(nil, errors.New("error")).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/blocks", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 500, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestStudentHandlerAvailability(t *testing.T) {
+	mockUseCase := new(mocks.StudentUseCase)
+	h := http.NewStudentHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
+
+	t.Run("get-success", func(t *testing.T) {
+		availability := &domain.Availability{StudentID: "def", TimeZone: "UTC",
+			Weekly: []domain.WeeklySlot{{Day: time.Monday, Start: 9 * 60, End: 12 * 60}}}
+		mockUseCase.On("GetAvailability", mock.Anything, "abc", "def").Return(availability, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/def/availability", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		assert.Contains(t, w.Body.String(), `"start":"09:00"`)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("update-success", func(t *testing.T) {
+		expected := &domain.Availability{TimeZone: "America/Toronto",
+			Weekly: []domain.WeeklySlot{{Day: time.Friday, Start: 13*60 + 30, End: 24 * 60}}}
+		mockUseCase.On("UpdateAvailability", mock.Anything, "abc", expected).Return(nil).Once()
+		body := `{"time_zone":"America/Toronto","weekly":[{"day":5,"start":"13:30","end":"24:00"}]}`
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/availability", strings.NewReader(body))
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("update-invalid-time", func(t *testing.T) {
+		body := `{"weekly":[{"day":5,"start":"1:30pm","end":"24:00"}]}`
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/availability", strings.NewReader(body))
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("update-not-self", func(t *testing.T) {
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/availability", strings.NewReader(`{}`))
+		reqFound.Header.Set("id", "def")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 401, w.Code)
+	})
+
+	t.Run("common-success", func(t *testing.T) {
+		from := time.Date(2026, 9, 14, 9, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 0, 7)
+		intervals := []domain.Interval{{Start: from, End: from.Add(time.Hour)}}
+		mockUseCase.On("GetCommonAvailability", mock.Anything, "abc", []string{"abc", "def"}, from, to).
+			Return(intervals, nil).Once()
+		reqFound := httptest.NewRequest("GET",
+			"/api/v1/availability/common?students=abc&students=def&from=2026-09-14T09:00:00Z", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("common-invalid-from", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/v1/availability/common?students=abc&from=tomorrow", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+}
+
+func TestStudentHandlerAvatar(t *testing.T) {
+	mockUseCase := new(mocks.StudentUseCase)
+	h := http.NewStudentHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
+	image := []byte("\x89PNG\r\n\x1a\nimage")
+	avatar := &domain.Avatar{URL: "http://cdn/avatars/abc/1/original.png"}
+
+	t.Run("update-raw-body", func(t *testing.T) {
+		mockUseCase.On("UpdateAvatar", mock.Anything, "abc", image).Return(avatar, nil).Once()
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/avatar", bytes.NewReader(image))
+		reqFound.Header.Set("Content-Type", "image/png")
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		assert.Contains(t, w.Body.String(), avatar.URL)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("update-multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("avatar", "me.png")
+		part.Write(image)
+		form.Close()
+		mockUseCase.On("UpdateAvatar", mock.Anything, "abc", image).Return(avatar, nil).Once()
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/avatar", &body)
+		reqFound.Header.Set("Content-Type", form.FormDataContentType())
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("update-multipart-missing-field", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		form.WriteField("name", "me")
+		form.Close()
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/avatar", &body)
+		reqFound.Header.Set("Content-Type", form.FormDataContentType())
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("update-rest-error", func(t *testing.T) {
+		restErr := e.NewBadRequestError("unsupported image type")
+		mockUseCase.On("UpdateAvatar", mock.Anything, "abc", mock.Anything).Return(nil, restErr).Once()
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/avatar", strings.NewReader("GIF89a"))
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("update-not-self", func(t *testing.T) {
+		reqFound := httptest.NewRequest("PUT", "/api/v1/student/abc/avatar", bytes.NewReader(image))
+		reqFound.Header.Set("id", "def")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 401, w.Code)
+	})
+
+	t.Run("delete-success", func(t *testing.T) {
+		mockUseCase.On("DeleteAvatar", mock.Anything, "abc").Return(nil).Once()
+		reqFound := httptest.NewRequest("DELETE", "/api/v1/student/abc/avatar", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestStudentHandlerOnboarding(t *testing.T) {
+	mockUseCase := new(mocks.StudentUseCase)
+	h := http.NewStudentHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
+
+	t.Run("success", func(t *testing.T) {
+		onboarding := domain.NewOnboarding(&domain.Student{ID: "abc"}, nil)
+		mockUseCase.On("GetOnboarding", mock.Anything, "abc").Return(onboarding, nil).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/onboarding", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		assert.Contains(t, w.Body.String(), `"score":0`)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("not-self", func(t *testing.T) {
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/onboarding", nil)
+		reqFound.Header.Set("id", "def")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 401, w.Code)
+	})
+
+	t.Run("usecase-error", func(t *testing.T) {
+		mockUseCase.On("GetOnboarding", mock.Anything, "abc").Return(nil, e.NewNotFoundError("not found")).Once()
+		reqFound := httptest.NewRequest("GET", "/api/v1/student/abc/onboarding", nil)
+		reqFound.Header.Set("id", "abc")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 404, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestStudentHandlerBatchGet(t *testing.T) {
+	mockUseCase := new(mocks.StudentUseCase)
+	h := http.NewStudentHandler(mockUseCase)
+	mw := new(mocks.MiddlewareMock)
+	parser := new(mocks.ClaimsParserMock)
+	r := app.Server(h, nil, nil, nil, nil, nil, mw, mw, parser)
+
+	t.Run("success", func(t *testing.T) {
+		result := &domain.BatchGetResult{
+			Students: []domain.StudentSummary{{ID: "a", FirstName: "Ada"}},
+			Missing:  []string{"b"},
+		}
+		mockUseCase.On("BatchGet", mock.Anything, "viewer", []string{"a", "b"}, []string{"reviews"}).
+			Return(result, nil).Once()
+		reqFound := httptest.NewRequest("POST", "/api/v1/students:batchGet?include=reviews",
+			strings.NewReader(`{"ids":["a","b"]}`))
+		reqFound.Header.Set("id", "viewer")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 200, w.Code)
+		assert.Contains(t, w.Body.String(), `"missing":["b"]`)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("unknown-method", func(t *testing.T) {
+		reqFound := httptest.NewRequest("POST", "/api/v1/students:batchDelete", strings.NewReader(`{"ids":["a"]}`))
+		reqFound.Header.Set("id", "viewer")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 404, w.Code)
+	})
+
+	t.Run("invalid-body", func(t *testing.T) {
+		reqFound := httptest.NewRequest("POST", "/api/v1/students:batchGet", strings.NewReader(`{"ids":"a"}`))
+		reqFound.Header.Set("id", "viewer")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("usecase-error", func(t *testing.T) {
+		mockUseCase.On("BatchGet", mock.Anything, "viewer", []string{}, []string(nil)).
+			Return(nil, e.NewBadRequestError("no ids")).Once()
+		reqFound := httptest.NewRequest("POST", "/api/v1/students:batchGet", strings.NewReader(`{"ids":[]}`))
+		reqFound.Header.Set("id", "viewer")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, reqFound)
+		assert.Equal(t, 400, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+)
+
+const (
+	selectAvailabilities = `SELECT st_id, time_zone, weekly, exceptions, updated_at FROM public.availability
+	WHERE st_id = ANY($1) ORDER BY st_id;`
+	upsertAvailability = `INSERT INTO public.availability (st_id, time_zone, weekly, exceptions, updated_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (st_id) DO UPDATE SET time_zone=EXCLUDED.time_zone, weekly=EXCLUDED.weekly,
+	exceptions=EXCLUDED.exceptions, updated_at=EXCLUDED.updated_at;`
+)
+
+// GetAvailabilities returns the availability of the students who have one
+func (r *studentRepository) GetAvailabilities(ctx context.Context, ids []string) ([]domain.Availability, error) {
+	rows, err := r.db.Query(ctx, selectAvailabilities, ids)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	availabilities := []domain.Availability{}
+	for rows.Next() {
+		var availability domain.Availability
+		err = rows.Scan(&availability.StudentID, &availability.TimeZone, &availability.Weekly, &availability.Exceptions,
+			&availability.UpdatedAt)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		availabilities = append(availabilities, availability)
+	}
+	return availabilities, nil
+}
+
+// SaveAvailability creates or replaces the availability of the student
+func (r *studentRepository) SaveAvailability(ctx context.Context, availability *domain.Availability) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, upsertAvailability, availability.StudentID, availability.TimeZone, availability.Weekly,
+		availability.Exceptions, availability.UpdatedAt)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/airbenders/profile/Student/repository"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/pgxmocks"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestAvailability(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+	columns := []string{"st_id", "time_zone", "weekly", "exceptions", "updated_at"}
+
+	t.Run("get-success", func(t *testing.T) {
+		now := time.Now()
+		weekly := []domain.WeeklySlot{{Day: time.Monday, Start: 60, End: 120}}
+		expected := []domain.Availability{{StudentID: "a", TimeZone: "UTC", Weekly: weekly,
+			Exceptions: []domain.AvailabilityException{}, UpdatedAt: now}}
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow("a", "UTC", weekly, []domain.AvailabilityException{}, now).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), []string{"a", "b"}).Return(pgxRows, nil)
+
+		sr := repository.NewStudentRepository(mockPool)
+		availabilities, err := sr.GetAvailabilities(context.Background(), []string{"a", "b"})
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, availabilities)
+	})
+
+	t.Run("get-query-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("err"))
+
+		sr := repository.NewStudentRepository(mockPool)
+		availabilities, err := sr.GetAvailabilities(context.Background(), []string{"a"})
+
+		assert.Error(t, err)
+		assert.Nil(t, availabilities)
+	})
+
+	t.Run("save-success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.SaveAvailability(context.Background(), &domain.Availability{StudentID: "a", TimeZone: "UTC"})
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("save-exec-err", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("err")).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.SaveAvailability(context.Background(), &domain.Availability{StudentID: "a", TimeZone: "UTC"})
+
+		assert.Error(t, err)
+		txMock.AssertExpectations(t)
+	})
+}
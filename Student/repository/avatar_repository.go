@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+)
+
+const updateAvatar = `UPDATE public.student SET avatar=$1, updated_at=now() WHERE id=$2;`
+
+// UpdateAvatar replaces the avatar of the student. A nil avatar removes it
+func (r *studentRepository) UpdateAvatar(ctx context.Context, id string, avatar *domain.Avatar) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, updateAvatar, avatar, id)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/airbenders/profile/Student/repository"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/pgxmocks"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestUpdateAvatar(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+	avatar := &domain.Avatar{URL: "http://localhost/blobs/avatars/a/original.png", ContentType: "image/png"}
+
+	t.Run("success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{avatar, "a"}).
+			Return(pgconn.CommandTag{}, nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.UpdateAvatar(context.Background(), "a", avatar)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("exec-err", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("err")).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.UpdateAvatar(context.Background(), "a", nil)
+
+		assert.Error(t, err)
+		txMock.AssertExpectations(t)
+	})
+}
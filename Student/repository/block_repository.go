@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+)
+
+// HiddenFrom filters out the students s that shouldn't be shown to the viewer, the query parameter of the placeholder:
+// the ones who blocked the viewer and the ones the viewer blocked or muted
+func HiddenFrom(viewer string) string {
+	return `NOT EXISTS (SELECT 1 FROM public.student_block b
+	WHERE (b.blocker = s.id AND b.blocked = ` + viewer + ` AND b.kind = 'block') OR (b.blocker = ` + viewer + ` AND b.blocked = s.id))`
+}
+
+const (
+	insertBlock = `INSERT INTO public.student_block (blocker, blocked, kind, created_at) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (blocker, blocked, kind) DO NOTHING;`
+	deleteBlock  = `DELETE FROM public.student_block WHERE blocker=$1 AND blocked=$2 AND kind=$3;`
+	selectBlocks = `SELECT blocker, blocked, kind, created_at FROM public.student_block WHERE blocker=$1
+	ORDER BY created_at DESC;`
+	selectIsBlocked = `SELECT EXISTS (SELECT 1 FROM public.student_block WHERE kind='block'
+	AND ((blocker=$1 AND blocked=$2) OR (blocker=$2 AND blocked=$1)));`
+)
+
+// SaveBlock stores the block. Blocking someone twice keeps the first block
+func (r *studentRepository) SaveBlock(ctx context.Context, block *domain.Block) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, insertBlock, block.BlockerID, block.BlockedID, block.Kind, block.CreatedAt)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// DeleteBlock removes the block if there is one
+func (r *studentRepository) DeleteBlock(ctx context.Context, blockerID string, blockedID string, kind domain.BlockKind) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, deleteBlock, blockerID, blockedID, kind)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// GetBlocks returns the blocks and mutes made by the student, latest first
+func (r *studentRepository) GetBlocks(ctx context.Context, blockerID string) ([]domain.Block, error) {
+	rows, err := r.db.Query(ctx, selectBlocks, blockerID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	blocks := []domain.Block{}
+	for rows.Next() {
+		var block domain.Block
+		err = rows.Scan(&block.BlockerID, &block.BlockedID, &block.Kind, &block.CreatedAt)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// IsBlocked is true if either student blocked the other one. Mutes don't count
+func (r *studentRepository) IsBlocked(ctx context.Context, id string, otherID string) (bool, error) {
+	rows, err := r.db.Query(ctx, selectIsBlocked, id, otherID)
+	if err != nil {
+		return false, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	blocked := false
+	for rows.Next() {
+		err = rows.Scan(&blocked)
+		if err != nil {
+			return false, errors.NewInternalServerError(err.Error())
+		}
+	}
+	return blocked, nil
+}
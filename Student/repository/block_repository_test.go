@@ -0,0 +1,99 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/airbenders/profile/Student/repository"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/pgxmocks"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestBlocks(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+
+	t.Run("save-success", func(t *testing.T) {
+		block := &domain.Block{BlockerID: "a", BlockedID: "b", Kind: domain.Blocking, CreatedAt: time.Now()}
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"a", "b", domain.Blocking, block.CreatedAt}).
+			Return(pgconn.CommandTag{}, nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.SaveBlock(context.Background(), block)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("delete-success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{"a", "b", domain.Muting}).
+			Return(pgconn.CommandTag{}, nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.DeleteBlock(context.Background(), "a", "b", domain.Muting)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("save-can't-begin-transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("err"))
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.SaveBlock(context.Background(), &domain.Block{})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("get-success", func(t *testing.T) {
+		now := time.Now()
+		expected := []domain.Block{{BlockerID: "a", BlockedID: "b", Kind: domain.Muting, CreatedAt: now}}
+		pgxRows := pgxpoolmock.NewRows([]string{"blocker", "blocked", "kind", "created_at"}).
+			AddRow("a", "b", domain.Muting, now).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a").Return(pgxRows, nil)
+
+		sr := repository.NewStudentRepository(mockPool)
+		blocks, err := sr.GetBlocks(context.Background(), "a")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, blocks)
+	})
+
+	t.Run("is-blocked", func(t *testing.T) {
+		pgxRows := pgxpoolmock.NewRows([]string{"exists"}).AddRow(true).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a", "b").Return(pgxRows, nil)
+
+		sr := repository.NewStudentRepository(mockPool)
+		blocked, err := sr.IsBlocked(context.Background(), "a", "b")
+
+		assert.NoError(t, err)
+		assert.True(t, blocked)
+	})
+
+	t.Run("is-blocked-query-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a", "b").Return(nil, errors.New("err"))
+
+		sr := repository.NewStudentRepository(mockPool)
+		blocked, err := sr.IsBlocked(context.Background(), "a", "b")
+
+		assert.Error(t, err)
+		assert.False(t, blocked)
+	})
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+)
+
+const (
+	// Discoverable keeps the students s who didn't hide from discovery in their privacy settings
+	Discoverable = `NOT COALESCE((s.privacy->>'hide_from_discovery')::boolean, false)`
+	// reputation is the number of positive tags a student received in reviews minus the negative ones
+	reputation = `COALESCE((SELECT SUM(CASE WHEN t.positive THEN 1 ELSE -1 END) FROM public.review r
+	JOIN public.review_tag rt ON rt.review_id = r.id JOIN public.tag t ON t.name = rt.tag_name
+	WHERE r.reviewed = s.id), 0)`
+	selectReputation = `SELECT ` + reputation + ` FROM public.student s WHERE s.id=$1;`
+	selectPrivacy    = `SELECT privacy FROM public.student WHERE id=$1;`
+	updatePrivacy    = `UPDATE public.student SET privacy=$1, updated_at=now() WHERE id=$2;`
+)
+
+var (
+	selectClassmates = `SELECT ` + StudentColumns + `, s.shared, s.reputation FROM (
+		SELECT s.*, ARRAY(SELECT unnest(s.current_classes) INTERSECT SELECT unnest($2::text[]) ORDER BY 1) AS shared,
+		` + reputation + ` AS reputation
+		FROM public.student s
+		WHERE s.school=$1 AND s.id <> $3 AND s.current_classes && $2::text[]
+		AND ($4::text = '' OR (s.campus = $4 AND NOT COALESCE(s.privacy->'hidden_fields' @> '["campus"]', false)))
+		AND ($5::text = '' OR (s.faculty = $5 AND NOT COALESCE(s.privacy->'hidden_fields' @> '["faculty"]', false)))
+		AND ` + Discoverable + ` AND ` + HiddenFrom("$3") + `
+	) s ` + SchoolJoin + ` ORDER BY cardinality(s.shared) DESC, s.reputation DESC, s.id LIMIT $6 OFFSET $7;`
+)
+
+// GetClassmates returns the discoverable students of the same school sharing current classes with st, most shared
+// classes first, then highest reputation. Students hidden from st by a block or a mute are left out. The filter keeps
+// the ones of the campus or the faculty of st, if st has them
+func (r *studentRepository) GetClassmates(ctx context.Context, st *domain.Student, filter domain.ClassmateFilter, page domain.Page) ([]domain.Classmate, error) {
+	var campusID, facultyID string
+	if filter.SameCampus && st.Campus != nil {
+		campusID = st.Campus.ID
+	}
+	if filter.SameFaculty && st.Faculty != nil {
+		facultyID = st.Faculty.ID
+	}
+	rows, err := r.db.Query(ctx, selectClassmates, st.School.ID, st.CurrentClasses, st.ID, campusID, facultyID,
+		page.Size, page.Offset())
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	classmates := []domain.Classmate{}
+	for rows.Next() {
+		var classmate domain.Classmate
+		err = ScanStudent(rows, &classmate.Student, &classmate.SharedClasses, &classmate.Reputation)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		classmates = append(classmates, classmate)
+	}
+	return classmates, nil
+}
+
+// GetReputation returns the reputation of the student, 0 if the student doesn't exist
+func (r *studentRepository) GetReputation(ctx context.Context, id string) (int, error) {
+	rows, err := r.db.Query(ctx, selectReputation, id)
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	var score int
+	for rows.Next() {
+		err = rows.Scan(&score)
+		if err != nil {
+			return 0, errors.NewInternalServerError(err.Error())
+		}
+	}
+	return score, nil
+}
+
+// GetPrivacy returns the privacy settings of the student. Returns the default settings if the student doesn't exist
+func (r *studentRepository) GetPrivacy(ctx context.Context, id string) (*domain.Privacy, error) {
+	rows, err := r.db.Query(ctx, selectPrivacy, id)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	var privacy domain.Privacy
+	for rows.Next() {
+		err = rows.Scan(&privacy)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+	}
+	return &privacy, nil
+}
+
+// UpdatePrivacy replaces the privacy settings of the student
+func (r *studentRepository) UpdatePrivacy(ctx context.Context, id string, privacy *domain.Privacy) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, updatePrivacy, privacy, id)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/airbenders/profile/Student/repository"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/pgxmocks"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestGetClassmates(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"id", "first_name", "last_name", "email", "general_info", "school", "current_classes",
+		"classes_taken", "created_at", "updated_at", "program", "year_of_study", "pronouns", "languages", "links",
+		"privacy", "avatar", "school_name", "school_country", "school_domains", "school_email", "school_verified_at",
+		"campus", "campus_name", "faculty", "faculty_name", "shared", "reputation"}
+	st := &domain.Student{ID: "a", School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 352", "COMP 354"}}
+	page := domain.Page{Number: 2, Size: 10}
+
+	t.Run("success", func(t *testing.T) {
+		schoolID := "sc"
+		now := time.Now()
+		expected := []domain.Classmate{{
+			Student: domain.Student{ID: "b", FirstName: "c", LastName: "d", Email: "e", GeneralInfo: "f",
+				School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 354"}, CreatedAt: now, UpdatedAt: now},
+			SharedClasses: []string{"COMP 354"},
+			Reputation:    4,
+		}}
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow("b", "c", "d", "e", "f", &schoolID, []string{"COMP 354"}, nil, now, now, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, []string{"COMP 354"}, 4).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "sc", st.CurrentClasses, "a", "", "", 10, 10).
+			Return(pgxRows, nil)
+		sr := repository.NewStudentRepository(mockPool)
+		classmates, err := sr.GetClassmates(context.Background(), st, domain.ClassmateFilter{}, page)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, classmates)
+	})
+
+	t.Run("same-campus", func(t *testing.T) {
+		st := &domain.Student{ID: "a", School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 352"},
+			Campus: &domain.Campus{ID: "sgw"}, Faculty: &domain.Faculty{ID: "eng"}}
+		schoolID, campusID, campusName := "sc", "sgw", "Sir George Williams"
+		now := time.Now()
+		expected := []domain.Classmate{{
+			Student: domain.Student{ID: "b", School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 352"},
+				Campus: &domain.Campus{ID: "sgw", SchoolID: "sc", Name: "Sir George Williams"}, CreatedAt: now,
+				UpdatedAt: now},
+			SharedClasses: []string{"COMP 352"},
+			Reputation:    1,
+		}}
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow("b", "", "", "", "", &schoolID, []string{"COMP 352"}, nil, now, now, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, &campusID, &campusName, nil, nil, []string{"COMP 352"}, 1).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "sc", st.CurrentClasses, "a", "sgw", "", 10, 10).
+			Return(pgxRows, nil)
+		sr := repository.NewStudentRepository(mockPool)
+		classmates, err := sr.GetClassmates(context.Background(), st, domain.ClassmateFilter{SameCampus: true}, page)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, classmates)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("err"))
+		sr := repository.NewStudentRepository(mockPool)
+		classmates, err := sr.GetClassmates(context.Background(), st, domain.ClassmateFilter{}, page)
+
+		assert.Error(t, err)
+		assert.Nil(t, classmates)
+	})
+}
+
+func TestGetReputation(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		pgxRows := pgxpoolmock.NewRows([]string{"reputation"}).AddRow(-2).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a").Return(pgxRows, nil)
+		sr := repository.NewStudentRepository(mockPool)
+		reputation, err := sr.GetReputation(context.Background(), "a")
+
+		assert.NoError(t, err)
+		assert.Equal(t, -2, reputation)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a").Return(nil, errors.New("err"))
+		sr := repository.NewStudentRepository(mockPool)
+		_, err := sr.GetReputation(context.Background(), "a")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPrivacy(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+
+	t.Run("get-success", func(t *testing.T) {
+		expected := domain.Privacy{HideFromDiscovery: true}
+		pgxRows := pgxpoolmock.NewRows([]string{"privacy"}).AddRow(expected).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a").Return(pgxRows, nil)
+		sr := repository.NewStudentRepository(mockPool)
+		privacy, err := sr.GetPrivacy(context.Background(), "a")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, *privacy)
+	})
+
+	t.Run("update-success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.UpdatePrivacy(context.Background(), "a", &domain.Privacy{})
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("update-can't-begin-transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("err"))
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.UpdatePrivacy(context.Background(), "a", &domain.Privacy{})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/jackc/pgx/v4"
+	"time"
+)
+
+const (
+	upsertEnrollment = `INSERT INTO public.enrollment(
+	st_id, class, term, term_start, term_end, status, section, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (st_id, class, term) DO UPDATE
+	SET status=EXCLUDED.status, section=COALESCE(NULLIF(EXCLUDED.section, ''), enrollment.section),
+	updated_at=EXCLUDED.updated_at;`
+	selectEnrollments = `SELECT st_id, class, term, status, section, updated_at FROM public.enrollment
+	WHERE st_id=$1 ORDER BY term_start DESC, class;`
+	// completeEndedTerms marks every enrollment of a term that is over as completed and moves those classes from the
+	// student's current classes to the classes taken, all in one statement
+	completeEndedTerms = `WITH ended AS (
+		UPDATE public.enrollment SET status='completed', updated_at=$1
+		WHERE status='enrolled' AND term_end <= $1
+		RETURNING st_id, class
+	), moved AS (
+		SELECT st_id, array_agg(class) AS classes FROM ended GROUP BY st_id
+	)
+	UPDATE public.student s
+	SET current_classes = ARRAY(SELECT unnest(s.current_classes) EXCEPT SELECT unnest(m.classes)),
+	classes_taken = ARRAY(SELECT unnest(s.classes_taken) UNION SELECT unnest(m.classes)),
+	updated_at = $1
+	FROM moved m WHERE s.id = m.st_id;`
+)
+
+var (
+	selectClassmatesInTerm = `SELECT ` + StudentColumns + `
+	FROM public.enrollment e JOIN public.student s ON s.id = e.st_id ` + SchoolJoin + `
+	WHERE s.school=$1 AND e.class=$2 AND e.term=$3 AND e.status <> 'dropped' AND ` + Discoverable + `
+	AND ` + HiddenFrom("$4") + `
+	ORDER BY s.last_name, s.first_name;`
+)
+
+// SaveEnrollments creates the enrollments or updates their status and section if they already exist
+func (r *studentRepository) SaveEnrollments(ctx context.Context, enrollments []domain.Enrollment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	if err = saveEnrollments(ctx, tx, enrollments); err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+// saveEnrollments upserts the enrollments in the transaction
+func saveEnrollments(ctx context.Context, tx pgx.Tx, enrollments []domain.Enrollment) error {
+	for _, e := range enrollments {
+		_, err := tx.Exec(ctx, upsertEnrollment, e.StudentID, e.Class, e.Term.String(), e.Term.Start(), e.Term.End(),
+			string(e.Status), e.Section, e.UpdatedAt)
+		if err != nil {
+			return errors.NewInternalServerError(err.Error())
+		}
+	}
+	return nil
+}
+
+// GetEnrollments returns the enrollment history of the student, most recent term first
+func (r *studentRepository) GetEnrollments(ctx context.Context, studentID string) ([]domain.Enrollment, error) {
+	rows, err := r.db.Query(ctx, selectEnrollments, studentID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	enrollments := []domain.Enrollment{}
+	for rows.Next() {
+		var enrollment domain.Enrollment
+		var term, status string
+		var section *string
+		err = rows.Scan(&enrollment.StudentID, &enrollment.Class, &term, &status, &section, &enrollment.UpdatedAt)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		enrollment.Term, err = domain.ParseTerm(term)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		enrollment.Status = domain.EnrollmentStatus(status)
+		if section != nil {
+			enrollment.Section = *section
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+	return enrollments, nil
+}
+
+// GetClassmatesInTerm returns the discoverable students of the school who took the class during the term and didn't
+// drop it, except the ones hidden from the viewer
+func (r *studentRepository) GetClassmatesInTerm(ctx context.Context, viewerID string, schoolID string, class string, term domain.Term) ([]domain.Student, error) {
+	rows, err := r.db.Query(ctx, selectClassmatesInTerm, schoolID, class, term.String(), viewerID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	students := []domain.Student{}
+	for rows.Next() {
+		var student domain.Student
+		err = ScanStudent(rows, &student)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		students = append(students, student)
+	}
+	return students, nil
+}
+
+// CompleteEndedTerms completes the enrollments of every term that ended by now. Returns how many students had
+// classes rolled over. The term ends are stored in UTC without a time zone, so now is compared in UTC too
+func (r *studentRepository) CompleteEndedTerms(ctx context.Context, now time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, completeEndedTerms, now.UTC())
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, errors.NewInternalServerError(err.Error())
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,241 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/airbenders/profile/Student/repository"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/pgxmocks"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveEnrollments(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+	enrollments := []domain.Enrollment{
+		{StudentID: "a", Class: "COMP 352", Term: domain.Term{Season: domain.Fall, Year: 2025}, Status: domain.Completed},
+		{StudentID: "a", Class: "COMP 354", Term: domain.Term{Season: domain.Winter, Year: 2026}, Status: domain.Enrolled},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, nil).Twice()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.SaveEnrollments(context.Background(), enrollments)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't begin transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("err"))
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.SaveEnrollments(context.Background(), enrollments)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("can't exec transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("err")).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.SaveEnrollments(context.Background(), enrollments)
+
+		assert.Error(t, err)
+		txMock.AssertExpectations(t)
+	})
+}
+
+func TestUpdateClasses(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	st := &domain.Student{ID: "a", CurrentClasses: []string{"COMP 354"}, ClassesTaken: []string{"COMP 352"}}
+	enrollments := []domain.Enrollment{
+		{StudentID: "a", Class: "COMP 354", Term: domain.Term{Season: domain.Winter, Year: 2026}, Status: domain.Enrolled},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		txMock := new(pgxmocks.TxMock)
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Twice()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.UpdateClasses(context.Background(), st, enrollments)
+
+		assert.NoError(t, err)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("enrollment-fails-classes-rolled-back", func(t *testing.T) {
+		txMock := new(pgxmocks.TxMock)
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag("UPDATE 1"), nil).Once()
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("err")).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		err := sr.UpdateClasses(context.Background(), st, enrollments)
+
+		assert.Error(t, err)
+		txMock.AssertExpectations(t)
+		txMock.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+}
+
+// sqlContaining matches the queries containing the fragment
+type sqlContaining string
+
+func (s sqlContaining) Matches(x interface{}) bool {
+	query, ok := x.(string)
+	return ok && strings.Contains(query, string(s))
+}
+
+func (s sqlContaining) String() string {
+	return "a query containing " + string(s)
+}
+
+func TestGetClassmatesInTerm(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"id", "first_name", "last_name", "email", "general_info", "school", "current_classes",
+		"classes_taken", "created_at", "updated_at", "program", "year_of_study", "pronouns", "languages", "links",
+		"privacy", "avatar", "school_name", "school_country", "school_domains", "school_email", "school_verified_at",
+		"campus", "campus_name", "faculty", "faculty_name"}
+	term := domain.Term{Season: domain.Winter, Year: 2026}
+
+	t.Run("success-discoverable-only", func(t *testing.T) {
+		schoolID := "sc"
+		now := time.Now()
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow("b", "c", "d", "e", "f", &schoolID, []string{"COMP 354"}, nil, now, now, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), sqlContaining("hide_from_discovery"), "sc", "COMP 354",
+			"Winter 2026", "a").Return(pgxRows, nil)
+		sr := repository.NewStudentRepository(mockPool)
+		students, err := sr.GetClassmatesInTerm(context.Background(), "a", "sc", "COMP 354", term)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, []domain.Student{{ID: "b", FirstName: "c", LastName: "d", Email: "e", GeneralInfo: "f",
+			School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 354"}, CreatedAt: now, UpdatedAt: now}},
+			students)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("err"))
+		sr := repository.NewStudentRepository(mockPool)
+		students, err := sr.GetClassmatesInTerm(context.Background(), "a", "sc", "COMP 354", term)
+
+		assert.Error(t, err)
+		assert.Nil(t, students)
+	})
+}
+
+func TestGetEnrollments(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"st_id", "class", "term", "status", "section", "updated_at"}
+	now := time.Now()
+	section := "PP"
+
+	t.Run("success", func(t *testing.T) {
+		expected := []domain.Enrollment{
+			{StudentID: "a", Class: "COMP 354", Term: domain.Term{Season: domain.Winter, Year: 2026},
+				Status: domain.Enrolled, Section: section, UpdatedAt: now},
+			{StudentID: "a", Class: "COMP 352", Term: domain.Term{Season: domain.Fall, Year: 2025},
+				Status: domain.Completed, UpdatedAt: now},
+		}
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow("a", "COMP 354", "Winter 2026", "enrolled", &section, now).
+			AddRow("a", "COMP 352", "Fall 2025", "completed", nil, now).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a").Return(pgxRows, nil)
+		sr := repository.NewStudentRepository(mockPool)
+		enrollments, err := sr.GetEnrollments(context.Background(), "a")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, enrollments)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "a").Return(nil, errors.New("err"))
+		sr := repository.NewStudentRepository(mockPool)
+		enrollments, err := sr.GetEnrollments(context.Background(), "a")
+
+		assert.Error(t, err)
+		assert.Nil(t, enrollments)
+	})
+}
+
+func TestCompleteEndedTerms(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	txMock := new(pgxmocks.TxMock)
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Date(2026, time.May, 1, 0, 30, 0, 0, time.FixedZone("EDT", -4*60*60))
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, []interface{}{now.UTC()}).
+			Return(pgconn.CommandTag("UPDATE 3"), nil).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+		txMock.On("Commit", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		count, err := sr.CompleteEndedTerms(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+		txMock.AssertExpectations(t)
+	})
+
+	t.Run("can't commit transaction", func(t *testing.T) {
+		mockPool.EXPECT().Begin(gomock.Any()).Return(txMock, nil)
+		txMock.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, nil).Once()
+		txMock.On("Commit", mock.Anything).Return(errors.New("err")).Once()
+		txMock.On("Rollback", mock.Anything).Return(nil).Once()
+
+		sr := repository.NewStudentRepository(mockPool)
+		_, err := sr.CompleteEndedTerms(context.Background(), time.Now())
+
+		assert.Error(t, err)
+		txMock.AssertExpectations(t)
+	})
+}
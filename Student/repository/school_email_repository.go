@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"time"
+)
+
+// selectUndeliverableSchoolEmail joins the address of the latest pending confirmation with the suppressions
+const selectUndeliverableSchoolEmail = `SELECT s.email, s.reason, s.diagnostic, s.created_at
+	FROM (SELECT email FROM public.confirmation WHERE st_id = $1 AND consumed_at IS NULL AND created_at > $2
+		ORDER BY created_at DESC LIMIT 1) AS latest
+	JOIN public.mail_suppression s ON s.email = lower(latest.email);`
+
+// GetUndeliverableSchoolEmail returns the suppression of the address the latest pending confirmation of the student,
+// created after since, was sent to. Returns nil if there is none or its address can get emails
+func (r *studentRepository) GetUndeliverableSchoolEmail(ctx context.Context, id string, since time.Time) (*domain.MailSuppression, error) {
+	rows, err := r.db.Query(ctx, selectUndeliverableSchoolEmail, id, since)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	var suppression *domain.MailSuppression
+	for rows.Next() {
+		var reason string
+		suppression = &domain.MailSuppression{}
+		err = rows.Scan(&suppression.Email, &reason, &suppression.Diagnostic, &suppression.CreatedAt)
+		if err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		suppression.Reason = domain.MailEventType(reason)
+	}
+	return suppression, nil
+}
@@ -0,0 +1,57 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/airbenders/profile/Student/repository"
+	"github.com/airbenders/profile/domain"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetUndeliverableSchoolEmail(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"email", "reason", "diagnostic", "created_at"}
+	since := time.Now().Add(-domain.ConfirmationTTL)
+
+	t.Run("bounced", func(t *testing.T) {
+		now := time.Now()
+		pgxRows := pgxpoolmock.NewRows(columns).AddRow("ada@concordia.ca", "bounce", "550 user unknown", now).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "abc", since).Return(pgxRows, nil)
+
+		sr := repository.NewStudentRepository(mockPool)
+		suppression, err := sr.GetUndeliverableSchoolEmail(context.Background(), "abc", since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.MailSuppression{Email: "ada@concordia.ca", Reason: domain.MailBounce,
+			Diagnostic: "550 user unknown", CreatedAt: now}, suppression)
+	})
+
+	t.Run("deliverable", func(t *testing.T) {
+		pgxRows := pgxpoolmock.NewRows(columns).ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "abc", since).Return(pgxRows, nil)
+
+		sr := repository.NewStudentRepository(mockPool)
+		suppression, err := sr.GetUndeliverableSchoolEmail(context.Background(), "abc", since)
+
+		assert.NoError(t, err)
+		assert.Nil(t, suppression)
+	})
+
+	t.Run("query-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "abc", since).Return(nil, errors.New("err"))
+
+		sr := repository.NewStudentRepository(mockPool)
+		suppression, err := sr.GetUndeliverableSchoolEmail(context.Background(), "abc", since)
+
+		assert.Error(t, err)
+		assert.Nil(t, suppression)
+	})
+}
@@ -12,6 +12,13 @@ CREATE TABLE IF NOT EXISTS public.student
     classes_taken text[] COLLATE pg_catalog."default",
     general_info character varying(1024) COLLATE pg_catalog."default",
     school character varying(64) COLLATE pg_catalog."default",
+    privacy jsonb NOT NULL DEFAULT '{}'::jsonb,
+    program text NOT NULL DEFAULT '',
+    year_of_study integer NOT NULL DEFAULT 0,
+    pronouns text NOT NULL DEFAULT '',
+    languages text[] NOT NULL DEFAULT '{}',
+    links jsonb NOT NULL DEFAULT '{}'::jsonb,
+    avatar jsonb,
     created_at timestamp without time zone,
     updated_at timestamp without time zone,
     CONSTRAINT student_pkey PRIMARY KEY (id),
@@ -26,7 +33,56 @@ TABLESPACE pg_default;
 ALTER TABLE public.student
     OWNER to postgres;
 
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS privacy jsonb NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS program text NOT NULL DEFAULT '';
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS year_of_study integer NOT NULL DEFAULT 0;
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS pronouns text NOT NULL DEFAULT '';
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS languages text[] NOT NULL DEFAULT '{}';
+-- links is in the format of domain.StudentLinks
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS links jsonb NOT NULL DEFAULT '{}'::jsonb;
+-- avatar is in the format of domain.Avatar, null without an avatar
+ALTER TABLE public.student ADD COLUMN IF NOT EXISTS avatar jsonb;
+CREATE INDEX IF NOT EXISTS student_school_current_classes_idx ON public.student USING gin (current_classes);
+
 INSERT INTO public.student (id, first_name, last_name, email, general_info, created_at, updated_at)
 VALUES ('234', 'Also Zubair', 'Nurie', 'mzznurie@msn.com', 'ballerr', now(), now());
 INSERT INTO public.student (id, first_name, last_name, email, general_info, created_at, updated_at)
 VALUES ('123', 'Zubair', 'Nurie', 'mznurie@msn.com', 'baller', now(), now());
+
+CREATE TABLE IF NOT EXISTS public.enrollment
+(
+    st_id character varying(64) NOT NULL REFERENCES public.student (id) ON DELETE CASCADE,
+    class text NOT NULL,
+    term text NOT NULL,
+    term_start timestamp without time zone NOT NULL,
+    term_end timestamp without time zone NOT NULL,
+    status text NOT NULL DEFAULT 'enrolled' CHECK (status IN ('enrolled', 'completed', 'dropped')),
+    section text,
+    updated_at timestamp without time zone,
+    CONSTRAINT enrollment_pkey PRIMARY KEY (st_id, class, term)
+);
+
+CREATE INDEX IF NOT EXISTS enrollment_class_term_idx ON public.enrollment (class, term);
+CREATE INDEX IF NOT EXISTS enrollment_open_idx ON public.enrollment (term_end) WHERE status = 'enrolled';
+
+CREATE TABLE IF NOT EXISTS public.student_block
+(
+    blocker character varying(64) NOT NULL REFERENCES public.student (id) ON DELETE CASCADE,
+    blocked character varying(64) NOT NULL REFERENCES public.student (id) ON DELETE CASCADE,
+    kind text NOT NULL CHECK (kind IN ('block', 'mute')),
+    created_at timestamp without time zone NOT NULL DEFAULT now(),
+    CONSTRAINT student_block_pkey PRIMARY KEY (blocker, blocked, kind)
+);
+
+CREATE INDEX IF NOT EXISTS student_block_blocked_idx ON public.student_block (blocked, blocker);
+
+-- weekly and exceptions are in the format of domain.WeeklySlot and domain.AvailabilityException
+CREATE TABLE IF NOT EXISTS public.availability
+(
+    st_id character varying(64) NOT NULL REFERENCES public.student (id) ON DELETE CASCADE,
+    time_zone text NOT NULL DEFAULT 'UTC',
+    weekly jsonb NOT NULL DEFAULT '[]'::jsonb,
+    exceptions jsonb NOT NULL DEFAULT '[]'::jsonb,
+    updated_at timestamp with time zone,
+    CONSTRAINT availability_pkey PRIMARY KEY (st_id)
+);
@@ -21,25 +21,91 @@ func NewStudentRepository(db pgxpoolmock.PgxPool) domain.StudentRepository {
 }
 
 const (
+	// StudentColumns are the columns scanned by ScanStudent, for the student table aliased as s joined with
+	// SchoolJoin. The other repositories that read students use them too
+	StudentColumns = `s.id, s.first_name, s.last_name, s.email, s.general_info, s.school, s.current_classes,
+	s.classes_taken, s.created_at, s.updated_at, s.program, s.year_of_study, s.pronouns, s.languages, s.links, s.privacy,
+	s.avatar, sc.name, sc.country, sc.domains, s.school_email, s.school_verified_at, s.campus, ca.name, s.faculty,
+	fa.name`
+	// SchoolJoin joins the school, the campus and the faculty of the student s, if it has them
+	SchoolJoin = `LEFT JOIN public.school sc ON sc.id = s.school LEFT JOIN public.campus ca ON ca.id = s.campus
+	LEFT JOIN public.faculty fa ON fa.id = s.faculty`
 	insert = `INSERT INTO public.student(
-	id, first_name, last_name, email, general_info, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5, $6, $7);`
-	selectByID = `SELECT id, first_name, last_name, email, general_info, school, current_classes, classes_taken, created_at, updated_at
-	FROM public.student WHERE id=$1;`
+	id, first_name, last_name, email, general_info, program, year_of_study, pronouns, languages, links, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);`
+	selectByID = `SELECT ` + StudentColumns + `
+	FROM public.student s ` + SchoolJoin + ` WHERE s.id=$1;`
+	// selectByIDs leaves out the students blocked from the viewer, $2. Muted students are still found
+	selectByIDs = `SELECT ` + StudentColumns + `
+	FROM public.student s ` + SchoolJoin + ` WHERE s.id = ANY($1) AND NOT EXISTS (SELECT 1 FROM public.student_block b
+	WHERE b.kind = 'block' AND ((b.blocker = s.id AND b.blocked = $2) OR (b.blocker = $2 AND b.blocked = s.id)));`
 	update = `UPDATE public.student
-	SET first_name=$2, last_name=$3, email=$4, general_info=$5, created_at=$6, updated_at=$7
+	SET first_name=$2, last_name=$3, email=$4, general_info=$5, program=$6, year_of_study=$7, pronouns=$8,
+	languages=$9, links=$10, created_at=$11, updated_at=$12
 	WHERE id=$1;`
 	deleteStudent = `DELETE FROM public.student
 	WHERE id=$1;`
-	getSchoolName     = `SELECT name FROM school WHERE ID=$1`
-	updateClasses     = `UPDATE public.student SET current_classes=$1, classes_taken=$2, updated_at=$3 WHERE id = $4;`
-	searchWithClasses = `SELECT id, first_name, last_name, email, general_info, school, current_classes, classes_taken, 
-	created_at, updated_at FROM public.student WHERE first_name ILIKE '%' || $1 ||'%' AND last_name  ILIKE '%' || $2 ||'%'
-	AND current_classes && $3`
-	searchWithoutClasses = `SELECT id, first_name, last_name, email, general_info, school, current_classes, classes_taken, 
-	created_at, updated_at FROM public.student WHERE first_name ILIKE '%' || $1 ||'%' AND last_name  ILIKE '%' || $2 ||'%'`
+	updateClasses = `UPDATE public.student SET current_classes=$1, classes_taken=$2, updated_at=$3 WHERE id = $4;`
 )
 
+var (
+	// searchStudents filters on every criteria that is given. A student only matches a filter on a profile field if
+	// the field isn't hidden from the viewer. Results are ordered by school so they can be grouped
+	searchStudents = `SELECT ` + StudentColumns + ` FROM public.student s ` + SchoolJoin + `
+	WHERE s.first_name ILIKE '%' || $1 ||'%' AND s.last_name ILIKE '%' || $2 ||'%' AND ` + HiddenFrom("$3") + `
+	AND (cardinality($4::text[]) = 0 OR s.current_classes && $4::text[])
+	AND ($5::text = '' OR (s.program ILIKE '%' || $5 || '%'
+		AND (s.id = $3 OR NOT COALESCE(s.privacy->'hidden_fields' @> '["program"]', false))))
+	AND ($6::int = 0 OR (s.year_of_study = $6
+		AND (s.id = $3 OR NOT COALESCE(s.privacy->'hidden_fields' @> '["year"]', false))))
+	AND (cardinality($7::text[]) = 0 OR (s.languages && $7::text[]
+		AND (s.id = $3 OR NOT COALESCE(s.privacy->'hidden_fields' @> '["languages"]', false))))
+	AND ($8::text = '' OR sc.name ILIKE '%' || $8 || '%')
+	AND ($9::text = '' OR lower(sc.country) = lower($9))
+	AND ($10::text = '' OR (s.campus = $10
+		AND (s.id = $3 OR NOT COALESCE(s.privacy->'hidden_fields' @> '["campus"]', false))))
+	AND ($11::text = '' OR (s.faculty = $11
+		AND (s.id = $3 OR NOT COALESCE(s.privacy->'hidden_fields' @> '["faculty"]', false))))
+	ORDER BY sc.country NULLS LAST, sc.name NULLS LAST, s.last_name, s.first_name;`
+)
+
+// ScanStudent scans the StudentColumns of the row into student, then the extra columns into dest
+func ScanStudent(rows pgx.Rows, student *domain.Student, dest ...interface{}) error {
+	var schoolID, schoolName, country, schoolEmail, campusID, campusName, facultyID, facultyName *string
+	var domains []string
+	var verifiedAt *time.Time
+	err := rows.Scan(append([]interface{}{&student.ID, &student.FirstName, &student.LastName, &student.Email,
+		&student.GeneralInfo, &schoolID, &student.CurrentClasses, &student.ClassesTaken, &student.CreatedAt,
+		&student.UpdatedAt, &student.Program, &student.Year, &student.Pronouns, &student.Languages, &student.Links,
+		&student.Privacy, &student.Avatar, &schoolName, &country, &domains, &schoolEmail, &verifiedAt, &campusID,
+		&campusName, &facultyID, &facultyName}, dest...)...)
+	if err != nil {
+		return err
+	}
+	if schoolID != nil {
+		student.School = &domain.School{
+			ID:      *schoolID,
+			Domains: domains,
+		}
+		if schoolName != nil {
+			student.School.Name = *schoolName
+		}
+		if country != nil {
+			student.School.Country = *country
+		}
+		if campusID != nil && campusName != nil {
+			student.Campus = &domain.Campus{ID: *campusID, SchoolID: *schoolID, Name: *campusName}
+		}
+		if facultyID != nil && facultyName != nil {
+			student.Faculty = &domain.Faculty{ID: *facultyID, SchoolID: *schoolID, Name: *facultyName}
+		}
+	}
+	if schoolEmail != nil && verifiedAt != nil {
+		student.SchoolVerification = &domain.SchoolVerification{Email: *schoolEmail, VerifiedAt: *verifiedAt}
+	}
+	return nil
+}
+
 // Create stores the student in the db. Returns err if unable to
 func (r *studentRepository) Create(ctx context.Context, id string, st *domain.Student) error {
 	tx, err := r.db.Begin(ctx)
@@ -48,7 +114,8 @@ func (r *studentRepository) Create(ctx context.Context, id string, st *domain.St
 	}
 	defer tx.Rollback(ctx)
 
-	_, err = tx.Exec(ctx, insert, id, st.FirstName, st.LastName, st.Email, st.GeneralInfo, st.CreatedAt, st.UpdatedAt)
+	_, err = tx.Exec(ctx, insert, id, st.FirstName, st.LastName, st.Email, st.GeneralInfo, st.Program, st.Year,
+		st.Pronouns, languagesOf(st), st.Links, st.CreatedAt, st.UpdatedAt)
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
 	}
@@ -71,23 +138,36 @@ func (r *studentRepository) GetByID(ctx context.Context, id string) (*domain.Stu
 
 	var student domain.Student
 	for rows.Next() {
-		var schoolID *string
-		err = rows.Scan(&student.ID, &student.FirstName, &student.LastName, &student.Email, &student.GeneralInfo,
-			&schoolID, &student.CurrentClasses, &student.ClassesTaken, &student.CreatedAt, &student.UpdatedAt)
+		err = ScanStudent(rows, &student)
 		if err != nil {
 			err = errors.NewInternalServerError(err.Error())
 			return nil, err
 		}
-		if schoolID != nil {
-			student.School = &domain.School{
-				ID: *schoolID,
-			}
-		}
 	}
 
 	return &student, nil
 }
 
+// GetByIDs returns the students with those IDs in a single query, skipping the ones that don't exist or are blocked
+// from the viewer
+func (r *studentRepository) GetByIDs(ctx context.Context, viewerID string, ids []string) ([]domain.Student, error) {
+	rows, err := r.db.Query(ctx, selectByIDs, ids, viewerID)
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	defer rows.Close()
+
+	students := []domain.Student{}
+	for rows.Next() {
+		var student domain.Student
+		if err = ScanStudent(rows, &student); err != nil {
+			return nil, errors.NewInternalServerError(err.Error())
+		}
+		students = append(students, student)
+	}
+	return students, nil
+}
+
 // Update changes the record in the db. Returns err if isn't able to
 func (r *studentRepository) Update(ctx context.Context, st *domain.Student) error {
 	tx, err := r.db.Begin(ctx)
@@ -96,7 +176,8 @@ func (r *studentRepository) Update(ctx context.Context, st *domain.Student) erro
 	}
 	defer tx.Rollback(ctx)
 
-	_, err = tx.Exec(ctx, update, st.ID, st.FirstName, st.LastName, st.Email, st.GeneralInfo, st.CreatedAt, st.UpdatedAt)
+	_, err = tx.Exec(ctx, update, st.ID, st.FirstName, st.LastName, st.Email, st.GeneralInfo, st.Program, st.Year,
+		st.Pronouns, languagesOf(st), st.Links, st.CreatedAt, st.UpdatedAt)
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
 	}
@@ -128,7 +209,9 @@ func (r *studentRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *studentRepository) UpdateClasses(ctx context.Context, st *domain.Student) error {
+// UpdateClasses saves the classes of the student and the enrollments recording the change in one transaction, so
+// the enrollment history can't fall out of sync with the classes
+func (r *studentRepository) UpdateClasses(ctx context.Context, st *domain.Student, enrollments []domain.Enrollment) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
@@ -138,6 +221,9 @@ func (r *studentRepository) UpdateClasses(ctx context.Context, st *domain.Studen
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
 	}
+	if err = saveEnrollments(ctx, tx, enrollments); err != nil {
+		return err
+	}
 	err = tx.Commit(ctx)
 	if err != nil {
 		return errors.NewInternalServerError(err.Error())
@@ -146,14 +232,25 @@ func (r *studentRepository) UpdateClasses(ctx context.Context, st *domain.Studen
 	return nil
 }
 
-func (r *studentRepository) SearchStudents(ctx context.Context, st *domain.Student) ([]domain.Student, error) {
-	var rows pgx.Rows
-	var err error
-	if len(st.CurrentClasses) > 0 {
-		rows, err = r.db.Query(ctx, searchWithClasses, st.FirstName, st.LastName, st.CurrentClasses)
-	} else {
-		rows, err = r.db.Query(ctx, searchWithoutClasses, st.FirstName, st.LastName)
+// SearchStudents returns the students matching the names and the profile fields of st, and sharing a class with st
+// if it has classes. Students hidden from the viewer by a block or a mute are left out
+func (r *studentRepository) SearchStudents(ctx context.Context, viewerID string, st *domain.Student) ([]domain.Student, error) {
+	classes := st.CurrentClasses
+	if classes == nil {
+		classes = []string{}
+	}
+	var schoolName, country, campusID, facultyID string
+	if st.School != nil {
+		schoolName, country = st.School.Name, st.School.Country
+	}
+	if st.Campus != nil {
+		campusID = st.Campus.ID
+	}
+	if st.Faculty != nil {
+		facultyID = st.Faculty.ID
 	}
+	rows, err := r.db.Query(ctx, searchStudents, st.FirstName, st.LastName, viewerID, classes, st.Program, st.Year,
+		languagesOf(st), schoolName, country, campusID, facultyID)
 	if err != nil {
 		err = errors.NewInternalServerError(err.Error())
 		return nil, err
@@ -163,19 +260,20 @@ func (r *studentRepository) SearchStudents(ctx context.Context, st *domain.Stude
 	students := []domain.Student{}
 	for rows.Next() {
 		var student domain.Student
-		var schoolID *string
-		err = rows.Scan(&student.ID, &student.FirstName, &student.LastName, &student.Email, &student.GeneralInfo,
-			&schoolID, &student.CurrentClasses, &student.ClassesTaken, &student.CreatedAt, &student.UpdatedAt)
+		err = ScanStudent(rows, &student)
 		if err != nil {
 			err = errors.NewInternalServerError(err.Error())
 			return nil, err
 		}
-		if schoolID != nil {
-			student.School = &domain.School{
-				ID: *schoolID,
-			}
-		}
 		students = append(students, student)
 	}
 	return students, nil
 }
+
+// languagesOf returns the languages of the student, never nil since the column can't be null
+func languagesOf(st *domain.Student) []string {
+	if st.Languages == nil {
+		return []string{}
+	}
+	return st.Languages
+}
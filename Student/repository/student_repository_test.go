@@ -21,7 +21,10 @@ func TestGetByID(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
-	columns := []string{"id", "first_name", "last_name", "email", "general_info", "school", "current_classes", "classes_taken", "created_at", "updated_at"}
+	columns := []string{"id", "first_name", "last_name", "email", "general_info", "school", "current_classes", "classes_taken", "created_at", "updated_at",
+		"program", "year_of_study", "pronouns", "languages", "links", "privacy", "avatar",
+		"school_name", "school_country", "school_domains",
+		"school_email", "school_verified_at", "campus", "campus_name", "faculty", "faculty_name"}
 
 	t.Run("success-with-nil-school", func(t *testing.T) {
 		expectedStudent := &domain.Student{
@@ -36,6 +39,12 @@ func TestGetByID(t *testing.T) {
 			CreatedAt:      time.Now(),
 			UpdatedAt:      time.Now(),
 			Reviews:        nil,
+			Program:        "Software Engineering",
+			Year:           2,
+			Pronouns:       "they/them",
+			Languages:      []string{"English", "French"},
+			Links:          domain.StudentLinks{GitHub: "https://github.com/b"},
+			Privacy:        domain.Privacy{HiddenFields: []string{domain.FieldYear}},
 		}
 		pgxRows := pgxpoolmock.NewRows(columns).AddRow(
 			expectedStudent.ID,
@@ -48,6 +57,13 @@ func TestGetByID(t *testing.T) {
 			expectedStudent.ClassesTaken,
 			expectedStudent.CreatedAt,
 			expectedStudent.UpdatedAt,
+			expectedStudent.Program,
+			expectedStudent.Year,
+			expectedStudent.Pronouns,
+			expectedStudent.Languages,
+			expectedStudent.Links,
+			expectedStudent.Privacy,
+			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
 		).ToPgxRows()
 		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf("string")).Return(pgxRows, nil)
 		sr := repository.NewStudentRepository(mockPool)
@@ -58,19 +74,24 @@ func TestGetByID(t *testing.T) {
 	})
 
 	t.Run("success-with-some-school", func(t *testing.T) {
+		school := &domain.School{ID: "something", Name: "Concordia University", Country: "Canada",
+			Domains: []string{"concordia.ca"}}
+		verification := &domain.SchoolVerification{Email: "b@concordia.ca", VerifiedAt: time.Now()}
 		expectedStudent := domain.Student{
 			ID:             "a",
 			FirstName:      "b",
 			LastName:       "c",
 			Email:          "d",
 			GeneralInfo:    "e",
-			School:         &domain.School{ID: "something"},
+			School:         school,
 			CurrentClasses: nil,
 			ClassesTaken:   nil,
 			CreatedAt:      time.Now(),
 			UpdatedAt:      time.Now(),
 			Reviews:        nil,
 		}
+		expectedStudent.SchoolVerification = verification
+		expectedStudent.Campus = &domain.Campus{ID: "sgw", SchoolID: school.ID, Name: "Sir George Williams"}
 		pgxRows := pgxpoolmock.NewRows(columns).AddRow(
 			expectedStudent.ID,
 			expectedStudent.FirstName,
@@ -81,7 +102,14 @@ func TestGetByID(t *testing.T) {
 			expectedStudent.CurrentClasses,
 			expectedStudent.ClassesTaken,
 			expectedStudent.CreatedAt,
-			expectedStudent.UpdatedAt).ToPgxRows()
+			expectedStudent.UpdatedAt,
+			nil, nil, nil, nil, nil, nil, nil,
+			&expectedStudent.School.Name,
+			&expectedStudent.School.Country,
+			expectedStudent.School.Domains,
+			&verification.Email,
+			&verification.VerifiedAt,
+			&expectedStudent.Campus.ID, &expectedStudent.Campus.Name, nil, nil).ToPgxRows()
 		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).Return(pgxRows, nil)
 		sr := repository.NewStudentRepository(mockPool)
 		student, err := sr.GetByID(context.Background(), "a")
@@ -101,6 +129,46 @@ func TestGetByID(t *testing.T) {
 	})
 }
 
+func TestGetByIDs(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	columns := []string{"id", "first_name", "last_name", "email", "general_info", "school", "current_classes", "classes_taken", "created_at", "updated_at",
+		"program", "year_of_study", "pronouns", "languages", "links", "privacy", "avatar",
+		"school_name", "school_country", "school_domains",
+		"school_email", "school_verified_at", "campus", "campus_name", "faculty", "faculty_name"}
+	ids := []string{"a", "b", "c"}
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Now()
+		pgxRows := pgxpoolmock.NewRows(columns).
+			AddRow("a", "Ada", "Lovelace", "a@b.c", "", nil, nil, nil, now, now, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil).
+			AddRow("c", "Alan", "Turing", "c@b.c", "", nil, nil, nil, now, now, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				nil, nil, nil, nil, nil, nil).
+			ToPgxRows()
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), ids, "viewer").Return(pgxRows, nil)
+		sr := repository.NewStudentRepository(mockPool)
+		students, err := sr.GetByIDs(context.Background(), "viewer", ids)
+
+		assert.NoError(t, err)
+		assert.Len(t, students, 2)
+		assert.Equal(t, "a", students[0].ID)
+		assert.Equal(t, "Turing", students[1].LastName)
+	})
+
+	t.Run("query-return-err", func(t *testing.T) {
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), ids, "viewer").Return(nil, errors.New("err"))
+		sr := repository.NewStudentRepository(mockPool)
+		students, err := sr.GetByIDs(context.Background(), "viewer", ids)
+
+		assert.Error(t, err)
+		assert.Nil(t, students)
+	})
+}
+
 func TestUpdate(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
@@ -284,7 +352,10 @@ func TestSearchStudents(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
-	columns := []string{"id", "first_name", "last_name", "email", "general_info", "school", "current_classes", "classes_taken", "created_at", "updated_at"}
+	columns := []string{"id", "first_name", "last_name", "email", "general_info", "school", "current_classes", "classes_taken", "created_at", "updated_at",
+		"program", "year_of_study", "pronouns", "languages", "links", "privacy", "avatar",
+		"school_name", "school_country", "school_domains",
+		"school_email", "school_verified_at", "campus", "campus_name", "faculty", "faculty_name"}
 
 	t.Run("success-with-nil-school", func(t *testing.T) {
 		var retrievedStudents []domain.Student
@@ -313,10 +384,12 @@ func TestSearchStudents(t *testing.T) {
 			expectedStudent.ClassesTaken,
 			expectedStudent.CreatedAt,
 			expectedStudent.UpdatedAt,
+			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
 		).ToPgxRows()
-		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf("string")).Return(pgxRows, nil)
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "", "", "v", []string{}, "", 0, []string{}, "", "", "", "").
+			Return(pgxRows, nil)
 		sr := repository.NewStudentRepository(mockPool)
-		student, err := sr.SearchStudents(context.Background(), &domain.Student{ID: "a"})
+		student, err := sr.SearchStudents(context.Background(), "v", &domain.Student{ID: "a"})
 
 		assert.NoError(t, err)
 		assert.EqualValues(t, retrievedStudents, student)
@@ -361,7 +434,8 @@ func TestSearchStudents(t *testing.T) {
 			expectedStudent1.CurrentClasses,
 			expectedStudent1.ClassesTaken,
 			expectedStudent1.CreatedAt,
-			expectedStudent1.UpdatedAt).
+			expectedStudent1.UpdatedAt,
+			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).
 			AddRow(expectedStudent2.ID,
 				expectedStudent2.FirstName,
 				expectedStudent2.LastName,
@@ -371,20 +445,26 @@ func TestSearchStudents(t *testing.T) {
 				expectedStudent2.CurrentClasses,
 				expectedStudent2.ClassesTaken,
 				expectedStudent2.CreatedAt,
-				expectedStudent2.UpdatedAt).ToPgxRows()
-		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).Return(pgxRows, nil)
+				expectedStudent2.UpdatedAt,
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).ToPgxRows()
+		filters := &domain.Student{ID: "a", CurrentClasses: []string{"COMP 354"}, Program: "soft", Year: 2,
+			Languages: []string{"French"}, School: &domain.School{Name: "concordia", Country: "canada"},
+			Faculty: &domain.Faculty{ID: "eng"}}
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "", "", "v", []string{"COMP 354"}, "soft", 2,
+			[]string{"French"}, "concordia", "canada", "", "eng").Return(pgxRows, nil)
 		sr := repository.NewStudentRepository(mockPool)
-		student, err := sr.SearchStudents(context.Background(), &domain.Student{ID: "a"})
+		student, err := sr.SearchStudents(context.Background(), "v", filters)
 
 		assert.NoError(t, err)
 		assert.EqualValues(t, retrievedStudents, student)
 	})
 
 	t.Run("query-return-err", func(t *testing.T) {
-		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf("string")).
+		mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("err"))
 		sr := repository.NewStudentRepository(mockPool)
-		student, err := sr.SearchStudents(context.Background(), &domain.Student{})
+		student, err := sr.SearchStudents(context.Background(), "v", &domain.Student{})
 
 		assert.Error(t, err)
 		assert.Nil(t, student)
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"reflect"
+	"time"
+)
+
+const (
+	maxCommonAvailabilityStudents = 10
+	maxCommonAvailabilityRange    = 31 * 24 * time.Hour
+)
+
+// GetAvailability returns the availability of the student. Students who haven't set one are never available.
+// Students blocked from each other can't see each other's availability
+func (s *studentUseCase) GetAvailability(c context.Context, viewerID string, id string) (*domain.Availability, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	err := s.checkVisible(ctx, viewerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	availabilities, err := s.studentRepository.GetAvailabilities(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(availabilities) == 0 {
+		return &domain.Availability{
+			StudentID:  id,
+			TimeZone:   "UTC",
+			Weekly:     []domain.WeeklySlot{},
+			Exceptions: []domain.AvailabilityException{},
+		}, nil
+	}
+	return &availabilities[0], nil
+}
+
+// UpdateAvailability replaces the availability of the student if it exists and is valid
+func (s *studentUseCase) UpdateAvailability(c context.Context, id string, availability *domain.Availability) error {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	existingStudent, err := s.studentRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(existingStudent, &domain.Student{}) {
+		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+	if err = availability.Validate(); err != nil {
+		return errors.NewBadRequestError(err.Error())
+	}
+	if availability.Weekly == nil {
+		availability.Weekly = []domain.WeeklySlot{}
+	}
+	if availability.Exceptions == nil {
+		availability.Exceptions = []domain.AvailabilityException{}
+	}
+
+	availability.StudentID = id
+	availability.UpdatedAt = time.Now()
+	return s.studentRepository.SaveAvailability(ctx, availability)
+}
+
+// GetCommonAvailability returns when all the students are free between from and to
+func (s *studentUseCase) GetCommonAvailability(c context.Context, viewerID string, ids []string, from, to time.Time) ([]domain.Interval, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	ids = removeDuplicates(ids)
+	if len(ids) < 2 || len(ids) > maxCommonAvailabilityStudents {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("provide between 2 and %d students", maxCommonAvailabilityStudents))
+	}
+	if !to.After(from) || to.Sub(from) > maxCommonAvailabilityRange {
+		return nil, errors.NewBadRequestError("the end must be after the start and at most 31 days later")
+	}
+	for _, id := range ids {
+		err := s.checkVisible(ctx, viewerID, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	availabilities, err := s.studentRepository.GetAvailabilities(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(availabilities) < len(ids) {
+		// someone hasn't said when they are free
+		return []domain.Interval{}, nil
+	}
+	free := make([][]domain.Interval, 0, len(availabilities))
+	for i := range availabilities {
+		free = append(free, availabilities[i].FreeIntervals(from, to))
+	}
+	return domain.CommonIntervals(free...), nil
+}
+
+// checkVisible returns a not found error if the student doesn't exist or is blocked from the viewer
+func (s *studentUseCase) checkVisible(ctx context.Context, viewerID string, id string) error {
+	student, err := s.studentRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+	if viewerID == "" || viewerID == id {
+		return nil
+	}
+	blocked, err := s.studentRepository.IsBlocked(ctx, viewerID, id)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+	return nil
+}
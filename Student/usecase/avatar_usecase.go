@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/airbenders/profile/utils/imaging"
+	"log"
+	"reflect"
+	"time"
+)
+
+// uploadTimeout leaves time to upload the avatar and its thumbnails to the blob store
+const uploadTimeout = 30 * time.Second
+
+// UpdateAvatar checks the image is a JPEG, PNG or WebP of a reasonable size, stores it with its thumbnails and makes
+// it the student's avatar. The previous avatar is deleted once the new one is saved
+func (s *studentUseCase) UpdateAvatar(c context.Context, id string, image []byte) (*domain.Avatar, error) {
+	ctx, cancel := context.WithTimeout(c, uploadTimeout)
+	defer cancel()
+
+	contentType, err := validateAvatar(image)
+	if err != nil {
+		return nil, err
+	}
+	student, err := s.studentRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return nil, errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+
+	now := time.Now().UTC()
+	avatar := &domain.Avatar{
+		ContentType: contentType,
+		Thumbnails:  map[int]string{},
+		UpdatedAt:   now,
+	}
+	keys := avatarKeys(id, avatar)
+	blobs := map[string][]byte{keys[0]: image}
+	for i, size := range domain.AvatarThumbnailSizes {
+		blobs[keys[i+1]], err = imaging.Thumbnail(image, contentType, size)
+		if err != nil {
+			return nil, errors.NewBadRequestError(err.Error())
+		}
+	}
+
+	for i, key := range keys {
+		blobType := contentType
+		if i > 0 {
+			blobType = imaging.ThumbnailType(contentType)
+		}
+		if err = s.blobStore.Put(ctx, key, blobType, blobs[key]); err != nil {
+			s.deleteBlobs(keys)
+			return nil, errors.NewInternalServerError("failed to store the avatar: " + err.Error())
+		}
+	}
+	avatar.URL = s.blobStore.URL(keys[0])
+	for i, size := range domain.AvatarThumbnailSizes {
+		avatar.Thumbnails[size] = s.blobStore.URL(keys[i+1])
+	}
+
+	if err = s.studentRepository.UpdateAvatar(ctx, id, avatar); err != nil {
+		s.deleteBlobs(keys)
+		return nil, err
+	}
+	if student.Avatar != nil {
+		s.deleteBlobs(avatarKeys(id, student.Avatar))
+	}
+	student.Avatar = avatar
+	s.messagingManager.Edited <- *student
+
+	return avatar, nil
+}
+
+// DeleteAvatar removes the avatar of the student and its files, if there's one
+func (s *studentUseCase) DeleteAvatar(c context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	student, err := s.studentRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+	if student.Avatar == nil {
+		return nil
+	}
+
+	if err = s.studentRepository.UpdateAvatar(ctx, id, nil); err != nil {
+		return err
+	}
+	s.deleteBlobs(avatarKeys(id, student.Avatar))
+	student.Avatar = nil
+	s.messagingManager.Edited <- *student
+	return nil
+}
+
+// validateAvatar returns the type of the image, or a 400 if it isn't an image that can be an avatar
+func validateAvatar(image []byte) (string, error) {
+	if len(image) == 0 {
+		return "", errors.NewBadRequestError("no image provided")
+	}
+	if len(image) > domain.MaxAvatarSize {
+		return "", errors.NewBadRequestError(fmt.Sprintf("the image is too large. The most is %d MB",
+			domain.MaxAvatarSize>>20))
+	}
+	contentType, err := imaging.ContentType(image)
+	if err != nil {
+		return "", errors.NewBadRequestError(err.Error())
+	}
+	width, height, err := imaging.Dimensions(image, contentType)
+	if err != nil {
+		return "", errors.NewBadRequestError(err.Error())
+	}
+	if width < domain.MinAvatarDimension || height < domain.MinAvatarDimension ||
+		width > domain.MaxAvatarDimension || height > domain.MaxAvatarDimension {
+		return "", errors.NewBadRequestError(fmt.Sprintf("the image is %dx%d. Expected %d to %d pixels on each side",
+			width, height, domain.MinAvatarDimension, domain.MaxAvatarDimension))
+	}
+	return contentType, nil
+}
+
+// avatarKeys are the blob keys of the avatar, the image first and then the thumbnails in the order of
+// domain.AvatarThumbnailSizes. Every upload gets its own folder so the URLs change and caches don't keep the old one
+func avatarKeys(id string, avatar *domain.Avatar) []string {
+	prefix := fmt.Sprintf("avatars/%s/%d/", id, avatar.UpdatedAt.UnixNano())
+	keys := []string{prefix + "original" + imaging.Extension(avatar.ContentType)}
+	extension := imaging.Extension(imaging.ThumbnailType(avatar.ContentType))
+	for _, size := range domain.AvatarThumbnailSizes {
+		keys = append(keys, fmt.Sprintf("%s%d%s", prefix, size, extension))
+	}
+	return keys
+}
+
+// deleteBlobs deletes what it can. Files left behind only waste space, so failures are logged
+func (s *studentUseCase) deleteBlobs(keys []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.contextTimeout)
+	defer cancel()
+
+	for _, key := range keys {
+		if err := s.blobStore.Delete(ctx, key); err != nil {
+			log.Println("failed to delete blob", key, err)
+		}
+	}
+}
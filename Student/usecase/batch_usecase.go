@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+)
+
+// BatchGet looks up many students at once for other services. Students come back as summaries in the order they were
+// asked for, and the ones that don't exist or are blocked from the viewer are listed in Missing.
+// Reviews are only loaded when include has domain.IncludeReviews, for all the students in one query
+func (s *studentUseCase) BatchGet(c context.Context, viewerID string, ids []string, include []string) (*domain.BatchGetResult, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	ids = removeDuplicates(ids)
+	if len(ids) == 0 || len(ids) > domain.MaxBatchGetIDs {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("provide between 1 and %d student IDs", domain.MaxBatchGetIDs))
+	}
+	withReviews := false
+	for _, name := range include {
+		switch name {
+		case domain.IncludeReviews:
+			withReviews = true
+		default:
+			return nil, errors.NewBadRequestError(fmt.Sprintf("can't include %q. Expected %q", name, domain.IncludeReviews))
+		}
+	}
+
+	students, err := s.studentRepository.GetByIDs(ctx, viewerID, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]domain.Student, len(students))
+	for _, student := range students {
+		found[student.ID] = student
+	}
+
+	reviews := make(map[string][]domain.Review)
+	if withReviews && len(students) > 0 {
+		studentIDs := make([]string, 0, len(students))
+		for _, student := range students {
+			studentIDs = append(studentIDs, student.ID)
+		}
+		all, err := s.reviewRepository.GetReviewsForAll(ctx, studentIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, review := range all {
+			reviews[review.Reviewed.ID] = append(reviews[review.Reviewed.ID], review)
+		}
+	}
+
+	result := &domain.BatchGetResult{Students: []domain.StudentSummary{}, Missing: []string{}}
+	for _, id := range ids {
+		student, ok := found[id]
+		if !ok {
+			result.Missing = append(result.Missing, id)
+			continue
+		}
+		summary := domain.StudentSummary{
+			ID:        student.ID,
+			FirstName: student.FirstName,
+			LastName:  student.LastName,
+			Avatar:    student.Avatar,
+			Reviews:   reviews[id],
+		}
+		result.Students = append(result.Students, summary)
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"github.com/streadway/amqp"
+	"log"
+	"reflect"
+	"time"
+)
+
+// Block blocks or mutes the target for the student. Blocks are sent to the other services so they can enforce them
+func (s *studentUseCase) Block(c context.Context, id string, targetID string, kind domain.BlockKind) error {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	if id == targetID {
+		return errors.NewBadRequestError(fmt.Sprintf("can't %s yourself", kind))
+	}
+	target, err := s.studentRepository.GetByID(ctx, targetID)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(target, &domain.Student{}) {
+		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, targetID))
+	}
+
+	block := domain.Block{
+		BlockerID: id,
+		BlockedID: targetID,
+		Kind:      kind,
+		CreatedAt: time.Now(),
+	}
+	err = s.studentRepository.SaveBlock(ctx, &block)
+	if err != nil {
+		return err
+	}
+	if kind == domain.Blocking {
+		s.messagingManager.Blocked <- block
+	}
+	return nil
+}
+
+// Unblock removes the block or mute of the target by the student
+func (s *studentUseCase) Unblock(c context.Context, id string, targetID string, kind domain.BlockKind) error {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	err := s.studentRepository.DeleteBlock(ctx, id, targetID, kind)
+	if err != nil {
+		return err
+	}
+	if kind == domain.Blocking {
+		s.messagingManager.Unblocked <- domain.Block{BlockerID: id, BlockedID: targetID, Kind: kind}
+	}
+	return nil
+}
+
+// GetBlocks returns the students blocked and muted by the student
+func (s *studentUseCase) GetBlocks(c context.Context, id string) ([]domain.Block, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	return s.studentRepository.GetBlocks(ctx, id)
+}
+
+// BlockStudentTopic sends messages for blocks between students
+func (s *studentUseCase) BlockStudentTopic() {
+	for block := range s.messagingManager.Blocked {
+		s.publishBlock("profile.blocked", block)
+	}
+}
+
+// UnblockStudentTopic sends messages for removed blocks between students
+func (s *studentUseCase) UnblockStudentTopic() {
+	for block := range s.messagingManager.Unblocked {
+		s.publishBlock("profile.unblocked", block)
+	}
+}
+
+func (s *studentUseCase) publishBlock(key string, block domain.Block) {
+	body, err := json.Marshal(block)
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+	err = s.messagingManager.Ch.Publish(
+		"profile",
+		key,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: contentType,
+			Body:        body,
+		})
+	if err != nil {
+		log.Println(publishErrorMessage, err)
+	}
+	log.Println(ampqMessageSent)
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"reflect"
+)
+
+// GetClassmates returns the students of the same school sharing current classes with the student, ranked by the
+// number of shared classes and then by reputation. Students hiding from discovery are left out. The filter needs the
+// student to have picked the campus or the faculty it filters on
+func (s *studentUseCase) GetClassmates(c context.Context, id string, filter domain.ClassmateFilter, page domain.Page) ([]domain.Classmate, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	student, err := s.studentRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return nil, errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+	if student.School == nil {
+		return nil, errors.NewBadRequestError("confirm your school to find classmates")
+	}
+	if filter.SameCampus && student.Campus == nil {
+		return nil, errors.NewBadRequestError("pick your campus to find classmates on it")
+	}
+	if filter.SameFaculty && student.Faculty == nil {
+		return nil, errors.NewBadRequestError("pick your faculty to find classmates in it")
+	}
+	if len(student.CurrentClasses) == 0 {
+		return []domain.Classmate{}, nil
+	}
+
+	classmates, err := s.studentRepository.GetClassmates(ctx, student, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	for i := range classmates {
+		classmates[i].Student.HidePrivateFields(id)
+	}
+	return classmates, nil
+}
+
+// GetPrivacy returns the privacy settings of the student
+func (s *studentUseCase) GetPrivacy(c context.Context, id string) (*domain.Privacy, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	return s.studentRepository.GetPrivacy(ctx, id)
+}
+
+// UpdatePrivacy replaces the privacy settings of the student if it exists
+func (s *studentUseCase) UpdatePrivacy(c context.Context, id string, privacy *domain.Privacy) error {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	if err := validatePrivacy(privacy); err != nil {
+		return err
+	}
+	existingStudent, err := s.studentRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(existingStudent, &domain.Student{}) {
+		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+
+	return s.studentRepository.UpdatePrivacy(ctx, id, privacy)
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"reflect"
+	"time"
+)
+
+// GetOnboarding returns how complete the student's profile is and the steps left to complete it
+func (s *studentUseCase) GetOnboarding(c context.Context, id string) (*domain.Onboarding, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	student, err := s.studentRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return nil, errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+	return s.onboarding(ctx, student)
+}
+
+func (s *studentUseCase) onboarding(ctx context.Context, student *domain.Student) (*domain.Onboarding, error) {
+	availabilities, err := s.studentRepository.GetAvailabilities(ctx, []string{student.ID})
+	if err != nil {
+		return nil, err
+	}
+	var availability *domain.Availability
+	if len(availabilities) > 0 {
+		availability = &availabilities[0]
+	}
+	onboarding := domain.NewOnboarding(student, availability)
+	onboarding.UndeliverableEmail, err = s.studentRepository.GetUndeliverableSchoolEmail(ctx, student.ID,
+		time.Now().Add(-domain.ConfirmationTTL))
+	if err != nil {
+		return nil, err
+	}
+	return onboarding, nil
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/errors"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits on the academic profile fields
+const (
+	maxProgramLength  = 128
+	maxPronounsLength = 32
+	maxLanguages      = 10
+	maxLanguageLength = 32
+	maxYear           = 8
+)
+
+// validateProfile checks and cleans up the academic profile fields of the student. Returns a 400 describing the first
+// invalid field
+func validateProfile(st *domain.Student) error {
+	st.Program = strings.TrimSpace(st.Program)
+	if utf8.RuneCountInString(st.Program) > maxProgramLength {
+		return errors.NewBadRequestError(fmt.Sprintf("program is too long. The most is %d characters", maxProgramLength))
+	}
+	if st.Year < 0 || st.Year > maxYear {
+		return errors.NewBadRequestError(fmt.Sprintf("invalid year of study %d. Expected 1 to %d", st.Year, maxYear))
+	}
+	st.Pronouns = strings.TrimSpace(st.Pronouns)
+	if utf8.RuneCountInString(st.Pronouns) > maxPronounsLength {
+		return errors.NewBadRequestError(fmt.Sprintf("pronouns are too long. The most is %d characters", maxPronounsLength))
+	}
+
+	languages, err := normalizeLanguages(st.Languages)
+	if err != nil {
+		return err
+	}
+	st.Languages = languages
+
+	for _, field := range st.Clear {
+		if !containsField(domain.ClearableFields, field) {
+			return errors.NewBadRequestError(fmt.Sprintf("field %q can't be cleared. Expected one of %s", field,
+				strings.Join(domain.ClearableFields, ", ")))
+		}
+	}
+
+	return validateLinks(&st.Links)
+}
+
+// normalizeLanguages trims and capitalizes the languages and removes duplicates
+func normalizeLanguages(languages []string) ([]string, error) {
+	if languages == nil {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(languages))
+	for _, language := range languages {
+		language = strings.TrimSpace(language)
+		if language == "" {
+			return nil, errors.NewBadRequestError("languages can't be empty")
+		}
+		if utf8.RuneCountInString(language) > maxLanguageLength {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("language %q is too long. The most is %d characters",
+				language, maxLanguageLength))
+		}
+		first, size := utf8.DecodeRuneInString(language)
+		normalized = append(normalized, strings.ToUpper(string(first))+strings.ToLower(language[size:]))
+	}
+	normalized = removeDuplicates(normalized)
+	if len(normalized) > maxLanguages {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("too many languages. The most is %d", maxLanguages))
+	}
+	return normalized, nil
+}
+
+// validateLinks checks that each link is an http(s) URL, and that the GitHub and LinkedIn ones point to a profile on
+// those websites
+func validateLinks(links *domain.StudentLinks) error {
+	var err error
+	links.GitHub, err = validateLink("GitHub", links.GitHub, "github.com", "/")
+	if err != nil {
+		return err
+	}
+	links.LinkedIn, err = validateLink("LinkedIn", links.LinkedIn, "linkedin.com", "/in/")
+	if err != nil {
+		return err
+	}
+	links.Portfolio, err = validateLink("portfolio", links.Portfolio, "", "")
+	return err
+}
+
+// validateLink checks the link is an http(s) URL on the host, or any host if it's empty. Links to a host must have a
+// path after pathPrefix, which is where the profile name is. Empty links are allowed
+func validateLink(name string, link string, host string, pathPrefix string) (string, error) {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return "", nil
+	}
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.NewBadRequestError(fmt.Sprintf("invalid %s link %q. Expected a URL starting with https://",
+			name, link))
+	}
+	if host == "" {
+		return link, nil
+	}
+	hostname := strings.ToLower(u.Hostname())
+	if hostname != host && !strings.HasSuffix(hostname, "."+host) {
+		return "", errors.NewBadRequestError(fmt.Sprintf("invalid %s link %q. Expected a link to %s", name, link, host))
+	}
+	profile := strings.TrimPrefix(u.Path, pathPrefix)
+	if !strings.HasPrefix(u.Path, pathPrefix) || strings.Trim(profile, "/") == "" {
+		return "", errors.NewBadRequestError(fmt.Sprintf(
+			"invalid %s link %q. Expected a link to a profile, like https://%s%susername", name, link, host, pathPrefix))
+	}
+	return link, nil
+}
+
+// validatePrivacy checks that only profile fields that can be hidden are, and removes duplicates
+func validatePrivacy(privacy *domain.Privacy) error {
+	for _, field := range privacy.HiddenFields {
+		if !containsField(domain.HideableFields, field) {
+			return errors.NewBadRequestError(fmt.Sprintf("field %q can't be hidden. Expected one of %s", field,
+				strings.Join(domain.HideableFields, ", ")))
+		}
+	}
+	if privacy.HiddenFields != nil {
+		privacy.HiddenFields = removeDuplicates(privacy.HiddenFields)
+	}
+	return nil
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if field == f {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/streadway/amqp"
 	"log"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -19,29 +20,35 @@ const (
 	contentType          = "text/plain"
 	publishErrorMessage  = "failed to publish "
 	ampqMessageSent      = "student sent to queue"
+	invalidClassMessage  = "invalid class code %q. Expected a subject followed by a number, like COMP 354"
 )
 
 type studentUseCase struct {
 	studentRepository domain.StudentRepository
 	reviewRepository  domain.ReviewRepository
 	tagRepository     domain.TagRepository
+	blobStore         domain.BlobStore
 	contextTimeout    time.Duration
 	messagingManager  *MessagingManager
 }
 
 type MessagingManager struct {
-	Ch      mocks.Channel
-	Created chan domain.Student
-	Edited  chan domain.Student
-	Deleted chan string
+	Ch        mocks.Channel
+	Created   chan domain.Student
+	Edited    chan domain.Student
+	Deleted   chan string
+	Blocked   chan domain.Block
+	Unblocked chan domain.Block
 }
 
 func NewMessagingManager(ch mocks.Channel) *MessagingManager {
 	return &MessagingManager{
-		Ch:      ch,
-		Created: make(chan domain.Student),
-		Edited:  make(chan domain.Student),
-		Deleted: make(chan string),
+		Ch:        ch,
+		Created:   make(chan domain.Student),
+		Edited:    make(chan domain.Student),
+		Deleted:   make(chan string),
+		Blocked:   make(chan domain.Block),
+		Unblocked: make(chan domain.Block),
 	}
 
 }
@@ -51,12 +58,14 @@ func NewStudentUseCase(mm *MessagingManager,
 	sr domain.StudentRepository,
 	rr domain.ReviewRepository,
 	tr domain.TagRepository,
+	bs domain.BlobStore,
 	timeout time.Duration) domain.StudentUseCase {
 	return &studentUseCase{
 		messagingManager:  mm,
 		studentRepository: sr,
 		reviewRepository:  rr,
 		tagRepository:     tr,
+		blobStore:         bs,
 		contextTimeout:    timeout,
 	}
 }
@@ -75,6 +84,9 @@ func (s *studentUseCase) Create(c context.Context, st *domain.Student) error {
 	} else {
 		return errors.NewBadRequestError("The student should have an ID from auth service")
 	}
+	if err := validateProfile(st); err != nil {
+		return err
+	}
 
 	st.CreatedAt = time.Now()
 	st.UpdatedAt = time.Now()
@@ -110,11 +122,16 @@ func (s *studentUseCase) CreateStudentTopic() {
 	}
 }
 
-// GetByID seeks student from repo layer and returns if it exists, else return error
-func (s *studentUseCase) GetByID(c context.Context, id string) (*domain.Student, error) {
+// GetByID seeks student from repo layer and returns if it exists, else return error.
+// Students blocked from each other can't see each other, so they get the same error as for a missing student.
+// Only the relations included by the query are loaded, and the onboarding steps only if they are wanted
+func (s *studentUseCase) GetByID(c context.Context, viewerID string, id string, query domain.ProfileQuery) (*domain.Student, error) {
 	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
 	defer cancel()
 
+	if err := query.Validate(); err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
 	student, err := s.studentRepository.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -123,8 +140,42 @@ func (s *studentUseCase) GetByID(c context.Context, id string) (*domain.Student,
 
 		return nil, errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
 	}
+	if viewerID != "" && viewerID != id {
+		blocked, err := s.studentRepository.IsBlocked(ctx, viewerID, id)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+		}
+	}
+
+	if query.Includes(domain.IncludeReviews) {
+		student.Reviews = s.reviewsWithTags(ctx, student.ID)
+	}
+	if query.Includes(domain.IncludeReputation) {
+		reputation, err := s.studentRepository.GetReputation(ctx, student.ID)
+		if err != nil {
+			log.Println("Can't get the reputation right now.")
+		} else {
+			student.Reputation = &reputation
+		}
+	}
+	student.HidePrivateFields(viewerID)
+	if viewerID == id && query.Wants("onboarding") {
+		student.Onboarding, err = s.onboarding(ctx, student)
+		if err != nil {
+			log.Println("Can't get the onboarding steps right now.")
+		}
+	}
 
-	reviews, err := s.reviewRepository.GetReviewsFor(ctx, student.ID)
+	return student, nil
+}
+
+// reviewsWithTags returns the reviews of the student with whether their tags are positive. Reviews are extras of a
+// profile, so failures are only logged
+func (s *studentUseCase) reviewsWithTags(ctx context.Context, id string) []domain.Review {
+	reviews, err := s.reviewRepository.GetReviewsFor(ctx, id)
 	if err != nil {
 		log.Println("Can't get the reviews right now.")
 	}
@@ -136,17 +187,12 @@ func (s *studentUseCase) GetByID(c context.Context, id string) (*domain.Student,
 	for _, tag := range tags {
 		tagMap[tag.Name] = tag.Positive
 	}
-	populateReview := func(review domain.Review) {
-		for _, reviewTags := range review.Tags {
-			reviewTags.Positive = tagMap[reviewTags.Name]
-		}
-	}
 	for _, review := range reviews {
-		go populateReview(review)
+		for _, reviewTag := range review.Tags {
+			reviewTag.Positive = tagMap[reviewTag.Name]
+		}
 	}
-	student.Reviews = reviews
-
-	return student, nil
+	return reviews
 }
 
 // Update checks if the student exists and updates if so. Otherwise, returns error
@@ -155,6 +201,9 @@ func (s *studentUseCase) Update(c context.Context, id string, st *domain.Student
 	defer cancel()
 
 	st.ID = id
+	if err := validateProfile(st); err != nil {
+		return nil, err
+	}
 	existingStudent, err := s.studentRepository.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -172,9 +221,11 @@ func (s *studentUseCase) Update(c context.Context, id string, st *domain.Student
 	return existingStudent, nil
 }
 
-// UpdateStudentTopic sends messages for creation of student
+// UpdateStudentTopic sends messages for creation of student. The school verification is left out, since every
+// service on the exchange gets the message and the school email is only shown to the student
 func (s *studentUseCase) UpdateStudentTopic() {
 	for student := range s.messagingManager.Edited {
+		student.SchoolVerification = nil
 		st, err := json.Marshal(student)
 		if err != nil {
 			log.Println(err.Error())
@@ -197,6 +248,7 @@ func (s *studentUseCase) UpdateStudentTopic() {
 }
 
 func updateStudent(existing *domain.Student, toUpdate *domain.Student) {
+	clearFields(existing, toUpdate.Clear)
 	if toUpdate.FirstName != "" {
 		existing.FirstName = toUpdate.FirstName
 	}
@@ -209,9 +261,48 @@ func updateStudent(existing *domain.Student, toUpdate *domain.Student) {
 	if toUpdate.GeneralInfo != "" {
 		existing.GeneralInfo = toUpdate.GeneralInfo
 	}
+	if toUpdate.Program != "" {
+		existing.Program = toUpdate.Program
+	}
+	if toUpdate.Year != 0 {
+		existing.Year = toUpdate.Year
+	}
+	if toUpdate.Pronouns != "" {
+		existing.Pronouns = toUpdate.Pronouns
+	}
+	if toUpdate.Languages != nil {
+		existing.Languages = toUpdate.Languages
+	}
+	if toUpdate.Links.GitHub != "" {
+		existing.Links.GitHub = toUpdate.Links.GitHub
+	}
+	if toUpdate.Links.LinkedIn != "" {
+		existing.Links.LinkedIn = toUpdate.Links.LinkedIn
+	}
+	if toUpdate.Links.Portfolio != "" {
+		existing.Links.Portfolio = toUpdate.Links.Portfolio
+	}
 	existing.UpdatedAt = time.Now()
 }
 
+// clearFields removes the optional profile fields, before the new values are copied
+func clearFields(st *domain.Student, fields []string) {
+	for _, field := range fields {
+		switch field {
+		case domain.FieldProgram:
+			st.Program = ""
+		case domain.FieldYear:
+			st.Year = 0
+		case domain.FieldPronouns:
+			st.Pronouns = ""
+		case domain.FieldLanguages:
+			st.Languages = nil
+		case domain.FieldLinks:
+			st.Links = domain.StudentLinks{}
+		}
+	}
+}
+
 // Delete removes the student if it exists. Otherwise, returns error
 func (s *studentUseCase) Delete(c context.Context, id string) error {
 	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
@@ -265,10 +356,41 @@ func (s *studentUseCase) AddClasses(c context.Context, id string, st *domain.Stu
 	if reflect.DeepEqual(existingStudent, &domain.Student{}) {
 		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
 	}
+	if err = normalizeStudentClasses(st); err != nil {
+		return err
+	}
 
+	newClasses := removeClasses(removeDuplicates(st.CurrentClasses), existingStudent.CurrentClasses)
 	st.CurrentClasses = removeDuplicates(append(existingStudent.CurrentClasses, st.CurrentClasses...))
 	st.ClassesTaken = removeDuplicates(append(existingStudent.ClassesTaken, st.ClassesTaken...))
-	return s.studentRepository.UpdateClasses(ctx, st)
+	return s.studentRepository.UpdateClasses(ctx, st, enrollmentsOf(id, newClasses, domain.Enrolled))
+}
+
+// normalizeStudentClasses brings both class lists of the student to the "COMP 354" form. Returns a 400 for the first
+// code that can't be normalized
+func normalizeStudentClasses(st *domain.Student) error {
+	var err error
+	st.CurrentClasses, err = normalizeClasses(st.CurrentClasses)
+	if err != nil {
+		return err
+	}
+	st.ClassesTaken, err = normalizeClasses(st.ClassesTaken)
+	return err
+}
+
+func normalizeClasses(classes []string) ([]string, error) {
+	if classes == nil {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(classes))
+	for _, class := range classes {
+		code, ok := domain.NormalizeClassCode(class)
+		if !ok {
+			return nil, errors.NewBadRequestError(fmt.Sprintf(invalidClassMessage, class))
+		}
+		normalized = append(normalized, code)
+	}
+	return normalized, nil
 }
 
 func removeDuplicates(slice []string) []string {
@@ -298,9 +420,14 @@ func (s *studentUseCase) RemoveClasses(c context.Context, id string, st *domain.
 	if reflect.DeepEqual(existingStudent, &domain.Student{}) {
 		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
 	}
-	st.CurrentClasses = removeClasses(existingStudent.CurrentClasses, st.CurrentClasses)
+	if err = normalizeStudentClasses(st); err != nil {
+		return err
+	}
+	remainingClasses := removeClasses(existingStudent.CurrentClasses, st.CurrentClasses)
+	droppedClasses := removeClasses(existingStudent.CurrentClasses, remainingClasses)
+	st.CurrentClasses = remainingClasses
 	st.ClassesTaken = removeClasses(existingStudent.ClassesTaken, st.ClassesTaken)
-	return s.studentRepository.UpdateClasses(ctx, st)
+	return s.studentRepository.UpdateClasses(ctx, st, enrollmentsOf(id, droppedClasses, domain.Dropped))
 }
 
 func removeClasses(existingClasses, classesToRemove []string) []string {
@@ -330,21 +457,163 @@ func (s *studentUseCase) CompleteClass(c context.Context, id string, st *domain.
 	if reflect.DeepEqual(existingStudent, &domain.Student{}) {
 		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
 	}
+	if err = normalizeStudentClasses(st); err != nil {
+		return err
+	}
 	completedClasses := st.CurrentClasses
 	st.CurrentClasses = removeClasses(existingStudent.CurrentClasses, st.CurrentClasses)
 	st.ClassesTaken = removeDuplicates(append(existingStudent.ClassesTaken, completedClasses...))
-	return s.studentRepository.UpdateClasses(ctx, st)
+	return s.studentRepository.UpdateClasses(ctx, st, enrollmentsOf(id, removeDuplicates(completedClasses), domain.Completed))
+}
+
+// enrollmentsOf are the enrollments recording a change to the classes for the current term, which keep the
+// enrollment history in sync with the classes
+func enrollmentsOf(id string, classes []string, status domain.EnrollmentStatus) []domain.Enrollment {
+	now := time.Now()
+	term := domain.TermOf(now)
+	enrollments := make([]domain.Enrollment, 0, len(classes))
+	for _, class := range classes {
+		enrollments = append(enrollments, domain.Enrollment{
+			StudentID: id,
+			Class:     class,
+			Term:      term,
+			Status:    status,
+			UpdatedAt: now,
+		})
+	}
+	return enrollments
+}
+
+// SaveEnrollments records enrollments for any term, e.g. to fill in past terms or add a section.
+// Enrollments without a term are for the current term and enrollments without a status are enrolled
+func (s *studentUseCase) SaveEnrollments(c context.Context, id string, enrollments []domain.Enrollment) error {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	if len(enrollments) == 0 {
+		return errors.NewBadRequestError("no enrollments provided")
+	}
+	existingStudent, err := s.studentRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(existingStudent, &domain.Student{}) {
+		return errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+
+	now := time.Now()
+	for i := range enrollments {
+		e := &enrollments[i]
+		class, ok := domain.NormalizeClassCode(e.Class)
+		if !ok {
+			return errors.NewBadRequestError(fmt.Sprintf(invalidClassMessage, e.Class))
+		}
+		if e.Status == "" {
+			e.Status = domain.Enrolled
+		}
+		if !e.Status.IsValid() {
+			return errors.NewBadRequestError(fmt.Sprintf("invalid status %q. Expected enrolled, completed or dropped", e.Status))
+		}
+		if e.Term.IsZero() {
+			e.Term = domain.TermOf(now)
+		}
+		e.StudentID = id
+		e.Class = class
+		e.Section = strings.TrimSpace(e.Section)
+		e.UpdatedAt = now
+	}
+	return s.studentRepository.SaveEnrollments(ctx, enrollments)
+}
+
+// GetEnrollments returns the enrollment history of the student
+func (s *studentUseCase) GetEnrollments(c context.Context, id string) ([]domain.Enrollment, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	return s.studentRepository.GetEnrollments(ctx, id)
+}
+
+// GetClassmatesInTerm returns the other students of the student's school who took the class during the term
+func (s *studentUseCase) GetClassmatesInTerm(c context.Context, id string, class string, term domain.Term) ([]domain.Student, error) {
+	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
+	defer cancel()
+
+	code, ok := domain.NormalizeClassCode(class)
+	if !ok {
+		return nil, errors.NewBadRequestError(fmt.Sprintf(invalidClassMessage, class))
+	}
+	student, err := s.studentRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(student, &domain.Student{}) {
+		return nil, errors.NewNotFoundError(fmt.Sprintf(errorMessage, id))
+	}
+	if student.School == nil {
+		return nil, errors.NewBadRequestError("confirm your school to find classmates")
+	}
+
+	students, err := s.studentRepository.GetClassmatesInTerm(ctx, id, student.School.ID, code, term)
+	if err != nil {
+		return nil, err
+	}
+	classmates := make([]domain.Student, 0, len(students))
+	for _, classmate := range students {
+		if classmate.ID != id {
+			classmate.HidePrivateFields(id)
+			classmates = append(classmates, classmate)
+		}
+	}
+	return classmates, nil
+}
+
+// RollOverTerms completes the classes of the terms that are over, then checks again every interval.
+// Meant to be run in its own goroutine
+func (s *studentUseCase) RollOverTerms(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		s.completeEndedTerms()
+	}
 }
 
-func (s *studentUseCase) SearchStudents(c context.Context, st *domain.Student) ([]domain.Student, error) {
+func (s *studentUseCase) completeEndedTerms() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.contextTimeout)
+	defer cancel()
+
+	count, err := s.studentRepository.CompleteEndedTerms(ctx, time.Now())
+	if err != nil {
+		log.Println("failed to roll over terms", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("completed the classes of %d students whose term ended\n", count)
+	}
+}
+
+// SearchStudents finds the students by name, classes and profile fields, leaving out the ones hidden from the viewer.
+// Filters only match the profile fields the students share with the viewer
+func (s *studentUseCase) SearchStudents(c context.Context, viewerID string, st *domain.Student) ([]domain.Student, error) {
 	ctx, cancel := context.WithTimeout(c, s.contextTimeout)
 	defer cancel()
 
-	retrievedStudents, err := s.studentRepository.SearchStudents(ctx, st)
+	classes, err := normalizeClasses(st.CurrentClasses)
+	if err != nil {
+		return nil, err
+	}
+	st.CurrentClasses = classes
+	if err = validateProfile(st); err != nil {
+		return nil, err
+	}
+
+	retrievedStudents, err := s.studentRepository.SearchStudents(ctx, viewerID, st)
 
 	if err != nil {
 		return nil, err
 	}
+	for i := range retrievedStudents {
+		retrievedStudents[i].HidePrivateFields(viewerID)
+	}
 
 	return retrievedStudents, nil
 }
@@ -43,3 +43,120 @@ func TestUpdateStudent(t *testing.T) {
 	existing.UpdatedAt = expected.UpdatedAt
 	assert.EqualValues(t, expected, existing)
 }
+
+func TestUpdateStudentProfile(t *testing.T) {
+	existing := &domain.Student{
+		ID:        "asd",
+		Program:   "Computer Science",
+		Year:      2,
+		Languages: []string{"English"},
+		Links:     domain.StudentLinks{GitHub: "https://github.com/sunny"},
+	}
+	toUpdate := &domain.Student{
+		Year:  3,
+		Links: domain.StudentLinks{LinkedIn: "https://www.linkedin.com/in/sunny"},
+	}
+
+	updateStudent(existing, toUpdate)
+
+	assert.Equal(t, "Computer Science", existing.Program)
+	assert.Equal(t, 3, existing.Year)
+	assert.EqualValues(t, []string{"English"}, existing.Languages)
+	assert.EqualValues(t, domain.StudentLinks{GitHub: "https://github.com/sunny",
+		LinkedIn: "https://www.linkedin.com/in/sunny"}, existing.Links)
+}
+
+func TestUpdateStudentClear(t *testing.T) {
+	existing := &domain.Student{
+		ID:        "asd",
+		Program:   "Computer Science",
+		Year:      2,
+		Pronouns:  "she/her",
+		Languages: []string{"English"},
+		Links:     domain.StudentLinks{GitHub: "https://github.com/sunny", Portfolio: "https://sunny.dev"},
+	}
+	toUpdate := &domain.Student{
+		Clear: []string{domain.FieldYear, domain.FieldLanguages, domain.FieldLinks},
+		Links: domain.StudentLinks{LinkedIn: "https://www.linkedin.com/in/sunny"},
+	}
+
+	updateStudent(existing, toUpdate)
+
+	assert.Equal(t, "Computer Science", existing.Program)
+	assert.Equal(t, 0, existing.Year)
+	assert.Equal(t, "she/her", existing.Pronouns)
+	assert.Nil(t, existing.Languages)
+	assert.EqualValues(t, domain.StudentLinks{LinkedIn: "https://www.linkedin.com/in/sunny"}, existing.Links)
+}
+
+func TestValidateProfile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		st := &domain.Student{
+			Program:   " Software Engineering ",
+			Year:      4,
+			Pronouns:  "they/them",
+			Languages: []string{"english", " French", "ENGLISH"},
+			Links: domain.StudentLinks{
+				GitHub:    "https://github.com/someone",
+				LinkedIn:  "https://ca.linkedin.com/in/someone/",
+				Portfolio: "http://someone.dev",
+			},
+		}
+
+		err := validateProfile(st)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Software Engineering", st.Program)
+		assert.EqualValues(t, []string{"English", "French"}, st.Languages)
+	})
+
+	invalid := map[string]domain.Student{
+		"year":             {Year: 12},
+		"empty-language":   {Languages: []string{"English", " "}},
+		"github-host":      {Links: domain.StudentLinks{GitHub: "https://gitlab.com/someone"}},
+		"github-no-user":   {Links: domain.StudentLinks{GitHub: "https://github.com/"}},
+		"linkedin-company": {Links: domain.StudentLinks{LinkedIn: "https://linkedin.com/company/someone"}},
+		"portfolio-scheme": {Links: domain.StudentLinks{Portfolio: "ftp://someone.dev"}},
+		"portfolio-no-url": {Links: domain.StudentLinks{Portfolio: "someone.dev"}},
+		"clear-name":       {Clear: []string{"first_name"}},
+	}
+	for name, st := range invalid {
+		st := st
+		t.Run(name, func(t *testing.T) {
+			err := validateProfile(&st)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidatePrivacy(t *testing.T) {
+	privacy := &domain.Privacy{HiddenFields: []string{domain.FieldYear, domain.FieldLinks, domain.FieldYear}}
+
+	assert.NoError(t, validatePrivacy(privacy))
+	assert.EqualValues(t, []string{domain.FieldYear, domain.FieldLinks}, privacy.HiddenFields)
+	assert.Error(t, validatePrivacy(&domain.Privacy{HiddenFields: []string{"first_name"}}))
+}
+
+func TestNormalizeClasses(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		classes, err := normalizeClasses([]string{"COMP 354", "comp354", "COMP-354", " Soen  490 ", "engr_201a"})
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, []string{"COMP 354", "COMP 354", "COMP 354", "SOEN 490", "ENGR 201A"}, classes)
+	})
+
+	t.Run("invalid-code", func(t *testing.T) {
+		classes, err := normalizeClasses([]string{"COMP 354", "software engineering"})
+
+		assert.Error(t, err)
+		assert.Nil(t, classes)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		classes, err := normalizeClasses(nil)
+
+		assert.NoError(t, err)
+		assert.Nil(t, classes)
+	})
+}
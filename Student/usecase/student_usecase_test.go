@@ -1,15 +1,21 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/airbenders/profile/Student/usecase"
 	"github.com/airbenders/profile/domain"
 	"github.com/airbenders/profile/domain/mocks"
 	mocks2 "github.com/airbenders/profile/utils/channelmocks"
+	e "github.com/airbenders/profile/utils/errors"
 	"github.com/bxcodec/faker"
+	"github.com/streadway/amqp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"image"
+	"image/png"
 	"reflect"
 	"testing"
 	"time"
@@ -33,7 +39,7 @@ func TestCreate(t *testing.T) {
 			On("Create", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(studentType)).
 			Return(nil).
 			Once()
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		var student domain.Student
 		go func() {
 			student = <-mm.Created
@@ -55,7 +61,7 @@ func TestCreate(t *testing.T) {
 			On("Create", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(studentType)).
 			Return(errors.New("error")).
 			Once()
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 
 		err := u.Create(context.TODO(), &mockStudent)
 
@@ -69,7 +75,7 @@ func TestCreate(t *testing.T) {
 			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
 			Return(&mockStudent, nil).
 			Once()
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 
 		err := u.Create(context.TODO(), &mockStudent)
 
@@ -77,6 +83,22 @@ func TestCreate(t *testing.T) {
 
 		mockStudentRepo.AssertExpectations(t)
 	})
+
+	t.Run("case invalid-profile", func(t *testing.T) {
+		mockStudentRepo.
+			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
+			Return(nil, errors.New("error")).
+			Once()
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
+		invalid := mockStudent
+		invalid.Links = domain.StudentLinks{GitHub: "https://gitlab.com/someone"}
+
+		err := u.Create(context.TODO(), &invalid)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockStudentRepo.AssertExpectations(t)
+	})
 }
 
 func TestCreateStudentTopic(t *testing.T) {
@@ -88,7 +110,7 @@ func TestCreateStudentTopic(t *testing.T) {
 			Return(nil).
 			Twice()
 
-		u := usecase.NewStudentUseCase(mm, nil, nil, nil, time.Second)
+		u := usecase.NewStudentUseCase(mm, nil, nil, nil, nil, time.Second)
 		go u.CreateStudentTopic()
 		mm.Created <- domain.Student{}
 		mm.Created <- domain.Student{}
@@ -108,6 +130,7 @@ func TestGetByID(t *testing.T) {
 	mm := usecase.NewMessagingManager(channelMock)
 	channelMock.On("Publish", mock.AnythingOfType("string"), mock.AnythingOfType("string"),
 		mock.AnythingOfType("bool"), mock.AnythingOfType("bool"), mock.Anything)
+	withReviews := domain.ProfileQuery{Include: []string{domain.IncludeReviews}}
 
 	t.Run("case success", func(t *testing.T) {
 		mockStudentRepo.
@@ -119,24 +142,42 @@ func TestGetByID(t *testing.T) {
 			Return([]domain.Review{domain.Review{}, domain.Review{}}, nil).
 			Once()
 		mockTagRepo.On("FetchAllTags", mock.Anything).Return([]domain.Tag{}, nil).Once()
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, time.Second)
+		mockStudentRepo.On("GetAvailabilities", mock.Anything, []string{mockStudent.ID}).
+			Return([]domain.Availability{}, nil).Once()
+		mockStudentRepo.On("GetUndeliverableSchoolEmail", mock.Anything, mockStudent.ID, mock.Anything).
+			Return(nil, nil).Once()
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
 
-		student, err := u.GetByID(context.TODO(), mockStudent.ID)
+		student, err := u.GetByID(context.TODO(), mockStudent.ID, mockStudent.ID, withReviews)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, student)
+		assert.Contains(t, student.Onboarding.Missing, domain.StepSetAvailability)
 
 		mockStudentRepo.AssertExpectations(t)
 	})
 
+	t.Run("reviews-in-fields", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, mockStudent.ID).Return(&mockStudent, nil).Once()
+		mockReviewRepo.On("GetReviewsFor", mock.Anything, mockStudent.ID).Return([]domain.Review{{}}, nil).Once()
+		mockTagRepo.On("FetchAllTags", mock.Anything).Return([]domain.Tag{}, nil).Once()
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
+
+		student, err := u.GetByID(context.TODO(), "", mockStudent.ID, domain.ProfileQuery{Fields: []string{"reviews"}})
+
+		assert.NoError(t, err)
+		assert.Len(t, student.Reviews, 1)
+		mockReviewRepo.AssertExpectations(t)
+	})
+
 	t.Run("case error", func(t *testing.T) {
 		mockStudentRepo.
 			On("GetByID", mock.Anything, mock.AnythingOfType("string")).
 			Return(nil, errors.New("error")).
 			Once()
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
 
-		student, err := u.GetByID(context.TODO(), mockStudent.ID)
+		student, err := u.GetByID(context.TODO(), mockStudent.ID, mockStudent.ID, withReviews)
 
 		assert.Error(t, err)
 		assert.True(t, reflect.ValueOf(student).IsNil())
@@ -150,15 +191,104 @@ func TestGetByID(t *testing.T) {
 			Return(&domain.Student{}, nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
 
-		student, err := u.GetByID(context.TODO(), mockStudent.ID)
+		student, err := u.GetByID(context.TODO(), mockStudent.ID, mockStudent.ID, withReviews)
 
 		assert.Error(t, err)
 		assert.True(t, reflect.ValueOf(student).IsNil())
 
 		mockStudentRepo.AssertExpectations(t)
 	})
+
+	t.Run("case blocked", func(t *testing.T) {
+		mockStudentRepo.
+			On("GetByID", mock.Anything, mockStudent.ID).
+			Return(&mockStudent, nil).
+			Once()
+		mockStudentRepo.On("IsBlocked", mock.Anything, "viewer", mockStudent.ID).Return(true, nil).Once()
+
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
+
+		student, err := u.GetByID(context.TODO(), "viewer", mockStudent.ID, withReviews)
+
+		assert.Error(t, err)
+		assert.Equal(t, 404, err.(*e.RestError).Code)
+		assert.Nil(t, student)
+
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("case hidden-fields", func(t *testing.T) {
+		withProfile := mockStudent
+		withProfile.Onboarding = nil
+		withProfile.Program = "Software Engineering"
+		withProfile.Pronouns = "she/her"
+		withProfile.Privacy = domain.Privacy{HiddenFields: []string{domain.FieldProgram}}
+		mockStudentRepo.On("GetByID", mock.Anything, mockStudent.ID).Return(&withProfile, nil).Once()
+		mockStudentRepo.On("IsBlocked", mock.Anything, "viewer", mockStudent.ID).Return(false, nil).Once()
+		mockReviewRepo.On("GetReviewsFor", mock.Anything, mockStudent.ID).Return([]domain.Review{}, nil).Once()
+		mockTagRepo.On("FetchAllTags", mock.Anything).Return([]domain.Tag{}, nil).Once()
+
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
+
+		student, err := u.GetByID(context.TODO(), "viewer", mockStudent.ID, withReviews)
+
+		assert.NoError(t, err)
+		assert.Empty(t, student.Program)
+		assert.Equal(t, "she/her", student.Pronouns)
+		assert.Nil(t, student.Onboarding)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("case includes", func(t *testing.T) {
+		withSchool := mockStudent
+		withSchool.Onboarding = nil
+		school := &domain.School{ID: "concordia", Name: "Concordia University", Country: "Canada"}
+		withSchool.School = school
+		mockStudentRepo.On("GetByID", mock.Anything, mockStudent.ID).Return(&withSchool, nil).Once()
+		mockStudentRepo.On("IsBlocked", mock.Anything, "viewer", mockStudent.ID).Return(false, nil).Once()
+		mockStudentRepo.On("GetReputation", mock.Anything, mockStudent.ID).Return(3, nil).Once()
+
+		unusedReviewRepo := new(mocks.ReviewRepositoryMock)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, unusedReviewRepo, mockTagRepo, nil, time.Second)
+		query := domain.ProfileQuery{Include: []string{domain.IncludeSchool, domain.IncludeReputation}}
+
+		student, err := u.GetByID(context.TODO(), "viewer", mockStudent.ID, query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, school, student.School)
+		assert.Equal(t, 3, *student.Reputation)
+		mockStudentRepo.AssertExpectations(t)
+		unusedReviewRepo.AssertNotCalled(t, "GetReviewsFor", mock.Anything, mockStudent.ID)
+	})
+
+	t.Run("case only-fields", func(t *testing.T) {
+		withoutOnboarding := mockStudent
+		withoutOnboarding.Onboarding = nil
+		mockStudentRepo.On("GetByID", mock.Anything, mockStudent.ID).Return(&withoutOnboarding, nil).Once()
+
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+		query := domain.ProfileQuery{Fields: []string{"first_name"}}
+
+		student, err := u.GetByID(context.TODO(), mockStudent.ID, mockStudent.ID, query)
+
+		assert.NoError(t, err)
+		assert.Nil(t, student.Onboarding)
+		assert.Nil(t, student.Reputation)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("case err-invalid-query", func(t *testing.T) {
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
+		query := domain.ProfileQuery{Include: []string{"friends"}}
+
+		student, err := u.GetByID(context.TODO(), "viewer", mockStudent.ID, query)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		assert.Nil(t, student)
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -181,7 +311,7 @@ func TestUpdate(t *testing.T) {
 			Return(nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
 		var student domain.Student
 		go func() {
 			student = <-mm.Edited
@@ -202,7 +332,7 @@ func TestUpdate(t *testing.T) {
 			Return(nil, errors.New("error")).
 			Once()
 
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
 		_, err := u.Update(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.Error(t, err)
@@ -216,7 +346,7 @@ func TestUpdate(t *testing.T) {
 			Return(&domain.Student{}, nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, mockTagRepo, nil, time.Second)
 		_, err := u.Update(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.Error(t, err)
@@ -234,13 +364,32 @@ func TestUpdateStudentTopic(t *testing.T) {
 			Return(nil).
 			Twice()
 
-		u := usecase.NewStudentUseCase(mm, nil, nil, nil, time.Second)
+		u := usecase.NewStudentUseCase(mm, nil, nil, nil, nil, time.Second)
 		go u.UpdateStudentTopic()
 		mm.Edited <- domain.Student{}
 		mm.Edited <- domain.Student{}
 		time.Sleep(10 * time.Millisecond)
 		channelMock.AssertExpectations(t)
 	})
+
+	t.Run("without-school-verification", func(t *testing.T) {
+		channelMock := new(mocks2.ChannelMock)
+		mm := usecase.NewMessagingManager(channelMock)
+		published := make(chan []byte, 1)
+		channelMock.On("Publish", "profile", "profile.updated", false, false, mock.Anything).
+			Run(func(args mock.Arguments) { published <- args.Get(4).(amqp.Publishing).Body }).
+			Return(nil).Once()
+
+		u := usecase.NewStudentUseCase(mm, nil, nil, nil, nil, time.Second)
+		go u.UpdateStudentTopic()
+		mm.Edited <- domain.Student{ID: "a", SchoolVerification: &domain.SchoolVerification{Email: "a@concordia.ca",
+			VerifiedAt: time.Now()}}
+
+		body := <-published
+		assert.Contains(t, string(body), `"id":"a"`)
+		assert.NotContains(t, string(body), "school_verification")
+		assert.NotContains(t, string(body), "a@concordia.ca")
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -262,7 +411,7 @@ func TestDelete(t *testing.T) {
 			Return(nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		go func() {
 			id := <-mm.Deleted
 			assert.Equal(t, mockStudent.ID, id)
@@ -279,7 +428,7 @@ func TestDelete(t *testing.T) {
 			Return(nil, errors.New("error")).
 			Once()
 
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.Delete(context.TODO(), mockStudent.ID)
 
 		assert.Error(t, err)
@@ -293,7 +442,7 @@ func TestDelete(t *testing.T) {
 			Return(&domain.Student{}, nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.Delete(context.TODO(), mockStudent.ID)
 
 		assert.Error(t, err)
@@ -311,7 +460,7 @@ func TestDeleteStudentTopic(t *testing.T) {
 			Return(nil).
 			Twice()
 
-		u := usecase.NewStudentUseCase(mm, nil, nil, nil, time.Second)
+		u := usecase.NewStudentUseCase(mm, nil, nil, nil, nil, time.Second)
 		go u.DeleteStudentTopic()
 		mm.Deleted <- "asd"
 		mm.Deleted <- "cde"
@@ -332,11 +481,11 @@ func TestAddClasses(t *testing.T) {
 			Return(&mockStudent, nil).
 			Once()
 		mockStudentRepo.
-			On("UpdateClasses", mock.Anything, mock.AnythingOfType("*domain.Student")).
+			On("UpdateClasses", mock.Anything, mock.AnythingOfType("*domain.Student"), mock.Anything).
 			Return(nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.AddClasses(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.NoError(t, err)
@@ -350,7 +499,7 @@ func TestAddClasses(t *testing.T) {
 			Return(nil, errors.New("error")).
 			Once()
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.AddClasses(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.Error(t, err)
@@ -364,7 +513,7 @@ func TestAddClasses(t *testing.T) {
 			Return(&domain.Student{}, nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.AddClasses(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.Error(t, err)
@@ -385,11 +534,11 @@ func TestRemoveClasses(t *testing.T) {
 			Return(&mockStudent, nil).
 			Once()
 		mockStudentRepo.
-			On("UpdateClasses", mock.Anything, mock.AnythingOfType("*domain.Student")).
+			On("UpdateClasses", mock.Anything, mock.AnythingOfType("*domain.Student"), mock.Anything).
 			Return(nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.RemoveClasses(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.NoError(t, err)
@@ -403,7 +552,7 @@ func TestRemoveClasses(t *testing.T) {
 			Return(nil, errors.New("error")).
 			Once()
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.RemoveClasses(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.Error(t, err)
@@ -417,7 +566,7 @@ func TestRemoveClasses(t *testing.T) {
 			Return(&domain.Student{}, nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.RemoveClasses(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.Error(t, err)
@@ -438,11 +587,11 @@ func TestCompleteClass(t *testing.T) {
 			Return(&mockStudent, nil).
 			Once()
 		mockStudentRepo.
-			On("UpdateClasses", mock.Anything, mock.AnythingOfType("*domain.Student")).
+			On("UpdateClasses", mock.Anything, mock.AnythingOfType("*domain.Student"), mock.Anything).
 			Return(nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.CompleteClass(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.NoError(t, err)
@@ -456,7 +605,7 @@ func TestCompleteClass(t *testing.T) {
 			Return(nil, errors.New("error")).
 			Once()
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.CompleteClass(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.Error(t, err)
@@ -470,7 +619,7 @@ func TestCompleteClass(t *testing.T) {
 			Return(&domain.Student{}, nil).
 			Once()
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 		err := u.CompleteClass(context.TODO(), mockStudent.ID, &mockStudent)
 
 		assert.Error(t, err)
@@ -489,16 +638,16 @@ func TestSearchStudents(t *testing.T) {
 
 	t.Run("case success", func(t *testing.T) {
 		mockStudentRepo.
-			On("SearchStudents", mock.Anything, mock.AnythingOfType("*domain.Student")).
+			On("SearchStudents", mock.Anything, "viewer", mock.AnythingOfType("*domain.Student")).
 			Return(retrievedStudents, nil).
 			Once()
 		mockReviewRepo.
 			On("GetReviewsFor", mock.Anything, mock.AnythingOfType("string")).
 			Return([]domain.Review{domain.Review{}, domain.Review{}}, nil)
 
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo,  nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo,  nil, nil, time.Second)
 
-		student, err := u.SearchStudents(context.TODO(), &mockStudent)
+		student, err := u.SearchStudents(context.TODO(), "viewer", &mockStudent)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, student)
@@ -508,12 +657,12 @@ func TestSearchStudents(t *testing.T) {
 
 	t.Run("internal error", func(t *testing.T) {
 		mockStudentRepo.
-			On("SearchStudents", mock.Anything, mock.AnythingOfType("*domain.Student")).
+			On("SearchStudents", mock.Anything, "viewer", mock.AnythingOfType("*domain.Student")).
 			Return(nil, errors.New("error retrieving students")).
 			Once()
-		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, time.Second)
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
 
-		student, err := u.SearchStudents(context.TODO(), &mockStudent)
+		student, err := u.SearchStudents(context.TODO(), "viewer", &mockStudent)
 
 		assert.Error(t, err)
 		assert.True(t, reflect.ValueOf(student).IsNil())
@@ -522,3 +671,733 @@ func TestSearchStudents(t *testing.T) {
 	})
 
 }
+
+func TestClassEnrollments(t *testing.T) {
+	mockStudentRepo := new(mocks.StudentRepositoryMock)
+	existing := &domain.Student{ID: "abc", CurrentClasses: []string{"COMP 352"}}
+	term := domain.TermOf(time.Now())
+	isEnrollment := func(class string, status domain.EnrollmentStatus) interface{} {
+		return mock.MatchedBy(func(enrollments []domain.Enrollment) bool {
+			return len(enrollments) == 1 && enrollments[0].StudentID == "abc" && enrollments[0].Class == class &&
+				enrollments[0].Status == status && enrollments[0].Term == term
+		})
+	}
+
+	t.Run("add-records-new-classes-only", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(existing, nil).Once()
+		mockStudentRepo.On("UpdateClasses", mock.Anything, mock.AnythingOfType("*domain.Student"),
+			isEnrollment("COMP 354", domain.Enrolled)).Return(nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.AddClasses(context.TODO(), "abc", &domain.Student{CurrentClasses: []string{"comp352", "comp-354"}})
+
+		assert.NoError(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("remove-drops-current-classes", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(existing, nil).Once()
+		mockStudentRepo.On("UpdateClasses", mock.Anything, mock.AnythingOfType("*domain.Student"),
+			isEnrollment("COMP 352", domain.Dropped)).Return(nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.RemoveClasses(context.TODO(), "abc", &domain.Student{CurrentClasses: []string{"COMP 352", "COMP 999"}})
+
+		assert.NoError(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("complete-marks-completed", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(existing, nil).Once()
+		mockStudentRepo.On("UpdateClasses", mock.Anything, mock.AnythingOfType("*domain.Student"),
+			isEnrollment("COMP 352", domain.Completed)).Return(nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.CompleteClass(context.TODO(), "abc", &domain.Student{CurrentClasses: []string{"COMP 352"}})
+
+		assert.NoError(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid-class-code", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(existing, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.AddClasses(context.TODO(), "abc", &domain.Student{CurrentClasses: []string{"software"}})
+
+		assert.Error(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+}
+
+func TestSaveEnrollments(t *testing.T) {
+	mockStudentRepo := new(mocks.StudentRepositoryMock)
+	existing := &domain.Student{ID: "abc"}
+
+	t.Run("success-with-defaults", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(existing, nil).Once()
+		mockStudentRepo.On("SaveEnrollments", mock.Anything, mock.MatchedBy(func(enrollments []domain.Enrollment) bool {
+			return enrollments[0].Class == "COMP 354" && enrollments[0].Status == domain.Enrolled &&
+				enrollments[0].Term == domain.TermOf(time.Now()) && enrollments[0].Section == "PP"
+		})).Return(nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.SaveEnrollments(context.TODO(), "abc", []domain.Enrollment{{Class: "comp354", Section: " PP "}})
+
+		assert.NoError(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid-status", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(existing, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.SaveEnrollments(context.TODO(), "abc", []domain.Enrollment{{Class: "COMP 354", Status: "failed"}})
+
+		assert.Error(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("no-enrollments", func(t *testing.T) {
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.SaveEnrollments(context.TODO(), "abc", nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestGetClassmatesInTerm(t *testing.T) {
+	mockStudentRepo := new(mocks.StudentRepositoryMock)
+	term := domain.Term{Season: domain.Winter, Year: 2026}
+
+	t.Run("success-excludes-self", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").
+			Return(&domain.Student{ID: "abc", School: &domain.School{ID: "sc"}}, nil).Once()
+		mockStudentRepo.On("GetClassmatesInTerm", mock.Anything, "abc", "sc", "COMP 354", term).
+			Return([]domain.Student{{ID: "abc"}, {ID: "def"}}, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		students, err := u.GetClassmatesInTerm(context.TODO(), "abc", "comp354", term)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, []domain.Student{{ID: "def"}}, students)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("school-not-confirmed", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		students, err := u.GetClassmatesInTerm(context.TODO(), "abc", "COMP 354", term)
+
+		assert.Error(t, err)
+		assert.Nil(t, students)
+		mockStudentRepo.AssertExpectations(t)
+	})
+}
+
+func TestRollOverTerms(t *testing.T) {
+	mockStudentRepo := new(mocks.StudentRepositoryMock)
+	mockStudentRepo.On("CompleteEndedTerms", mock.Anything, mock.AnythingOfType("time.Time")).
+		Return(int64(2), nil)
+
+	u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+	go u.RollOverTerms(time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+
+	mockStudentRepo.AssertCalled(t, "CompleteEndedTerms", mock.Anything, mock.AnythingOfType("time.Time"))
+}
+
+func TestGetClassmates(t *testing.T) {
+	mockStudentRepo := new(mocks.StudentRepositoryMock)
+	page := domain.Page{Number: 1, Size: 20}
+
+	t.Run("success", func(t *testing.T) {
+		student := &domain.Student{ID: "abc", School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 354"}}
+		classmates := []domain.Classmate{{Student: domain.Student{ID: "def"}, SharedClasses: []string{"COMP 354"}}}
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(student, nil).Once()
+		mockStudentRepo.On("GetClassmates", mock.Anything, student, domain.ClassmateFilter{}, page).
+			Return(classmates, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		returned, err := u.GetClassmates(context.TODO(), "abc", domain.ClassmateFilter{}, page)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, classmates, returned)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("no-current-classes", func(t *testing.T) {
+		student := &domain.Student{ID: "abc", School: &domain.School{ID: "sc"}}
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(student, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		returned, err := u.GetClassmates(context.TODO(), "abc", domain.ClassmateFilter{}, page)
+
+		assert.NoError(t, err)
+		assert.Empty(t, returned)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("school-not-confirmed", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		returned, err := u.GetClassmates(context.TODO(), "abc", domain.ClassmateFilter{}, page)
+
+		assert.Error(t, err)
+		assert.Nil(t, returned)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("same-campus", func(t *testing.T) {
+		student := &domain.Student{ID: "abc", School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 354"},
+			Campus: &domain.Campus{ID: "sgw"}}
+		filter := domain.ClassmateFilter{SameCampus: true}
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(student, nil).Once()
+		mockStudentRepo.On("GetClassmates", mock.Anything, student, filter, page).
+			Return([]domain.Classmate{}, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		returned, err := u.GetClassmates(context.TODO(), "abc", filter, page)
+
+		assert.NoError(t, err)
+		assert.Empty(t, returned)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("same-faculty-without-faculty", func(t *testing.T) {
+		student := &domain.Student{ID: "abc", School: &domain.School{ID: "sc"}, CurrentClasses: []string{"COMP 354"},
+			Campus: &domain.Campus{ID: "sgw"}}
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(student, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		returned, err := u.GetClassmates(context.TODO(), "abc", domain.ClassmateFilter{SameFaculty: true}, page)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		assert.Nil(t, returned)
+		mockStudentRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdatePrivacy(t *testing.T) {
+	mockStudentRepo := new(mocks.StudentRepositoryMock)
+	privacy := &domain.Privacy{HideFromDiscovery: true}
+
+	t.Run("success", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+		mockStudentRepo.On("UpdatePrivacy", mock.Anything, "abc", privacy).Return(nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.UpdatePrivacy(context.TODO(), "abc", privacy)
+
+		assert.NoError(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("err-empty-student", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{}, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.UpdatePrivacy(context.TODO(), "abc", privacy)
+
+		assert.Error(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("err-field-cant-be-hidden", func(t *testing.T) {
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.UpdatePrivacy(context.TODO(), "abc", &domain.Privacy{HiddenFields: []string{"email"}})
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockStudentRepo.AssertExpectations(t)
+	})
+}
+
+func TestBlock(t *testing.T) {
+	mockStudentRepo := new(mocks.StudentRepositoryMock)
+	channelMock := new(mocks2.ChannelMock)
+	mm := usecase.NewMessagingManager(channelMock)
+
+	t.Run("block-success", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "def").Return(&domain.Student{ID: "def"}, nil).Once()
+		mockStudentRepo.On("SaveBlock", mock.Anything, mock.AnythingOfType("*domain.Block")).Return(nil).Once()
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+		var block domain.Block
+		done := make(chan bool)
+		go func() {
+			block = <-mm.Blocked
+			done <- true
+		}()
+
+		err := u.Block(context.TODO(), "abc", "def", domain.Blocking)
+		<-done
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", block.BlockerID)
+		assert.Equal(t, "def", block.BlockedID)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("mute-is-not-published", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "def").Return(&domain.Student{ID: "def"}, nil).Once()
+		mockStudentRepo.On("SaveBlock", mock.Anything, mock.AnythingOfType("*domain.Block")).Return(nil).Once()
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+
+		err := u.Block(context.TODO(), "abc", "def", domain.Muting)
+
+		assert.NoError(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("self", func(t *testing.T) {
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+
+		err := u.Block(context.TODO(), "abc", "abc", domain.Blocking)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+	})
+
+	t.Run("target-not-found", func(t *testing.T) {
+		mockStudentRepo.On("GetByID", mock.Anything, "def").Return(&domain.Student{}, nil).Once()
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+
+		err := u.Block(context.TODO(), "abc", "def", domain.Blocking)
+
+		assert.Error(t, err)
+		assert.Equal(t, 404, err.(*e.RestError).Code)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("unblock-success", func(t *testing.T) {
+		mockStudentRepo.On("DeleteBlock", mock.Anything, "abc", "def", domain.Blocking).Return(nil).Once()
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+		var block domain.Block
+		done := make(chan bool)
+		go func() {
+			block = <-mm.Unblocked
+			done <- true
+		}()
+
+		err := u.Unblock(context.TODO(), "abc", "def", domain.Blocking)
+		<-done
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Block{BlockerID: "abc", BlockedID: "def", Kind: domain.Blocking}, block)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("unblock-error", func(t *testing.T) {
+		mockStudentRepo.On("DeleteBlock", mock.Anything, "abc", "def", domain.Muting).Return(errors.New("error")).Once()
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+
+		err := u.Unblock(context.TODO(), "abc", "def", domain.Muting)
+
+		assert.Error(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+}
+
+func TestBlockStudentTopics(t *testing.T) {
+	channelMock := new(mocks2.ChannelMock)
+	mm := usecase.NewMessagingManager(channelMock)
+	t.Run("success", func(t *testing.T) {
+		channelMock.
+			On("Publish", "profile", "profile.blocked", false, false, mock.Anything).
+			Return(nil).
+			Once()
+		channelMock.
+			On("Publish", "profile", "profile.unblocked", false, false, mock.Anything).
+			Return(nil).
+			Once()
+
+		u := usecase.NewStudentUseCase(mm, nil, nil, nil, nil, time.Second)
+		go u.BlockStudentTopic()
+		go u.UnblockStudentTopic()
+		mm.Blocked <- domain.Block{BlockerID: "abc", BlockedID: "def", Kind: domain.Blocking}
+		mm.Unblocked <- domain.Block{BlockerID: "abc", BlockedID: "def", Kind: domain.Blocking}
+		// wait a bit so the goroutines run
+		time.Sleep(10 * time.Millisecond)
+		channelMock.AssertExpectations(t)
+	})
+}
+
+func TestAvailability(t *testing.T) {
+	monday := time.Date(2026, 9, 14, 0, 0, 0, 0, time.UTC)
+
+	t.Run("get-default", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+		mockStudentRepo.On("GetAvailabilities", mock.Anything, []string{"abc"}).Return([]domain.Availability{}, nil).Once()
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+
+		availability, err := u.GetAvailability(context.TODO(), "abc", "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "UTC", availability.TimeZone)
+		assert.Empty(t, availability.Weekly)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("get-blocked", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+		mockStudentRepo.On("IsBlocked", mock.Anything, "def", "abc").Return(true, nil).Once()
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+
+		availability, err := u.GetAvailability(context.TODO(), "def", "abc")
+
+		assert.Error(t, err)
+		assert.Equal(t, 404, err.(*e.RestError).Code)
+		assert.Nil(t, availability)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("update-success", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+		mockStudentRepo.On("SaveAvailability", mock.Anything, mock.AnythingOfType("*domain.Availability")).Return(nil).Once()
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		availability := &domain.Availability{Weekly: []domain.WeeklySlot{{Day: time.Monday, Start: 60, End: 120}}}
+
+		err := u.UpdateAvailability(context.TODO(), "abc", availability)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", availability.StudentID)
+		assert.Equal(t, "UTC", availability.TimeZone)
+		assert.NotNil(t, availability.Exceptions)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("update-invalid", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+
+		err := u.UpdateAvailability(context.TODO(), "abc", &domain.Availability{TimeZone: "Nowhere"})
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("common-success", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+		mockStudentRepo.On("GetByID", mock.Anything, "def").Return(&domain.Student{ID: "def"}, nil).Once()
+		mockStudentRepo.On("IsBlocked", mock.Anything, "abc", "def").Return(false, nil).Once()
+		mockStudentRepo.On("GetAvailabilities", mock.Anything, []string{"abc", "def"}).Return([]domain.Availability{
+			{StudentID: "abc", TimeZone: "UTC", Weekly: []domain.WeeklySlot{{Day: time.Monday, Start: 9 * 60, End: 12 * 60}}},
+			{StudentID: "def", TimeZone: "UTC", Weekly: []domain.WeeklySlot{{Day: time.Monday, Start: 11 * 60, End: 15 * 60}}},
+		}, nil).Once()
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+
+		intervals, err := u.GetCommonAvailability(context.TODO(), "abc", []string{"abc", "def", "abc"}, monday, monday.AddDate(0, 0, 7))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Interval{{Start: monday.Add(11 * time.Hour), End: monday.Add(12 * time.Hour)}}, intervals)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("common-someone-without-availability", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, mock.AnythingOfType("string")).Return(&domain.Student{ID: "x"}, nil).Twice()
+		mockStudentRepo.On("IsBlocked", mock.Anything, "abc", "def").Return(false, nil).Once()
+		mockStudentRepo.On("GetAvailabilities", mock.Anything, []string{"abc", "def"}).Return([]domain.Availability{
+			{StudentID: "abc", TimeZone: "UTC", Weekly: []domain.WeeklySlot{{Day: time.Monday, Start: 9 * 60, End: 12 * 60}}},
+		}, nil).Once()
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+
+		intervals, err := u.GetCommonAvailability(context.TODO(), "abc", []string{"abc", "def"}, monday, monday.AddDate(0, 0, 7))
+
+		assert.NoError(t, err)
+		assert.Empty(t, intervals)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("common-invalid-range", func(t *testing.T) {
+		u := usecase.NewStudentUseCase(nil, new(mocks.StudentRepositoryMock), nil, nil, nil, time.Second)
+
+		_, err := u.GetCommonAvailability(context.TODO(), "abc", []string{"abc", "def"}, monday, monday.AddDate(0, 2, 0))
+		assert.Error(t, err)
+		_, err = u.GetCommonAvailability(context.TODO(), "abc", []string{"abc"}, monday, monday.AddDate(0, 0, 7))
+		assert.Error(t, err)
+	})
+}
+
+func encodedPNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestUpdateAvatar(t *testing.T) {
+	channelMock := new(mocks2.ChannelMock)
+	mm := usecase.NewMessagingManager(channelMock)
+	blobURL := func(key string) string { return "http://cdn/" + key }
+
+	t.Run("success", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockBlobStore := new(mocks.BlobStoreMock)
+		previous := &domain.Avatar{ContentType: "image/jpeg", UpdatedAt: time.Unix(0, 42)}
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc", Avatar: previous}, nil).Once()
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), "image/png", mock.Anything).
+			Return(nil).Times(3)
+		mockBlobStore.On("URL", mock.AnythingOfType("string")).Return(blobURL)
+		mockStudentRepo.On("UpdateAvatar", mock.Anything, "abc", mock.AnythingOfType("*domain.Avatar")).Return(nil).Once()
+		for _, key := range []string{"original.jpg", "64.jpg", "256.jpg"} {
+			mockBlobStore.On("Delete", mock.Anything, "avatars/abc/42/"+key).Return(nil).Once()
+		}
+
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, mockBlobStore, time.Second)
+		go func() {
+			student := <-mm.Edited
+			assert.NotNil(t, student.Avatar)
+		}()
+		avatar, err := u.UpdateAvatar(context.TODO(), "abc", encodedPNG(t, 120, 80))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", avatar.ContentType)
+		assert.Regexp(t, `^http://cdn/avatars/abc/\d+/original\.png$`, avatar.URL)
+		assert.Regexp(t, `^http://cdn/avatars/abc/\d+/64\.png$`, avatar.Thumbnails[64])
+		assert.Regexp(t, `^http://cdn/avatars/abc/\d+/256\.png$`, avatar.Thumbnails[256])
+		mockStudentRepo.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+
+	t.Run("err-invalid-image", func(t *testing.T) {
+		u := usecase.NewStudentUseCase(mm, nil, nil, nil, nil, time.Second)
+
+		for _, image := range [][]byte{nil, []byte("GIF89a"), encodedPNG(t, 32, 200)} {
+			_, err := u.UpdateAvatar(context.TODO(), "abc", image)
+
+			assert.Error(t, err)
+			assert.Equal(t, 400, err.(*e.RestError).Code)
+		}
+	})
+
+	t.Run("err-webp-not-decoded", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		// lossless WebP header of a 100 x 100 image, without the pixels
+		bits := uint32(99) | uint32(99)<<14
+		webp := []byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00\x2f")
+		webp = append(webp, byte(bits), byte(bits>>8), byte(bits>>16), byte(bits>>24), 0, 0, 0, 0, 0, 0, 0, 0)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+		_, err := u.UpdateAvatar(context.TODO(), "abc", webp)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("err-store-fails", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockBlobStore := new(mocks.BlobStoreMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), "image/png", mock.Anything).
+			Return(errors.New("unavailable")).Once()
+		mockBlobStore.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Times(3)
+
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, mockBlobStore, time.Second)
+		_, err := u.UpdateAvatar(context.TODO(), "abc", encodedPNG(t, 64, 64))
+
+		assert.Error(t, err)
+		assert.Equal(t, 500, err.(*e.RestError).Code)
+		mockBlobStore.AssertExpectations(t)
+	})
+}
+
+func TestDeleteAvatar(t *testing.T) {
+	channelMock := new(mocks2.ChannelMock)
+	mm := usecase.NewMessagingManager(channelMock)
+
+	t.Run("success", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockBlobStore := new(mocks.BlobStoreMock)
+		avatar := &domain.Avatar{ContentType: "image/webp", UpdatedAt: time.Unix(0, 7)}
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc", Avatar: avatar}, nil).Once()
+		mockStudentRepo.On("UpdateAvatar", mock.Anything, "abc", (*domain.Avatar)(nil)).Return(nil).Once()
+		mockBlobStore.On("Delete", mock.Anything, "avatars/abc/7/original.webp").Return(nil).Once()
+		mockBlobStore.On("Delete", mock.Anything, "avatars/abc/7/64.png").Return(nil).Once()
+		mockBlobStore.On("Delete", mock.Anything, "avatars/abc/7/256.png").Return(nil).Once()
+
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, mockBlobStore, time.Second)
+		go func() {
+			student := <-mm.Edited
+			assert.Nil(t, student.Avatar)
+		}()
+		err := u.DeleteAvatar(context.TODO(), "abc")
+
+		assert.NoError(t, err)
+		mockStudentRepo.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+
+	t.Run("no-avatar", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.DeleteAvatar(context.TODO(), "abc")
+
+		assert.NoError(t, err)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("err-empty-student", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{}, nil).Once()
+
+		u := usecase.NewStudentUseCase(mm, mockStudentRepo, nil, nil, nil, time.Second)
+		err := u.DeleteAvatar(context.TODO(), "abc")
+
+		assert.Error(t, err)
+		assert.Equal(t, 404, err.(*e.RestError).Code)
+	})
+}
+
+func TestGetOnboarding(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		student := &domain.Student{ID: "abc", GeneralInfo: "I like plants", CurrentClasses: []string{"COMP 354"}}
+		availability := domain.Availability{StudentID: "abc", Weekly: []domain.WeeklySlot{{Day: time.Monday, Start: 60, End: 120}}}
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(student, nil).Once()
+		mockStudentRepo.On("GetAvailabilities", mock.Anything, []string{"abc"}).
+			Return([]domain.Availability{availability}, nil).Once()
+		mockStudentRepo.On("GetUndeliverableSchoolEmail", mock.Anything, "abc", mock.Anything).Return(nil, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		onboarding, err := u.GetOnboarding(context.TODO(), "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 60, onboarding.Score)
+		assert.EqualValues(t, []string{domain.StepConfirmSchool, domain.StepUploadAvatar}, onboarding.Missing)
+		assert.Nil(t, onboarding.UndeliverableEmail)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("undeliverable-school-email", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		bounce := &domain.MailSuppression{Email: "ada@concordia.ca", Reason: domain.MailBounce}
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+		mockStudentRepo.On("GetAvailabilities", mock.Anything, []string{"abc"}).Return([]domain.Availability{}, nil).Once()
+		mockStudentRepo.On("GetUndeliverableSchoolEmail", mock.Anything, "abc", mock.MatchedBy(func(since time.Time) bool {
+			return time.Since(since) >= domain.ConfirmationTTL
+		})).Return(bounce, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		onboarding, err := u.GetOnboarding(context.TODO(), "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, bounce, onboarding.UndeliverableEmail)
+		assert.Contains(t, onboarding.Missing, domain.StepConfirmSchool)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("err-empty-student", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{}, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		_, err := u.GetOnboarding(context.TODO(), "abc")
+
+		assert.Error(t, err)
+		assert.Equal(t, 404, err.(*e.RestError).Code)
+	})
+
+	t.Run("err-availability", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByID", mock.Anything, "abc").Return(&domain.Student{ID: "abc"}, nil).Once()
+		mockStudentRepo.On("GetAvailabilities", mock.Anything, []string{"abc"}).Return(nil, errors.New("err")).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		_, err := u.GetOnboarding(context.TODO(), "abc")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestBatchGet(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByIDs", mock.Anything, "viewer", []string{"b", "a", "c"}).
+			Return([]domain.Student{
+				{ID: "a", FirstName: "Ada", LastName: "Lovelace", GeneralInfo: "not in the summary"},
+				{ID: "b", FirstName: "Alan", LastName: "Turing"},
+			}, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		result, err := u.BatchGet(context.TODO(), "viewer", []string{"b", "a", "b", "c"}, nil)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, []domain.StudentSummary{
+			{ID: "b", FirstName: "Alan", LastName: "Turing"},
+			{ID: "a", FirstName: "Ada", LastName: "Lovelace"},
+		}, result.Students)
+		assert.EqualValues(t, []string{"c"}, result.Missing)
+		mockStudentRepo.AssertExpectations(t)
+	})
+
+	t.Run("success-with-reviews", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockReviewRepo := new(mocks.ReviewRepositoryMock)
+		reviews := []domain.Review{{ID: "r", Reviewed: domain.Student{ID: "a"}}, {ID: "s", Reviewed: domain.Student{ID: "a"}},
+			{ID: "t", Reviewed: domain.Student{ID: "b"}}}
+		mockStudentRepo.On("GetByIDs", mock.Anything, "viewer", []string{"a", "b", "c"}).
+			Return([]domain.Student{{ID: "a"}, {ID: "b"}, {ID: "c"}}, nil).Once()
+		mockReviewRepo.On("GetReviewsForAll", mock.Anything, []string{"a", "b", "c"}).Return(reviews, nil).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, mockReviewRepo, nil, nil, time.Second)
+		result, err := u.BatchGet(context.TODO(), "viewer", []string{"a", "b", "c"}, []string{domain.IncludeReviews})
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, reviews[:2], result.Students[0].Reviews)
+		assert.EqualValues(t, reviews[2:], result.Students[1].Reviews)
+		assert.Empty(t, result.Students[2].Reviews)
+		mockReviewRepo.AssertExpectations(t)
+	})
+
+	t.Run("err-too-many-ids", func(t *testing.T) {
+		ids := make([]string, domain.MaxBatchGetIDs+1)
+		for i := range ids {
+			ids[i] = fmt.Sprint(i)
+		}
+
+		u := usecase.NewStudentUseCase(nil, nil, nil, nil, nil, time.Second)
+		_, err := u.BatchGet(context.TODO(), "viewer", ids, nil)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+	})
+
+	t.Run("err-no-ids", func(t *testing.T) {
+		u := usecase.NewStudentUseCase(nil, nil, nil, nil, nil, time.Second)
+		_, err := u.BatchGet(context.TODO(), "viewer", nil, nil)
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+	})
+
+	t.Run("err-unknown-include", func(t *testing.T) {
+		u := usecase.NewStudentUseCase(nil, nil, nil, nil, nil, time.Second)
+		_, err := u.BatchGet(context.TODO(), "viewer", []string{"a"}, []string{"friends"})
+
+		assert.Error(t, err)
+		assert.Equal(t, 400, err.(*e.RestError).Code)
+	})
+
+	t.Run("err-repository", func(t *testing.T) {
+		mockStudentRepo := new(mocks.StudentRepositoryMock)
+		mockStudentRepo.On("GetByIDs", mock.Anything, "viewer", []string{"a"}).Return(nil, errors.New("err")).Once()
+
+		u := usecase.NewStudentUseCase(nil, mockStudentRepo, nil, nil, nil, time.Second)
+		_, err := u.BatchGet(context.TODO(), "viewer", []string{"a"}, nil)
+
+		assert.Error(t, err)
+	})
+}
@@ -23,7 +23,7 @@ func TestTagHandlerGetAllTags(t *testing.T) {
 	h := http.NewTagHandler(mockUseCase)
 	mw := new(mocks.MiddlewareMock)
 	parser := new(mocks.ClaimsParserMock)
-	server := httptest.NewServer(app.Server(nil, nil, h, nil, mw, mw, parser))
+	server := httptest.NewServer(app.Server(nil, nil, h, nil, nil, nil, mw, mw, parser))
 	defer server.Close()
 
 	var mockTag []domain.Tag
@@ -8,6 +8,12 @@ import (
 	"os"
 	"time"
 
+	http6 "github.com/airbenders/profile/Mail/delivery/http"
+	repository6 "github.com/airbenders/profile/Mail/repository"
+	usecase6 "github.com/airbenders/profile/Mail/usecase"
+	http5 "github.com/airbenders/profile/Recommendation/delivery/http"
+	repository5 "github.com/airbenders/profile/Recommendation/repository"
+	usecase5 "github.com/airbenders/profile/Recommendation/usecase"
 	http4 "github.com/airbenders/profile/Review/delivery/http"
 	repository4 "github.com/airbenders/profile/Review/repository"
 	usecase4 "github.com/airbenders/profile/Review/usecase"
@@ -20,7 +26,8 @@ import (
 	http3 "github.com/airbenders/profile/Tag/delivery/http"
 	repository3 "github.com/airbenders/profile/Tag/repository"
 	usecase3 "github.com/airbenders/profile/Tag/usecase"
-	"github.com/airbenders/profile/utils"
+	"github.com/airbenders/profile/utils/blobstore"
+	"github.com/airbenders/profile/utils/mailer"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -32,6 +39,8 @@ func Server(
 	schoolHandler *http2.SchoolHandler,
 	tagHandler *http3.TagHandler,
 	reviewHandler *http4.ReviewHandler,
+	recommendationHandler *http5.RecommendationHandler,
+	mailHandler *http6.MailHandler,
 	mwV0 middlwares.Middleware,
 	mwV1 middlwares.Middleware,
 	parser middlwares.ClaimsParser) *gin.Engine {
@@ -46,6 +55,8 @@ func Server(
 	mapStudentURLsV1(mwV1, parser, studentHandler, router)
 	mapSchoolURLsV1(mwV1, parser, schoolHandler, router)
 	mapReviewURLsV1(mwV1, parser, reviewHandler, router)
+	mapRecommendationURLsV1(mwV1, parser, recommendationHandler, router)
+	mapMailURLsV1(mwV1, parser, mailHandler, router)
 
 	return router
 }
@@ -86,15 +97,25 @@ func Start() {
 	studentRepository := repository.NewStudentRepository(pool)
 	reviewRepository := repository4.NewReviewRepository(pool)
 	tagRepository := repository3.NewTagRepository(pool)
-	studentUseCase := usecase.NewStudentUseCase(mm, studentRepository, reviewRepository, tagRepository, time.Second*3)
+	blobStore := blobstore.NewFromEnv()
+	studentUseCase := usecase.NewStudentUseCase(mm, studentRepository, reviewRepository, tagRepository, blobStore, time.Second*3)
 	studentHandler := http.NewStudentHandler(studentUseCase)
 	go studentUseCase.CreateStudentTopic()
 	go studentUseCase.UpdateStudentTopic()
 	go studentUseCase.DeleteStudentTopic()
+	go studentUseCase.BlockStudentTopic()
+	go studentUseCase.UnblockStudentTopic()
+	go studentUseCase.RollOverTerms(time.Hour)
 	schoolRepository := repository2.NewSchoolRepository(pool)
-	mail := utils.NewSimpleMail()
-	schoolUseCase := usecase2.NewSchoolUseCase(schoolRepository, studentRepository, mail, time.Second*3)
+	mailRepository := repository6.NewMailRepository(pool)
+	mailUseCase := usecase6.NewMailUseCase(mailRepository, mailer.NewFromEnv(), time.Second*3)
+	mailHandler := http6.NewMailHandler(mailUseCase)
+	go mailUseCase.DeliverQueuedMail(5 * time.Second)
+	go mailUseCase.PurgeOldDeliveries(time.Hour)
+	schoolUseCase := usecase2.NewSchoolUseCase(ch, schoolRepository, studentRepository, mailUseCase, time.Second*3)
 	schoolHandler := http2.NewSchoolHandler(schoolUseCase)
+	go schoolUseCase.PurgeExpiredConfirmations(time.Hour)
+	go schoolUseCase.SchoolChangedTopic()
 
 	tagUseCase := usecase3.NewTagUseCase(tagRepository, time.Second*3)
 	tagHandler := http3.NewTagHandler(tagUseCase)
@@ -102,10 +123,18 @@ func Start() {
 	reviewUseCase := usecase4.NewReviewUseCase(reviewRepository, studentRepository, time.Second*3)
 	reviewHandler := http4.NewReviewHandler(reviewUseCase)
 
+	recommendationRepository := repository5.NewRecommendationRepository(pool)
+	recommendationUseCase := usecase5.NewRecommendationUseCase(recommendationRepository, studentRepository, time.Second*3)
+	recommendationHandler := http5.NewRecommendationHandler(recommendationUseCase)
+
 	mwV0 := middlwares.NewMiddleware()
 	mwV1 := middlwares.NewAuth0Middleware()
 	parser := middlwares.NewParseClaimsMiddleware()
 
-	router := Server(studentHandler, schoolHandler, tagHandler, reviewHandler, mwV0, mwV1, parser)
+	router := Server(studentHandler, schoolHandler, tagHandler, reviewHandler, recommendationHandler, mailHandler,
+		mwV0, mwV1, parser)
+	if local, ok := blobStore.(*blobstore.LocalStore); ok {
+		router.GET(blobstore.LocalPath+"/*key", gin.WrapH(local.Handler(blobstore.LocalPath)))
+	}
 	router.Run()
 }
@@ -1,6 +1,8 @@
 package app
 
 import (
+	mailHttp "github.com/airbenders/profile/Mail/delivery/http"
+	recommendationHttp "github.com/airbenders/profile/Recommendation/delivery/http"
 	reviewHttp "github.com/airbenders/profile/Review/delivery/http"
 	schoolHttp "github.com/airbenders/profile/School/delivery/http"
 	studentHttp "github.com/airbenders/profile/Student/delivery/http"
@@ -19,6 +21,24 @@ func studentURLs(h *studentHttp.StudentHandler, authorized *gin.RouterGroup) {
 	authorized.PUT("/removeClasses/:id", h.RemoveClasses)
 	authorized.PUT("/completeClasses/:id", h.CompleteAllClasses)
 	authorized.GET("/search/", h.SearchStudents)
+	authorized.GET(pathStudentID+"/enrollments", h.GetEnrollments)
+	authorized.PUT(pathStudentID+"/enrollments", h.SaveEnrollments)
+	authorized.GET("/classmates/history", h.GetClassmatesInTerm)
+	authorized.GET(pathStudentID+"/classmates", h.GetClassmates)
+	authorized.GET(pathStudentID+"/privacy", h.GetPrivacy)
+	authorized.PUT(pathStudentID+"/privacy", h.UpdatePrivacy)
+	authorized.GET(pathStudentID+"/blocks", h.GetBlocks)
+	authorized.PUT(pathStudentID+"/blocks/:target", h.Block)
+	authorized.DELETE(pathStudentID+"/blocks/:target", h.Unblock)
+	authorized.PUT(pathStudentID+"/mutes/:target", h.Mute)
+	authorized.DELETE(pathStudentID+"/mutes/:target", h.Unmute)
+	authorized.GET(pathStudentID+"/availability", h.GetAvailability)
+	authorized.PUT(pathStudentID+"/availability", h.UpdateAvailability)
+	authorized.GET("/availability/common", h.GetCommonAvailability)
+	authorized.PUT(pathStudentID+"/avatar", h.UpdateAvatar)
+	authorized.DELETE(pathStudentID+"/avatar", h.DeleteAvatar)
+	authorized.GET(pathStudentID+"/onboarding", h.GetOnboarding)
+	authorized.POST("/students:method", h.BatchGet)
 }
 
 func mapStudentURLsV0(m middlwares.Middleware, h *studentHttp.StudentHandler, router *gin.Engine) {
@@ -52,7 +72,17 @@ func mapSchoolURLsV1(m middlwares.Middleware, parserMW middlwares.ClaimsParser,
 
 func schoolURLs(authorized *gin.RouterGroup, h *schoolHttp.SchoolHandler) {
 	authorized.GET("/school", h.SearchStudentSchool)
+	authorized.GET("/schools", h.SearchSchools)
+	authorized.DELETE("/school", h.LeaveSchool)
+	// not /school/history: a second route under /school/ stops gin redirecting /school/ to /school
+	authorized.GET("/school-history", h.GetSchoolHistory)
+	authorized.PUT("/school-units", h.SelectSchoolUnits)
+	authorized.GET("/schools/:id/campuses", h.GetCampuses)
+	authorized.GET("/schools/:id/faculties", h.GetFaculties)
 	authorized.GET("/school/confirm", h.SendConfirmationMail)
+	authorized.GET("/school/confirm/resend", h.ResendConfirmationMail)
+	authorized.POST("/school/confirm/code", h.ConfirmSchoolWithCode)
+	authorized.GET("/courses", h.SearchCourses)
 }
 
 func mapTagURLs(h *tagHttp.TagHandler, r *gin.Engine) {
@@ -77,3 +107,21 @@ func mapReviewURLsV1(m middlwares.Middleware, parserMW middlwares.ClaimsParser,
 	authorized.Use(parserMW.ParseClaimsMiddleware())
 	reviewURLs(h, authorized)
 }
+
+// recommendations are only on v1 since they are new
+func mapRecommendationURLsV1(m middlwares.Middleware, parserMW middlwares.ClaimsParser, h *recommendationHttp.RecommendationHandler, r *gin.Engine) {
+	authorized := r.Group("/api/v1")
+	authorized.Use(m.AuthMiddleware())
+	authorized.Use(parserMW.ParseClaimsMiddleware())
+	authorized.GET("/recommendations", h.Recommend)
+}
+
+// the admin views are only on v1 since they are new
+func mapMailURLsV1(m middlwares.Middleware, parserMW middlwares.ClaimsParser, h *mailHttp.MailHandler, r *gin.Engine) {
+	authorized := r.Group("/api/v1")
+	authorized.Use(m.AuthMiddleware())
+	authorized.Use(parserMW.ParseClaimsMiddleware())
+	authorized.GET("/mail/failed", h.GetFailedDeliveries)
+	// called by the mail provider, which has its own token
+	r.POST("/api/v1/mail/events", h.ReceiveMailEvents)
+}
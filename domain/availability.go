@@ -0,0 +1,253 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Limits on the size of an availability so expanding it stays cheap
+const (
+	MaxWeeklySlots = 50
+	MaxExceptions  = 100
+)
+
+// TimeOfDay is a wall clock time as minutes since midnight. It is represented as "15:04" in JSON.
+// 24:00 is allowed so a slot can last until the end of the day
+type TimeOfDay int
+
+// ParseTimeOfDay parses times like "09:30" or "24:00"
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	var hours, minutes int
+	n, err := fmt.Sscanf(s, "%d:%d", &hours, &minutes)
+	if err != nil || n != 2 || len(s) != 5 || hours < 0 || minutes < 0 || minutes > 59 ||
+		hours*60+minutes > 24*60 {
+		return 0, fmt.Errorf("invalid time %q. Expected HH:MM, like 09:30", s)
+	}
+	return TimeOfDay(hours*60 + minutes), nil
+}
+
+// String returns the time as "15:04"
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+// MarshalText encodes the time as "15:04"
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a time from "15:04"
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	parsed, err := ParseTimeOfDay(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// WeeklySlot is a time a student is free every week, in the student's time zone. Day 0 is Sunday
+type WeeklySlot struct {
+	Day   time.Weekday `json:"day"`
+	Start TimeOfDay    `json:"start"`
+	End   TimeOfDay    `json:"end"`
+}
+
+// AvailabilityException changes the weekly availability once. The student is free during the exception if
+// Available is true, and busy otherwise
+type AvailabilityException struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Available bool      `json:"available"`
+}
+
+// Availability is when a student can meet with teammates
+type Availability struct {
+	StudentID  string                  `json:"student_id"`
+	TimeZone   string                  `json:"time_zone"`
+	Weekly     []WeeklySlot            `json:"weekly"`
+	Exceptions []AvailabilityException `json:"exceptions"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
+
+// Interval is a period of time, from Start included to End excluded
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Duration is how long the interval lasts
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// Validate checks the time zone and that every slot and exception ends after it starts.
+// An empty time zone is set to UTC
+func (a *Availability) Validate() error {
+	if a.TimeZone == "" {
+		a.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(a.TimeZone); err != nil {
+		return fmt.Errorf("unknown time zone %q. Expected a name like America/Toronto", a.TimeZone)
+	}
+	if len(a.Weekly) > MaxWeeklySlots {
+		return fmt.Errorf("too many weekly slots. The most is %d", MaxWeeklySlots)
+	}
+	if len(a.Exceptions) > MaxExceptions {
+		return fmt.Errorf("too many exceptions. The most is %d", MaxExceptions)
+	}
+	for _, slot := range a.Weekly {
+		if slot.Day < time.Sunday || slot.Day > time.Saturday {
+			return fmt.Errorf("invalid day %d. Expected 0 for Sunday to 6 for Saturday", slot.Day)
+		}
+		if slot.Start >= slot.End {
+			return fmt.Errorf("the slot on %s starting at %s must end after it starts", slot.Day, slot.Start)
+		}
+	}
+	for _, exception := range a.Exceptions {
+		if !exception.End.After(exception.Start) {
+			return fmt.Errorf("the exception starting at %s must end after it starts", exception.Start.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// FreeIntervals returns when the student is free between from and to, sorted and without overlaps
+func (a *Availability) FreeIntervals(from, to time.Time) []Interval {
+	loc, err := time.LoadLocation(a.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var free, busy []Interval
+	// start a day early and end a day late so slots near the edges are there whatever the time zone
+	first := from.In(loc).AddDate(0, 0, -1)
+	last := to.AddDate(0, 0, 1)
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc); day.Before(last); day = day.AddDate(0, 0, 1) {
+		for _, slot := range a.Weekly {
+			if slot.Day != day.Weekday() {
+				continue
+			}
+			free = append(free, Interval{Start: atTimeOfDay(day, slot.Start), End: atTimeOfDay(day, slot.End)})
+		}
+	}
+	for _, exception := range a.Exceptions {
+		interval := Interval{Start: exception.Start, End: exception.End}
+		if exception.Available {
+			free = append(free, interval)
+		} else {
+			busy = append(busy, interval)
+		}
+	}
+
+	return IntersectIntervals(SubtractIntervals(MergeIntervals(free), MergeIntervals(busy)),
+		[]Interval{{Start: from, End: to}})
+}
+
+// atTimeOfDay returns the moment of the day at that wall clock time. time.Date takes care of DST changes and of 24:00
+func atTimeOfDay(day time.Time, t TimeOfDay) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), int(t)/60, int(t)%60, 0, 0, day.Location())
+}
+
+// MergeIntervals sorts the intervals and joins the ones that overlap or touch
+func MergeIntervals(intervals []Interval) []Interval {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, interval := range intervals {
+		if interval.End.After(interval.Start) {
+			sorted = append(sorted, interval)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	merged := []Interval{}
+	for _, interval := range sorted {
+		last := len(merged) - 1
+		if last >= 0 && !interval.Start.After(merged[last].End) {
+			if interval.End.After(merged[last].End) {
+				merged[last].End = interval.End
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+// IntersectIntervals returns the time that is in both lists. Both must be merged
+func IntersectIntervals(a, b []Interval) []Interval {
+	result := []Interval{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		start := laterOf(a[i].Start, b[j].Start)
+		end := earlierOf(a[i].End, b[j].End)
+		if end.After(start) {
+			result = append(result, Interval{Start: start, End: end})
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
+
+// SubtractIntervals returns the time that is in a but not in b. Both must be merged
+func SubtractIntervals(a, b []Interval) []Interval {
+	result := []Interval{}
+	j := 0
+	for _, interval := range a {
+		start := interval.Start
+		for j < len(b) && !b[j].End.After(start) {
+			j++
+		}
+		for k := j; k < len(b) && b[k].Start.Before(interval.End); k++ {
+			if b[k].Start.After(start) {
+				result = append(result, Interval{Start: start, End: b[k].Start})
+			}
+			start = laterOf(start, b[k].End)
+		}
+		if interval.End.After(start) {
+			result = append(result, Interval{Start: start, End: interval.End})
+		}
+	}
+	return result
+}
+
+// CommonIntervals returns when everyone is free, given when each one is free
+func CommonIntervals(free ...[]Interval) []Interval {
+	if len(free) == 0 {
+		return []Interval{}
+	}
+	common := free[0]
+	for _, other := range free[1:] {
+		common = IntersectIntervals(common, other)
+	}
+	return common
+}
+
+// TotalDuration adds up the duration of the intervals
+func TotalDuration(intervals []Interval) time.Duration {
+	var total time.Duration
+	for _, interval := range intervals {
+		total += interval.Duration()
+	}
+	return total
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlierOf(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
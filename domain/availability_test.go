@@ -0,0 +1,89 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func at(day, hour, minute int) time.Time {
+	// September 14th 2026 is a Monday
+	return time.Date(2026, 9, 14+day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestTimeOfDayJSON(t *testing.T) {
+	var slot domain.WeeklySlot
+	err := json.Unmarshal([]byte(`{"day":1,"start":"09:30","end":"24:00"}`), &slot)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.WeeklySlot{Day: time.Monday, Start: 9*60 + 30, End: 24 * 60}, slot)
+
+	encoded, err := json.Marshal(slot)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"day":1,"start":"09:30","end":"24:00"}`, string(encoded))
+
+	for _, invalid := range []string{`"9:30"`, `"24:01"`, `"12:60"`, `"noon"`} {
+		var tod domain.TimeOfDay
+		assert.Error(t, json.Unmarshal([]byte(invalid), &tod), invalid)
+	}
+}
+
+func TestAvailabilityValidate(t *testing.T) {
+	availability := domain.Availability{Weekly: []domain.WeeklySlot{{Day: time.Friday, Start: 60, End: 120}}}
+	assert.NoError(t, availability.Validate())
+	assert.Equal(t, "UTC", availability.TimeZone)
+
+	assert.Error(t, (&domain.Availability{TimeZone: "Mars/Olympus"}).Validate())
+	assert.Error(t, (&domain.Availability{Weekly: []domain.WeeklySlot{{Day: 7, Start: 60, End: 120}}}).Validate())
+	assert.Error(t, (&domain.Availability{Weekly: []domain.WeeklySlot{{Day: 1, Start: 120, End: 120}}}).Validate())
+	assert.Error(t, (&domain.Availability{Exceptions: []domain.AvailabilityException{{Start: at(0, 10, 0), End: at(0, 9, 0)}}}).Validate())
+}
+
+func TestFreeIntervals(t *testing.T) {
+	availability := domain.Availability{
+		TimeZone: "America/Toronto",
+		Weekly: []domain.WeeklySlot{
+			{Day: time.Monday, Start: 9 * 60, End: 12 * 60},
+			{Day: time.Monday, Start: 11 * 60, End: 13 * 60},
+			{Day: time.Wednesday, Start: 18 * 60, End: 24 * 60},
+		},
+		Exceptions: []domain.AvailabilityException{
+			// busy in the middle of monday
+			{Start: at(0, 14, 0), End: at(0, 15, 0)},
+			// free on tuesday once
+			{Start: at(1, 12, 0), End: at(1, 13, 0), Available: true},
+		},
+	}
+
+	free := availability.FreeIntervals(at(0, 0, 0), at(3, 0, 0))
+
+	// Toronto is 4 hours behind UTC in September
+	assert.Equal(t, []domain.Interval{
+		{Start: at(0, 13, 0), End: at(0, 14, 0)},
+		{Start: at(0, 15, 0), End: at(0, 17, 0)},
+		{Start: at(1, 12, 0), End: at(1, 13, 0)},
+		{Start: at(2, 22, 0), End: at(3, 0, 0)},
+	}, utc(free))
+	assert.Equal(t, 6*time.Hour, domain.TotalDuration(free))
+}
+
+func TestCommonIntervals(t *testing.T) {
+	a := []domain.Interval{{Start: at(0, 9, 0), End: at(0, 12, 0)}, {Start: at(1, 9, 0), End: at(1, 12, 0)}}
+	b := []domain.Interval{{Start: at(0, 11, 0), End: at(1, 10, 0)}}
+	c := []domain.Interval{{Start: at(0, 0, 0), End: at(2, 0, 0)}}
+
+	assert.Equal(t, []domain.Interval{
+		{Start: at(0, 11, 0), End: at(0, 12, 0)},
+		{Start: at(1, 9, 0), End: at(1, 10, 0)},
+	}, domain.CommonIntervals(a, b, c))
+	assert.Empty(t, domain.CommonIntervals(a, []domain.Interval{}))
+}
+
+func utc(intervals []domain.Interval) []domain.Interval {
+	result := make([]domain.Interval, 0, len(intervals))
+	for _, interval := range intervals {
+		result = append(result, domain.Interval{Start: interval.Start.UTC(), End: interval.End.UTC()})
+	}
+	return result
+}
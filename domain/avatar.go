@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Limits on avatar uploads
+const (
+	MaxAvatarSize      = 5 << 20
+	MinAvatarDimension = 64
+	MaxAvatarDimension = 4096
+)
+
+// AvatarThumbnailSizes are the sides in pixels of the square thumbnails made for every avatar
+var AvatarThumbnailSizes = []int{64, 256}
+
+// Avatar is the profile picture of a student, with thumbnails keyed by their size
+type Avatar struct {
+	URL         string         `json:"url"`
+	ContentType string         `json:"content_type"`
+	Thumbnails  map[int]string `json:"thumbnails"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// BlobStore stores files by key and tells where they can be downloaded from
+type BlobStore interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
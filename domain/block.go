@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// BlockKind says how far a student wants to keep another one away
+type BlockKind string
+
+// A block hides the two students from each other and stops them from reviewing each other.
+// A mute only hides the muted student from the one who muted them
+const (
+	Blocking BlockKind = "block"
+	Muting   BlockKind = "mute"
+)
+
+// Block is a student blocking or muting another one
+type Block struct {
+	BlockerID string    `json:"blocker_id"`
+	BlockedID string    `json:"blocked_id"`
+	Kind      BlockKind `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,12 +1,95 @@
 package domain
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// ConfirmationTTL is how long a confirmation token can be used
+const ConfirmationTTL = 24 * time.Hour
+
+// The limits of the codes sent with confirmations, for students confirming in the app rather than with the link
+const (
+	// ConfirmationCodeLength is the number of digits of a code
+	ConfirmationCodeLength = 6
+	// ConfirmationCodeTTL is how long a code can be used. The link lasts longer
+	ConfirmationCodeTTL = 15 * time.Minute
+	// MaxConfirmationCodeAttempts is how many codes can be tried before the confirmation is locked
+	MaxConfirmationCodeAttempts = 5
+)
+
+// SchoolVerificationTTL is how long a confirmed school lasts before the student has to confirm it again
+const SchoolVerificationTTL = 365 * 24 * time.Hour
 
 // Confirmation stores the confirmation token for validation. Also stores the student and school id associated with
 // the confirmation. Lastly has a timestamp for expiry of records
 type Confirmation struct {
-	Token     string    `json:"token"`
+	// Token is only known when the confirmation is created. It is sent by email and never stored
+	Token string `json:"token"`
+	// TokenHash is what is stored in place of the token. See HashConfirmationToken
+	TokenHash string `json:"-"`
+	// Code is like Token, only known when the confirmation is created
+	Code string `json:"-"`
+	// CodeHash is what is stored in place of the code. See HashConfirmationCode
+	CodeHash string `json:"-"`
+	// CodeAttempts is how many codes were tried
+	CodeAttempts int `json:"code_attempts"`
+	// Email is the school email address the token was sent to
+	Email     string    `json:"email"`
 	School    School    `json:"in_school"`
 	Student   Student   `json:"for_student"`
 	CreatedAt time.Time `json:"created_at"`
+	// ConsumedAt is when the token was used, or replaced by a newer one. Nil while it can still be used
+	ConsumedAt *time.Time `json:"consumed_at,omitempty"`
+}
+
+// ConfirmationStatus is what came out of opening a confirmation link
+type ConfirmationStatus string
+
+// The statuses of a confirmation link
+const (
+	ConfirmationSucceeded        ConfirmationStatus = "confirmed"
+	ConfirmationAlreadyConfirmed ConfirmationStatus = "already_confirmed"
+	// ConfirmationExpired is also the status of a link replaced by a newer one
+	ConfirmationExpired ConfirmationStatus = "expired"
+	ConfirmationInvalid ConfirmationStatus = "invalid"
+)
+
+// ConfirmationCodeRequest is the code a student got by email
+type ConfirmationCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// SchoolVerification is the school email address that proved the student's enrollment. An address can only verify
+// one student of a school
+type SchoolVerification struct {
+	Email      string    `json:"email"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// ExpiresAt is when the school has to be confirmed again
+func (v SchoolVerification) ExpiresAt() time.Time {
+	return v.VerifiedAt.Add(SchoolVerificationTTL)
+}
+
+// ConfirmationSend is a confirmation email that was sent. Sends are kept to rate limit the emails
+type ConfirmationSend struct {
+	TokenHash string    `json:"-"`
+	StudentID string    `json:"student_id"`
+	Email     string    `json:"email"`
+	SentAt    time.Time `json:"sent_at"`
+}
+
+// HashConfirmationToken is the SHA-256 of the token, hex encoded. Tokens are random UUIDs, so a plain hash is enough
+// to keep a leaked table from being used to confirm schools
+func HashConfirmationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashConfirmationCode is the SHA-256 of the code salted with the hash of its token, hex encoded. Codes are short, so
+// the salt keeps one table of hashes from matching every code
+func HashConfirmationCode(tokenHash string, code string) string {
+	return HashConfirmationToken(tokenHash + ":" + code)
 }
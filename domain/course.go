@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Course is a class offered by a school. Subject and Number together make up its normalized code, e.g. COMP 354
+type Course struct {
+	ID       string `json:"id"`
+	SchoolID string `json:"school_id"`
+	Subject  string `json:"subject"`
+	Number   string `json:"number"`
+	Title    string `json:"title"`
+	Term     string `json:"term"`
+}
+
+// Code returns the normalized class code of the course
+func (c Course) Code() string {
+	return c.Subject + " " + c.Number
+}
+
+var (
+	classCodeRegex  = regexp.MustCompile(`^([A-Za-z]{2,5})[\s\-_.]*([0-9]{3,4}[A-Za-z]?)$`)
+	codeSeparators  = regexp.MustCompile(`[\s\-_.]+`)
+	letterThenDigit = regexp.MustCompile(`([A-Z])([0-9])`)
+)
+
+// NormalizeClassCode turns codes like "comp354", "COMP-354" or " Comp  354 " into "COMP 354".
+// Returns false if the code doesn't look like a subject followed by a course number
+func NormalizeClassCode(code string) (string, bool) {
+	matches := classCodeRegex.FindStringSubmatch(strings.TrimSpace(code))
+	if matches == nil {
+		return "", false
+	}
+	return strings.ToUpper(matches[1]) + " " + strings.ToUpper(matches[2]), true
+}
+
+// NormalizeCoursePrefix does a best-effort normalization of a partially typed class code, e.g. "comp3" becomes
+// "COMP 3", so it can be used for prefix matching against normalized codes
+func NormalizeCoursePrefix(query string) string {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	query = codeSeparators.ReplaceAllString(query, " ")
+	return letterThenDigit.ReplaceAllString(query, "$1 $2")
+}
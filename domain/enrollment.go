@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Season of an academic term
+type Season string
+
+// Seasons in the order they happen within a year
+const (
+	Winter Season = "Winter"
+	Summer Season = "Summer"
+	Fall   Season = "Fall"
+)
+
+// seasonStartMonth is the month each season starts in. A season ends when the next one starts
+var seasonStartMonth = map[Season]time.Month{
+	Winter: time.January,
+	Summer: time.May,
+	Fall:   time.September,
+}
+
+// Term is an academic term, e.g. Fall 2026. It is represented as "Fall 2026" in JSON
+type Term struct {
+	Season Season
+	Year   int
+}
+
+// TermOf returns the term the given moment falls in
+func TermOf(t time.Time) Term {
+	t = t.UTC()
+	switch {
+	case t.Month() >= time.September:
+		return Term{Season: Fall, Year: t.Year()}
+	case t.Month() >= time.May:
+		return Term{Season: Summer, Year: t.Year()}
+	default:
+		return Term{Season: Winter, Year: t.Year()}
+	}
+}
+
+// ParseTerm parses terms like "Fall 2026" or "winter 2027"
+func ParseTerm(s string) (Term, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return Term{}, fmt.Errorf("invalid term %q. Expected a season and a year, like Fall 2026", s)
+	}
+	season := Season(strings.ToUpper(fields[0][:1]) + strings.ToLower(fields[0][1:]))
+	if _, ok := seasonStartMonth[season]; !ok {
+		return Term{}, fmt.Errorf("invalid season %q. Expected one of Winter, Summer or Fall", fields[0])
+	}
+	year, err := strconv.Atoi(fields[1])
+	if err != nil || year < 1900 || year > 9999 {
+		return Term{}, fmt.Errorf("invalid year %q in term", fields[1])
+	}
+	return Term{Season: season, Year: year}, nil
+}
+
+// String returns the term as "Fall 2026"
+func (t Term) String() string {
+	return fmt.Sprintf("%s %d", t.Season, t.Year)
+}
+
+// IsZero is true for the zero Term
+func (t Term) IsZero() bool {
+	return t == Term{}
+}
+
+// Start is the first moment of the term
+func (t Term) Start() time.Time {
+	return time.Date(t.Year, seasonStartMonth[t.Season], 1, 0, 0, 0, 0, time.UTC)
+}
+
+// End is the first moment after the term, which is also the start of the next one
+func (t Term) End() time.Time {
+	return t.Next().Start()
+}
+
+// Next returns the term right after this one
+func (t Term) Next() Term {
+	switch t.Season {
+	case Winter:
+		return Term{Season: Summer, Year: t.Year}
+	case Summer:
+		return Term{Season: Fall, Year: t.Year}
+	default:
+		return Term{Season: Winter, Year: t.Year + 1}
+	}
+}
+
+// MarshalText encodes the term as "Fall 2026"
+func (t Term) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return []byte{}, nil
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a term from "Fall 2026"
+func (t *Term) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*t = Term{}
+		return nil
+	}
+	term, err := ParseTerm(string(text))
+	if err != nil {
+		return err
+	}
+	*t = term
+	return nil
+}
+
+// EnrollmentStatus says where a student stands in a class for a term
+type EnrollmentStatus string
+
+// The statuses an enrollment can have
+const (
+	Enrolled  EnrollmentStatus = "enrolled"
+	Completed EnrollmentStatus = "completed"
+	Dropped   EnrollmentStatus = "dropped"
+)
+
+// IsValid is true for the known enrollment statuses
+func (s EnrollmentStatus) IsValid() bool {
+	return s == Enrolled || s == Completed || s == Dropped
+}
+
+// Enrollment records a student taking a class during a term
+type Enrollment struct {
+	StudentID string           `json:"student_id"`
+	Class     string           `json:"class"`
+	Term      Term             `json:"term"`
+	Status    EnrollmentStatus `json:"status"`
+	Section   string           `json:"section,omitempty"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
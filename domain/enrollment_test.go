@@ -0,0 +1,47 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTermOf(t *testing.T) {
+	assert.Equal(t, domain.Term{Season: domain.Winter, Year: 2026}, domain.TermOf(time.Date(2026, 4, 30, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, domain.Term{Season: domain.Summer, Year: 2026}, domain.TermOf(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, domain.Term{Season: domain.Fall, Year: 2026}, domain.TermOf(time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestTermBoundaries(t *testing.T) {
+	fall := domain.Term{Season: domain.Fall, Year: 2026}
+
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), fall.Start())
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), fall.End())
+	assert.Equal(t, domain.Term{Season: domain.Winter, Year: 2027}, fall.Next())
+}
+
+func TestParseTerm(t *testing.T) {
+	term, err := domain.ParseTerm(" winter  2026 ")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Term{Season: domain.Winter, Year: 2026}, term)
+
+	_, err = domain.ParseTerm("Spring 2026")
+	assert.Error(t, err)
+	_, err = domain.ParseTerm("Fall")
+	assert.Error(t, err)
+	_, err = domain.ParseTerm("Fall twenty")
+	assert.Error(t, err)
+}
+
+func TestTermJSON(t *testing.T) {
+	var enrollment domain.Enrollment
+	err := json.Unmarshal([]byte(`{"class":"COMP 354","term":"Fall 2026"}`), &enrollment)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Term{Season: domain.Fall, Year: 2026}, enrollment.Term)
+
+	encoded, err := json.Marshal(enrollment)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"term":"Fall 2026"`)
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mailer sends emails. The body is the whole message with its headers, as built by mailer.Email
+type Mailer interface {
+	SendSimpleMail(ctx context.Context, to string, body []byte) error
+}
+
+// DefaultLocale is the locale of the emails for clients that don't prefer a supported one
+const DefaultLocale = "en"
+
+// SupportedLocales are the locales the emails are written in
+var SupportedLocales = []string{"en", "fr"}
+
+// MatchLocale returns the supported locale the client prefers the most in its Accept-Language header, like
+// "fr-CA,fr;q=0.9,en;q=0.8". Regions are ignored, so fr-CA gets fr
+func MatchLocale(acceptLanguage string) string {
+	type preference struct {
+		language string
+		quality  float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		language := strings.ToLower(strings.TrimSpace(fields[0]))
+		if i := strings.IndexAny(language, "-_"); i >= 0 {
+			language = language[:i]
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if language != "" && quality > 0 {
+			preferences = append(preferences, preference{language, quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+
+	for _, p := range preferences {
+		if contains(SupportedLocales, p.language) {
+			return p.language
+		}
+	}
+	return DefaultLocale
+}
+
+// MaxMailAttempts is how many times a queued email is tried before it is given up as dead
+const MaxMailAttempts = 8
+
+// MailRetention is how long sent and dead emails are kept before they are purged
+const MailRetention = 30 * 24 * time.Hour
+
+// MailStatus is where a queued email is in its delivery
+type MailStatus string
+
+// The statuses of a MailDelivery
+const (
+	// MailPending emails are waiting for their next attempt
+	MailPending MailStatus = "pending"
+	// MailSent emails were accepted by the transport
+	MailSent MailStatus = "sent"
+	// MailDead emails failed MaxMailAttempts times and won't be tried again
+	MailDead MailStatus = "dead"
+)
+
+// MailDelivery is an email in the queue. The body isn't shown since it can hold confirmation links, and it is nil
+// once the email is sent or dead
+type MailDelivery struct {
+	ID            string     `json:"id"`
+	To            string     `json:"to"`
+	Body          []byte     `json:"-"`
+	Status        MailStatus `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// MailEventType is what a mail provider reports about a sent email
+type MailEventType string
+
+// The types of a MailEvent
+const (
+	// MailBounce is an email the server of the recipient refused
+	MailBounce MailEventType = "bounce"
+	// MailComplaint is an email the recipient marked as spam
+	MailComplaint MailEventType = "complaint"
+)
+
+// MailEvent is a bounce or complaint notification about an address. Temporary bounces, like a full mailbox, aren't
+// Permanent and don't stop the emails to the address
+type MailEvent struct {
+	Type       MailEventType `json:"type"`
+	Email      string        `json:"email"`
+	Permanent  bool          `json:"permanent"`
+	Diagnostic string        `json:"diagnostic"`
+}
+
+// Suppresses is true if no more emails should be sent to the address of the event
+func (e MailEvent) Suppresses() bool {
+	return e.Type == MailComplaint || (e.Type == MailBounce && e.Permanent)
+}
+
+// MailSuppression is an undeliverable address: it bounced for good or its owner complained. Nothing is sent to it
+// anymore
+type MailSuppression struct {
+	Email      string        `json:"email"`
+	Reason     MailEventType `json:"reason"`
+	Diagnostic string        `json:"diagnostic,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// Message explains why emails can't be sent to the suppressed address
+func (s *MailSuppression) Message() string {
+	if s.Reason == MailComplaint {
+		return fmt.Sprintf("%s marked our emails as spam, so no more are sent to it. Use another address", s.Email)
+	}
+	return fmt.Sprintf("emails to %s bounce, so no more are sent to it. Use another address", s.Email)
+}
+
+// MailUseCase queues emails and delivers them in the background. Its SendSimpleMail only queues the email, and
+// refuses suppressed addresses
+type MailUseCase interface {
+	Mailer
+	DeliverQueuedMail(interval time.Duration)
+	PurgeOldDeliveries(interval time.Duration)
+	GetFailedDeliveries(ctx context.Context, page Page) ([]MailDelivery, error)
+	HandleMailEvents(ctx context.Context, events []MailEvent) (int, error)
+}
+
+// MailRepository stores the queued emails
+type MailRepository interface {
+	Enqueue(ctx context.Context, delivery *MailDelivery) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]MailDelivery, error)
+	MarkSent(ctx context.Context, id string, attempts int, at time.Time) error
+	MarkFailed(ctx context.Context, delivery *MailDelivery) error
+	GetDeliveries(ctx context.Context, status MailStatus, page Page) ([]MailDelivery, error)
+	DeleteOldDeliveries(ctx context.Context, before time.Time) (int64, error)
+	Suppress(ctx context.Context, suppression *MailSuppression) error
+	GetSuppression(ctx context.Context, email string) (*MailSuppression, error)
+}
@@ -0,0 +1,15 @@
+package domain_test
+
+import (
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMatchLocale(t *testing.T) {
+	assert.Equal(t, "fr", domain.MatchLocale("fr-CA,fr;q=0.9,en;q=0.8"))
+	assert.Equal(t, "en", domain.MatchLocale("de-DE, en;q=0.5, fr;q=0.4"))
+	assert.Equal(t, "fr", domain.MatchLocale("en;q=0.3, FR_ca;q=0.7"))
+	assert.Equal(t, "en", domain.MatchLocale("fr;q=0, es"))
+	assert.Equal(t, domain.DefaultLocale, domain.MatchLocale(""))
+}
@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+// BlobStoreMock struct
+type BlobStoreMock struct {
+	mock.Mock
+}
+
+// Put -- BlobStoreMock
+func (m *BlobStoreMock) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	ret := m.Called(ctx, key, contentType, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []byte) error); ok {
+		r0 = rf(ctx, key, contentType, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete -- BlobStoreMock
+func (m *BlobStoreMock) Delete(ctx context.Context, key string) error {
+	ret := m.Called(ctx, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// URL -- BlobStoreMock
+func (m *BlobStoreMock) URL(key string) string {
+	ret := m.Called(key)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.String(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,156 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MailRepositoryMock struct
+type MailRepositoryMock struct {
+	mock.Mock
+}
+
+// Enqueue - MailRepository
+func (m *MailRepositoryMock) Enqueue(ctx context.Context, delivery *domain.MailDelivery) error {
+	args := m.Called(ctx, delivery)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, *domain.MailDelivery) error); ok {
+		r0 = rf(ctx, delivery)
+	} else {
+		r0 = args.Error(0)
+	}
+	return r0
+}
+
+// ClaimDue - MailRepository
+func (m *MailRepositoryMock) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.MailDelivery, error) {
+	args := m.Called(ctx, now, lease, limit)
+
+	var r0 []domain.MailDelivery
+	if rf, ok := args.Get(0).(func(context.Context, time.Time, time.Duration, int) []domain.MailDelivery); ok {
+		r0 = rf(ctx, now, lease, limit)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.MailDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, time.Time, time.Duration, int) error); ok {
+		r1 = rf(ctx, now, lease, limit)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkSent - MailRepository
+func (m *MailRepositoryMock) MarkSent(ctx context.Context, id string, attempts int, at time.Time) error {
+	args := m.Called(ctx, id, attempts, at)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, string, int, time.Time) error); ok {
+		r0 = rf(ctx, id, attempts, at)
+	} else {
+		r0 = args.Error(0)
+	}
+	return r0
+}
+
+// MarkFailed - MailRepository
+func (m *MailRepositoryMock) MarkFailed(ctx context.Context, delivery *domain.MailDelivery) error {
+	args := m.Called(ctx, delivery)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, *domain.MailDelivery) error); ok {
+		r0 = rf(ctx, delivery)
+	} else {
+		r0 = args.Error(0)
+	}
+	return r0
+}
+
+// GetDeliveries - MailRepository
+func (m *MailRepositoryMock) GetDeliveries(ctx context.Context, status domain.MailStatus, page domain.Page) ([]domain.MailDelivery, error) {
+	args := m.Called(ctx, status, page)
+
+	var r0 []domain.MailDelivery
+	if rf, ok := args.Get(0).(func(context.Context, domain.MailStatus, domain.Page) []domain.MailDelivery); ok {
+		r0 = rf(ctx, status, page)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.MailDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, domain.MailStatus, domain.Page) error); ok {
+		r1 = rf(ctx, status, page)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteOldDeliveries - MailRepository
+func (m *MailRepositoryMock) DeleteOldDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+
+	var r0 int64
+	if rf, ok := args.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = args.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Suppress - MailRepository
+func (m *MailRepositoryMock) Suppress(ctx context.Context, suppression *domain.MailSuppression) error {
+	args := m.Called(ctx, suppression)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, *domain.MailSuppression) error); ok {
+		r0 = rf(ctx, suppression)
+	} else {
+		r0 = args.Error(0)
+	}
+	return r0
+}
+
+// GetSuppression - MailRepository
+func (m *MailRepositoryMock) GetSuppression(ctx context.Context, email string) (*domain.MailSuppression, error) {
+	args := m.Called(ctx, email)
+
+	var r0 *domain.MailSuppression
+	if rf, ok := args.Get(0).(func(context.Context, string) *domain.MailSuppression); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).(*domain.MailSuppression)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
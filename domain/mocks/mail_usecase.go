@@ -0,0 +1,81 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MailUseCase Mock struct
+type MailUseCase struct {
+	mock.Mock
+}
+
+// SendSimpleMail - MailUseCase
+func (m *MailUseCase) SendSimpleMail(ctx context.Context, to string, body []byte) error {
+	args := m.Called(ctx, to, body)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, string, []byte) error); ok {
+		r0 = rf(ctx, to, body)
+	} else {
+		r0 = args.Error(0)
+	}
+	return r0
+}
+
+// DeliverQueuedMail - MailUseCase
+func (m *MailUseCase) DeliverQueuedMail(interval time.Duration) {
+	m.Called(interval)
+}
+
+// PurgeOldDeliveries - MailUseCase
+func (m *MailUseCase) PurgeOldDeliveries(interval time.Duration) {
+	m.Called(interval)
+}
+
+// GetFailedDeliveries - MailUseCase
+func (m *MailUseCase) GetFailedDeliveries(ctx context.Context, page domain.Page) ([]domain.MailDelivery, error) {
+	args := m.Called(ctx, page)
+
+	var r0 []domain.MailDelivery
+	if rf, ok := args.Get(0).(func(context.Context, domain.Page) []domain.MailDelivery); ok {
+		r0 = rf(ctx, page)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.MailDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, domain.Page) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HandleMailEvents - MailUseCase
+func (m *MailUseCase) HandleMailEvents(ctx context.Context, events []domain.MailEvent) (int, error) {
+	args := m.Called(ctx, events)
+
+	var r0 int
+	if rf, ok := args.Get(0).(func(context.Context, []domain.MailEvent) int); ok {
+		r0 = rf(ctx, events)
+	} else {
+		r0 = args.Int(0)
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, []domain.MailEvent) error); ok {
+		r1 = rf(ctx, events)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// RecommendationRepositoryMock struct
+type RecommendationRepositoryMock struct {
+	mock.Mock
+}
+
+// GetCandidates - RecommendationRepository
+func (m *RecommendationRepositoryMock) GetCandidates(ctx context.Context, studentID string, class string) ([]domain.Candidate, error) {
+	args := m.Called(ctx, studentID, class)
+
+	var r0 []domain.Candidate
+	if rf, ok := args.Get(0).(func(context.Context, string, string) []domain.Candidate); ok {
+		r0 = rf(ctx, studentID, class)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Candidate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, studentID, class)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWeights - RecommendationRepository
+func (m *RecommendationRepositoryMock) GetWeights(ctx context.Context) (map[string]float64, error) {
+	args := m.Called(ctx)
+
+	var r0 map[string]float64
+	if rf, ok := args.Get(0).(func(context.Context) map[string]float64); ok {
+		r0 = rf(ctx)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).(map[string]float64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// RecommendationUseCase Mock struct
+type RecommendationUseCase struct {
+	mock.Mock
+}
+
+// Recommend - RecommendationUseCase
+func (m *RecommendationUseCase) Recommend(ctx context.Context, studentID string, class string, limit int) ([]domain.Recommendation, error) {
+	args := m.Called(ctx, studentID, class, limit)
+
+	var r0 []domain.Recommendation
+	if rf, ok := args.Get(0).(func(context.Context, string, string, int) []domain.Recommendation); ok {
+		r0 = rf(ctx, studentID, class, limit)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Recommendation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, studentID, class, limit)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
@@ -35,6 +35,29 @@ func (m *ReviewRepositoryMock) GetReviewsFor(ctx context.Context, reviewed strin
 	return r0, r1
 }
 
+// GetReviewsForAll mock function
+func (m *ReviewRepositoryMock) GetReviewsForAll(ctx context.Context, reviewed []string) ([]domain.Review, error) {
+	args := m.Called(ctx, reviewed)
+
+	var r0 []domain.Review
+	if rf, ok := args.Get(0).(func(context.Context, []string) []domain.Review); ok {
+		r0 = rf(ctx, reviewed)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Review)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, reviewed)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetReviewsBy mock function
 func (m *ReviewRepositoryMock) GetReviewsBy(ctx context.Context, reviewer string) ([]domain.Review, error) {
 	args := m.Called(ctx, reviewer)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/airbenders/profile/domain"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 // SchoolRepositoryMock struct
@@ -12,12 +13,12 @@ type SchoolRepositoryMock struct {
 }
 
 // SearchByDomain -- SchoolRepositoryMock
-func (m *SchoolRepositoryMock) SearchByDomain(ctx context.Context, name string) ([]domain.School, error) {
-	args := m.Called(ctx, name)
+func (m *SchoolRepositoryMock) SearchByDomain(ctx context.Context, domains []string) ([]domain.School, error) {
+	args := m.Called(ctx, domains)
 
 	var r0 []domain.School
-	if rf, ok := args.Get(0).(func(context.Context, string) []domain.School); ok {
-		r0 = rf(ctx, name)
+	if rf, ok := args.Get(0).(func(context.Context, []string) []domain.School); ok {
+		r0 = rf(ctx, domains)
 	} else {
 		if args.Get(0) != nil {
 			r0 = args.Get(0).([]domain.School)
@@ -25,8 +26,8 @@ func (m *SchoolRepositoryMock) SearchByDomain(ctx context.Context, name string)
 	}
 
 	var r1 error
-	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, name)
+	if rf, ok := args.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, domains)
 	} else {
 		r1 = args.Error(1)
 	}
@@ -34,26 +35,99 @@ func (m *SchoolRepositoryMock) SearchByDomain(ctx context.Context, name string)
 	return r0, r1
 }
 
-// SaveConfirmationToken -- SchoolRepositoryMock
-func (m *SchoolRepositoryMock) SaveConfirmationToken(ctx context.Context, confirmation *domain.Confirmation) error {
-	args := m.Called(ctx, confirmation)
+// SearchByName -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) SearchByName(ctx context.Context, name string, country string, page domain.Page) ([]domain.School, error) {
+	args := m.Called(ctx, name, country, page)
 
-	var r0 error
-	if rf, ok := args.Get(0).(func(context.Context, *domain.Confirmation) error); ok {
-		r0 = rf(ctx, confirmation)
+	var r0 []domain.School
+	if rf, ok := args.Get(0).(func(context.Context, string, string, domain.Page) []domain.School); ok {
+		r0 = rf(ctx, name, country, page)
 	} else {
-		r0 = args.Error(0)
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.School)
+		}
 	}
-	return r0
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string, string, domain.Page) error); ok {
+		r1 = rf(ctx, name, country, page)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SchoolEmailTaken -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) SchoolEmailTaken(ctx context.Context, schoolID string, email string, studentID string) (bool, error) {
+	args := m.Called(ctx, schoolID, email, studentID)
+
+	var r0 bool
+	if rf, ok := args.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, schoolID, email, studentID)
+	} else {
+		r0 = args.Bool(0)
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, schoolID, email, studentID)
+	} else {
+		r1 = args.Error(1)
+	}
+	return r0, r1
+}
+
+// GetEmailSuppression -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) GetEmailSuppression(ctx context.Context, email string) (*domain.MailSuppression, error) {
+	args := m.Called(ctx, email)
+
+	var r0 *domain.MailSuppression
+	if rf, ok := args.Get(0).(func(context.Context, string) *domain.MailSuppression); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).(*domain.MailSuppression)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = args.Error(1)
+	}
+	return r0, r1
+}
+
+// SaveConfirmationToken -- SchoolRepositoryMock. The sends returned first are given to checkSends
+func (m *SchoolRepositoryMock) SaveConfirmationToken(ctx context.Context, confirmation *domain.Confirmation, since time.Time, checkSends func([]domain.ConfirmationSend) error) error {
+	args := m.Called(ctx, confirmation, since)
+
+	var sends []domain.ConfirmationSend
+	if args.Get(0) != nil {
+		sends = args.Get(0).([]domain.ConfirmationSend)
+	}
+	if err := checkSends(sends); err != nil {
+		return err
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, *domain.Confirmation, time.Time) error); ok {
+		r1 = rf(ctx, confirmation, since)
+	} else {
+		r1 = args.Error(1)
+	}
+	return r1
 }
 
 // GetConfirmationByToken -- SchoolRepositoryMock
-func (m *SchoolRepositoryMock) GetConfirmationByToken(ctx context.Context, token string) (*domain.Confirmation, error) {
-	args := m.Called(ctx, token)
+func (m *SchoolRepositoryMock) GetConfirmationByToken(ctx context.Context, tokenHash string) (*domain.Confirmation, error) {
+	args := m.Called(ctx, tokenHash)
 
 	var r0 *domain.Confirmation
 	if rf, ok := args.Get(0).(func(context.Context, string) *domain.Confirmation); ok {
-		r0 = rf(ctx, token)
+		r0 = rf(ctx, tokenHash)
 	} else {
 		if args.Get(0) != nil {
 			r0 = args.Get(0).(*domain.Confirmation)
@@ -62,24 +136,205 @@ func (m *SchoolRepositoryMock) GetConfirmationByToken(ctx context.Context, token
 
 	var r1 error
 	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, token)
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+
+}
+
+// AddCodeAttempt -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) AddCodeAttempt(ctx context.Context, tokenHash string) (bool, error) {
+	args := m.Called(ctx, tokenHash)
+
+	var r0 bool
+	if rf, ok := args.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = args.Bool(0)
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = args.Error(1)
+	}
+	return r0, r1
+}
+
+// ConsumeConfirmation -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) ConsumeConfirmation(ctx context.Context, confirmation *domain.Confirmation, at time.Time) error {
+	args := m.Called(ctx, confirmation, at)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, *domain.Confirmation, time.Time) error); ok {
+		r0 = rf(ctx, confirmation, at)
+	} else {
+		r0 = args.Error(0)
+	}
+	return r0
+}
+
+// DeleteExpiredConfirmations -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) DeleteExpiredConfirmations(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+
+	var r0 int64
+	if rf, ok := args.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = args.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = args.Error(1)
+	}
+	return r0, r1
+}
+
+// RemoveSchool -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) RemoveSchool(ctx context.Context, studentID string, at time.Time) error {
+	args := m.Called(ctx, studentID, at)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, studentID, at)
+	} else {
+		r0 = args.Error(0)
+	}
+	return r0
+}
+
+// GetSchoolHistory -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) GetSchoolHistory(ctx context.Context, studentID string) ([]domain.SchoolAffiliation, error) {
+	args := m.Called(ctx, studentID)
+
+	var r0 []domain.SchoolAffiliation
+	if rf, ok := args.Get(0).(func(context.Context, string) []domain.SchoolAffiliation); ok {
+		r0 = rf(ctx, studentID)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.SchoolAffiliation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, studentID)
 	} else {
 		r1 = args.Error(1)
 	}
 
 	return r0, r1
+}
+
+// GetCampuses -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) GetCampuses(ctx context.Context, schoolID string) ([]domain.Campus, error) {
+	args := m.Called(ctx, schoolID)
+
+	var r0 []domain.Campus
+	if rf, ok := args.Get(0).(func(context.Context, string) []domain.Campus); ok {
+		r0 = rf(ctx, schoolID)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Campus)
+		}
+	}
 
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, schoolID)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
 }
 
-// AddSchoolForStudent -- SchoolRepositoryMock
-func (m *SchoolRepositoryMock) AddSchoolForStudent(ctx context.Context, stID string, scID string) error {
-	args := m.Called(ctx, stID, scID)
+// GetFaculties -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) GetFaculties(ctx context.Context, schoolID string) ([]domain.Faculty, error) {
+	args := m.Called(ctx, schoolID)
+
+	var r0 []domain.Faculty
+	if rf, ok := args.Get(0).(func(context.Context, string) []domain.Faculty); ok {
+		r0 = rf(ctx, schoolID)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Faculty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, schoolID)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSchoolUnits -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) UpdateSchoolUnits(ctx context.Context, studentID string, schoolID string, units domain.SchoolUnits) error {
+	args := m.Called(ctx, studentID, schoolID, units)
 
 	var r0 error
-	if rf, ok := args.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, stID, scID)
+	if rf, ok := args.Get(0).(func(context.Context, string, string, domain.SchoolUnits) error); ok {
+		r0 = rf(ctx, studentID, schoolID, units)
 	} else {
 		r0 = args.Error(0)
 	}
 	return r0
 }
+
+// SearchCourses -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) SearchCourses(ctx context.Context, schoolID string, codePrefix string, title string) ([]domain.Course, error) {
+	args := m.Called(ctx, schoolID, codePrefix, title)
+
+	var r0 []domain.Course
+	if rf, ok := args.Get(0).(func(context.Context, string, string, string) []domain.Course); ok {
+		r0 = rf(ctx, schoolID, codePrefix, title)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Course)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, schoolID, codePrefix, title)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingConfirmations -- SchoolRepositoryMock
+func (m *SchoolRepositoryMock) GetPendingConfirmations(ctx context.Context, studentID string, since time.Time) ([]domain.Confirmation, error) {
+	args := m.Called(ctx, studentID, since)
+
+	var r0 []domain.Confirmation
+	if rf, ok := args.Get(0).(func(context.Context, string, time.Time) []domain.Confirmation); ok {
+		r0 = rf(ctx, studentID, since)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Confirmation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, studentID, since)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
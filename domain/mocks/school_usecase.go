@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/airbenders/profile/domain"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 // SchoolUseCase Mock struct
@@ -32,13 +33,47 @@ func (m *SchoolUseCase) SearchSchoolByDomain(c context.Context, domainName strin
 	return r0, r1
 }
 
+// ResolveSchool - SchoolUseCase
+func (m *SchoolUseCase) ResolveSchool(c context.Context, domainName string, schoolID string) ([]domain.School, error) {
+	args := m.Called(c, domainName, schoolID)
+
+	var r0 []domain.School
+	if rf, ok := args.Get(0).(func(context.Context, string, string) []domain.School); ok {
+		r0 = rf(c, domainName, schoolID)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.School)
+		}
+	}
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, domainName, schoolID)
+	} else {
+		r1 = args.Error(1)
+	}
+	return r0, r1
+}
+
 // SendConfirmation - SchoolUseCase
-func (m *SchoolUseCase) SendConfirmation(c context.Context, st *domain.Student, email string, school *domain.School) error {
-	args := m.Called(c, st, email, school)
+func (m *SchoolUseCase) SendConfirmation(c context.Context, st *domain.Student, email string, school *domain.School, locale string) error {
+	args := m.Called(c, st, email, school, locale)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, *domain.Student, string, *domain.School, string) error); ok {
+		r0 = rf(c, st, email, school, locale)
+	} else {
+		r0 = args.Error(0)
+	}
+	return r0
+}
+
+// ResendConfirmation - SchoolUseCase
+func (m *SchoolUseCase) ResendConfirmation(c context.Context, studentID string, locale string) error {
+	args := m.Called(c, studentID, locale)
 
 	var r0 error
-	if rf, ok := args.Get(0).(func(context.Context, *domain.Student, string, *domain.School) error); ok {
-		r0 = rf(c, st, email, school)
+	if rf, ok := args.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, studentID, locale)
 	} else {
 		r0 = args.Error(0)
 	}
@@ -46,15 +81,183 @@ func (m *SchoolUseCase) SendConfirmation(c context.Context, st *domain.Student,
 }
 
 // ConfirmSchoolEnrollment - SchoolUseCase
-func (m *SchoolUseCase) ConfirmSchoolEnrollment(c context.Context, token string) error {
+func (m *SchoolUseCase) ConfirmSchoolEnrollment(c context.Context, token string) (domain.ConfirmationStatus, error) {
 	args := m.Called(c, token)
 
+	var r0 domain.ConfirmationStatus
+	if rf, ok := args.Get(0).(func(context.Context, string) domain.ConfirmationStatus); ok {
+		r0 = rf(c, token)
+	} else {
+		r0 = args.Get(0).(domain.ConfirmationStatus)
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, token)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmSchoolWithCode - SchoolUseCase
+func (m *SchoolUseCase) ConfirmSchoolWithCode(c context.Context, studentID string, code string) error {
+	args := m.Called(c, studentID, code)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, studentID, code)
+	} else {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
+
+// LeaveSchool - SchoolUseCase
+func (m *SchoolUseCase) LeaveSchool(c context.Context, studentID string) error {
+	args := m.Called(c, studentID)
+
 	var r0 error
 	if rf, ok := args.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(c, token)
+		r0 = rf(c, studentID)
+	} else {
+		r0 = args.Error(0)
+	}
+
+	return r0
+}
+
+// GetSchoolHistory - SchoolUseCase
+func (m *SchoolUseCase) GetSchoolHistory(c context.Context, studentID string) ([]domain.SchoolAffiliation, error) {
+	args := m.Called(c, studentID)
+
+	var r0 []domain.SchoolAffiliation
+	if rf, ok := args.Get(0).(func(context.Context, string) []domain.SchoolAffiliation); ok {
+		r0 = rf(c, studentID)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.SchoolAffiliation)
+		}
+	}
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, studentID)
+	} else {
+		r1 = args.Error(1)
+	}
+	return r0, r1
+}
+
+// PurgeExpiredConfirmations - SchoolUseCase
+func (m *SchoolUseCase) PurgeExpiredConfirmations(interval time.Duration) {
+	m.Called(interval)
+}
+
+// SchoolChangedTopic - SchoolUseCase
+func (m *SchoolUseCase) SchoolChangedTopic() {
+	m.Called()
+}
+
+// SearchSchools - SchoolUseCase
+func (m *SchoolUseCase) SearchSchools(c context.Context, query string, country string, page domain.Page) ([]domain.School, error) {
+	args := m.Called(c, query, country, page)
+
+	var r0 []domain.School
+	if rf, ok := args.Get(0).(func(context.Context, string, string, domain.Page) []domain.School); ok {
+		r0 = rf(c, query, country, page)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.School)
+		}
+	}
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string, string, domain.Page) error); ok {
+		r1 = rf(c, query, country, page)
+	} else {
+		r1 = args.Error(1)
+	}
+	return r0, r1
+}
+
+// GetCampuses - SchoolUseCase
+func (m *SchoolUseCase) GetCampuses(ctx context.Context, schoolID string) ([]domain.Campus, error) {
+	args := m.Called(ctx, schoolID)
+
+	var r0 []domain.Campus
+	if rf, ok := args.Get(0).(func(context.Context, string) []domain.Campus); ok {
+		r0 = rf(ctx, schoolID)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Campus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, schoolID)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFaculties - SchoolUseCase
+func (m *SchoolUseCase) GetFaculties(ctx context.Context, schoolID string) ([]domain.Faculty, error) {
+	args := m.Called(ctx, schoolID)
+
+	var r0 []domain.Faculty
+	if rf, ok := args.Get(0).(func(context.Context, string) []domain.Faculty); ok {
+		r0 = rf(ctx, schoolID)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Faculty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, schoolID)
+	} else {
+		r1 = args.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectSchoolUnits - SchoolUseCase
+func (m *SchoolUseCase) SelectSchoolUnits(ctx context.Context, studentID string, units domain.SchoolUnits) error {
+	args := m.Called(ctx, studentID, units)
+
+	var r0 error
+	if rf, ok := args.Get(0).(func(context.Context, string, domain.SchoolUnits) error); ok {
+		r0 = rf(ctx, studentID, units)
 	} else {
 		r0 = args.Error(0)
 	}
 
 	return r0
 }
+
+// SearchCourses - SchoolUseCase
+func (m *SchoolUseCase) SearchCourses(c context.Context, studentID string, query string) ([]domain.Course, error) {
+	args := m.Called(c, studentID, query)
+
+	var r0 []domain.Course
+	if rf, ok := args.Get(0).(func(context.Context, string, string) []domain.Course); ok {
+		r0 = rf(c, studentID, query)
+	} else {
+		if args.Get(0) != nil {
+			r0 = args.Get(0).([]domain.Course)
+		}
+	}
+	var r1 error
+	if rf, ok := args.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, studentID, query)
+	} else {
+		r1 = args.Error(1)
+	}
+	return r0, r1
+}
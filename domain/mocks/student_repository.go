@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/airbenders/profile/domain"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 // StudentRepositoryMock struct
@@ -74,24 +75,24 @@ func (m *StudentRepositoryMock) Delete(ctx context.Context, id string) error {
 	return r0
 }
 
-func (m *StudentRepositoryMock) UpdateClasses(c context.Context, st *domain.Student) error {
-	ret := m.Called(c, st)
+func (m *StudentRepositoryMock) UpdateClasses(c context.Context, st *domain.Student, enrollments []domain.Enrollment) error {
+	ret := m.Called(c, st, enrollments)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Student) error); ok {
-		r0 = rf(c, st)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Student, []domain.Enrollment) error); ok {
+		r0 = rf(c, st, enrollments)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
-func (m *StudentRepositoryMock) SearchStudents(ctx context.Context, st *domain.Student) ([]domain.Student, error) {
-	ret := m.Called(ctx, st)
+func (m *StudentRepositoryMock) SearchStudents(ctx context.Context, viewerID string, st *domain.Student) ([]domain.Student, error) {
+	ret := m.Called(ctx, viewerID, st)
 
 	var r0 []domain.Student
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Student) []domain.Student); ok {
-		r0 = rf(ctx, st)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Student) []domain.Student); ok {
+		r0 = rf(ctx, viewerID, st)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Student)
@@ -99,8 +100,339 @@ func (m *StudentRepositoryMock) SearchStudents(ctx context.Context, st *domain.S
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.Student) error); ok {
-		r1 = rf(ctx, st)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Student) error); ok {
+		r1 = rf(ctx, viewerID, st)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveEnrollments -- StudentRepositoryMock
+func (m *StudentRepositoryMock) SaveEnrollments(ctx context.Context, enrollments []domain.Enrollment) error {
+	ret := m.Called(ctx, enrollments)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Enrollment) error); ok {
+		r0 = rf(ctx, enrollments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetEnrollments -- StudentRepositoryMock
+func (m *StudentRepositoryMock) GetEnrollments(ctx context.Context, studentID string) ([]domain.Enrollment, error) {
+	ret := m.Called(ctx, studentID)
+
+	var r0 []domain.Enrollment
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Enrollment); ok {
+		r0 = rf(ctx, studentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Enrollment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, studentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClassmatesInTerm -- StudentRepositoryMock
+func (m *StudentRepositoryMock) GetClassmatesInTerm(ctx context.Context, viewerID string, schoolID string, class string, term domain.Term) ([]domain.Student, error) {
+	ret := m.Called(ctx, viewerID, schoolID, class, term)
+
+	var r0 []domain.Student
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, domain.Term) []domain.Student); ok {
+		r0 = rf(ctx, viewerID, schoolID, class, term)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Student)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, domain.Term) error); ok {
+		r1 = rf(ctx, viewerID, schoolID, class, term)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteEndedTerms -- StudentRepositoryMock
+func (m *StudentRepositoryMock) CompleteEndedTerms(ctx context.Context, now time.Time) (int64, error) {
+	ret := m.Called(ctx, now)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClassmates -- StudentRepositoryMock
+func (m *StudentRepositoryMock) GetClassmates(ctx context.Context, st *domain.Student, filter domain.ClassmateFilter, page domain.Page) ([]domain.Classmate, error) {
+	ret := m.Called(ctx, st, filter, page)
+
+	var r0 []domain.Classmate
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Student, domain.ClassmateFilter, domain.Page) []domain.Classmate); ok {
+		r0 = rf(ctx, st, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Classmate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Student, domain.ClassmateFilter, domain.Page) error); ok {
+		r1 = rf(ctx, st, filter, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrivacy -- StudentRepositoryMock
+func (m *StudentRepositoryMock) GetPrivacy(ctx context.Context, id string) (*domain.Privacy, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 *domain.Privacy
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Privacy); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Privacy)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePrivacy -- StudentRepositoryMock
+func (m *StudentRepositoryMock) UpdatePrivacy(ctx context.Context, id string, privacy *domain.Privacy) error {
+	ret := m.Called(ctx, id, privacy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Privacy) error); ok {
+		r0 = rf(ctx, id, privacy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveBlock -- StudentRepositoryMock
+func (m *StudentRepositoryMock) SaveBlock(ctx context.Context, block *domain.Block) error {
+	ret := m.Called(ctx, block)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Block) error); ok {
+		r0 = rf(ctx, block)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteBlock -- StudentRepositoryMock
+func (m *StudentRepositoryMock) DeleteBlock(ctx context.Context, blockerID string, blockedID string, kind domain.BlockKind) error {
+	ret := m.Called(ctx, blockerID, blockedID, kind)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.BlockKind) error); ok {
+		r0 = rf(ctx, blockerID, blockedID, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBlocks -- StudentRepositoryMock
+func (m *StudentRepositoryMock) GetBlocks(ctx context.Context, blockerID string) ([]domain.Block, error) {
+	ret := m.Called(ctx, blockerID)
+
+	var r0 []domain.Block
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Block); ok {
+		r0 = rf(ctx, blockerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Block)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, blockerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsBlocked -- StudentRepositoryMock
+func (m *StudentRepositoryMock) IsBlocked(ctx context.Context, id string, otherID string) (bool, error) {
+	ret := m.Called(ctx, id, otherID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, id, otherID)
+	} else {
+		r0 = ret.Bool(0)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, otherID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAvailabilities -- StudentRepositoryMock
+func (m *StudentRepositoryMock) GetAvailabilities(ctx context.Context, ids []string) ([]domain.Availability, error) {
+	ret := m.Called(ctx, ids)
+
+	var r0 []domain.Availability
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Availability); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Availability)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveAvailability -- StudentRepositoryMock
+func (m *StudentRepositoryMock) SaveAvailability(ctx context.Context, availability *domain.Availability) error {
+	ret := m.Called(ctx, availability)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Availability) error); ok {
+		r0 = rf(ctx, availability)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAvatar -- StudentRepositoryMock
+func (m *StudentRepositoryMock) UpdateAvatar(ctx context.Context, id string, avatar *domain.Avatar) error {
+	ret := m.Called(ctx, id, avatar)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Avatar) error); ok {
+		r0 = rf(ctx, id, avatar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByIDs -- StudentRepositoryMock
+func (m *StudentRepositoryMock) GetByIDs(ctx context.Context, viewerID string, ids []string) ([]domain.Student, error) {
+	ret := m.Called(ctx, viewerID, ids)
+
+	var r0 []domain.Student
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) []domain.Student); ok {
+		r0 = rf(ctx, viewerID, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Student)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, viewerID, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReputation -- StudentRepositoryMock
+func (m *StudentRepositoryMock) GetReputation(ctx context.Context, id string) (int, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Int(0)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUndeliverableSchoolEmail -- StudentRepositoryMock
+func (m *StudentRepositoryMock) GetUndeliverableSchoolEmail(ctx context.Context, id string, since time.Time) (*domain.MailSuppression, error) {
+	ret := m.Called(ctx, id, since)
+
+	var r0 *domain.MailSuppression
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.MailSuppression); ok {
+		r0 = rf(ctx, id, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MailSuppression)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, id, since)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/airbenders/profile/domain"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 // StudentUseCase Mock struct
@@ -23,6 +24,14 @@ func (m *StudentUseCase) CreateStudentTopic() {
 	panic("implement me")
 }
 
+func (m *StudentUseCase) BlockStudentTopic() {
+	panic("implement me")
+}
+
+func (m *StudentUseCase) UnblockStudentTopic() {
+	panic("implement me")
+}
+
 // Create - StudentUseCaseMock
 func (m *StudentUseCase) Create(ctx context.Context, st *domain.Student) error {
 	args := m.Called(ctx, st)
@@ -37,12 +46,12 @@ func (m *StudentUseCase) Create(ctx context.Context, st *domain.Student) error {
 }
 
 // GetByID - StudentUseCaseMock
-func (m *StudentUseCase) GetByID(ctx context.Context, id string) (*domain.Student, error) {
-	args := m.Called(ctx, id)
+func (m *StudentUseCase) GetByID(ctx context.Context, viewerID string, id string, query domain.ProfileQuery) (*domain.Student, error) {
+	args := m.Called(ctx, viewerID, id, query)
 
 	var r0 *domain.Student
-	if rf, ok := args.Get(0).(func(context.Context, string) *domain.Student); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := args.Get(0).(func(context.Context, string, string, domain.ProfileQuery) *domain.Student); ok {
+		r0 = rf(ctx, viewerID, id, query)
 	} else {
 		if args.Get(0) != nil {
 			r0 = args.Get(0).(*domain.Student)
@@ -50,8 +59,8 @@ func (m *StudentUseCase) GetByID(ctx context.Context, id string) (*domain.Studen
 	}
 
 	var r1 error
-	if rf, ok := args.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := args.Get(1).(func(context.Context, string, string, domain.ProfileQuery) error); ok {
+		r1 = rf(ctx, viewerID, id, query)
 	} else {
 		r1 = args.Error(1)
 	}
@@ -134,12 +143,12 @@ func (m *StudentUseCase) RemoveClasses(c context.Context, id string, st *domain.
 
 	return r0
 }
-func (m *StudentUseCase) SearchStudents(ctx context.Context, st *domain.Student) ([]domain.Student, error) {
-	ret := m.Called(ctx, st)
+func (m *StudentUseCase) SearchStudents(ctx context.Context, viewerID string, st *domain.Student) ([]domain.Student, error) {
+	ret := m.Called(ctx, viewerID, st)
 
 	var r0 []domain.Student
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Student) []domain.Student); ok {
-		r0 = rf(ctx, st)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Student) []domain.Student); ok {
+		r0 = rf(ctx, viewerID, st)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Student)
@@ -147,8 +156,326 @@ func (m *StudentUseCase) SearchStudents(ctx context.Context, st *domain.Student)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *domain.Student) error); ok {
-		r1 = rf(ctx, st)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.Student) error); ok {
+		r1 = rf(ctx, viewerID, st)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveEnrollments - StudentUseCaseMock
+func (m *StudentUseCase) SaveEnrollments(ctx context.Context, id string, enrollments []domain.Enrollment) error {
+	ret := m.Called(ctx, id, enrollments)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.Enrollment) error); ok {
+		r0 = rf(ctx, id, enrollments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetEnrollments - StudentUseCaseMock
+func (m *StudentUseCase) GetEnrollments(ctx context.Context, id string) ([]domain.Enrollment, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 []domain.Enrollment
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Enrollment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Enrollment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetClassmatesInTerm - StudentUseCaseMock
+func (m *StudentUseCase) GetClassmatesInTerm(ctx context.Context, id string, class string, term domain.Term) ([]domain.Student, error) {
+	ret := m.Called(ctx, id, class, term)
+
+	var r0 []domain.Student
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.Term) []domain.Student); ok {
+		r0 = rf(ctx, id, class, term)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Student)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.Term) error); ok {
+		r1 = rf(ctx, id, class, term)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (m *StudentUseCase) RollOverTerms(interval time.Duration) {
+	panic("implement me")
+}
+
+// GetClassmates - StudentUseCaseMock
+func (m *StudentUseCase) GetClassmates(ctx context.Context, id string, filter domain.ClassmateFilter, page domain.Page) ([]domain.Classmate, error) {
+	ret := m.Called(ctx, id, filter, page)
+
+	var r0 []domain.Classmate
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ClassmateFilter, domain.Page) []domain.Classmate); ok {
+		r0 = rf(ctx, id, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Classmate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ClassmateFilter, domain.Page) error); ok {
+		r1 = rf(ctx, id, filter, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrivacy - StudentUseCaseMock
+func (m *StudentUseCase) GetPrivacy(ctx context.Context, id string) (*domain.Privacy, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 *domain.Privacy
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Privacy); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Privacy)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePrivacy - StudentUseCaseMock
+func (m *StudentUseCase) UpdatePrivacy(ctx context.Context, id string, privacy *domain.Privacy) error {
+	ret := m.Called(ctx, id, privacy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Privacy) error); ok {
+		r0 = rf(ctx, id, privacy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Block - StudentUseCaseMock
+func (m *StudentUseCase) Block(ctx context.Context, id string, targetID string, kind domain.BlockKind) error {
+	ret := m.Called(ctx, id, targetID, kind)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.BlockKind) error); ok {
+		r0 = rf(ctx, id, targetID, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unblock - StudentUseCaseMock
+func (m *StudentUseCase) Unblock(ctx context.Context, id string, targetID string, kind domain.BlockKind) error {
+	ret := m.Called(ctx, id, targetID, kind)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.BlockKind) error); ok {
+		r0 = rf(ctx, id, targetID, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBlocks - StudentUseCaseMock
+func (m *StudentUseCase) GetBlocks(ctx context.Context, id string) ([]domain.Block, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 []domain.Block
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Block); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Block)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAvailability - StudentUseCaseMock
+func (m *StudentUseCase) GetAvailability(ctx context.Context, viewerID string, id string) (*domain.Availability, error) {
+	ret := m.Called(ctx, viewerID, id)
+
+	var r0 *domain.Availability
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Availability); ok {
+		r0 = rf(ctx, viewerID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Availability)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, viewerID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAvailability - StudentUseCaseMock
+func (m *StudentUseCase) UpdateAvailability(ctx context.Context, id string, availability *domain.Availability) error {
+	ret := m.Called(ctx, id, availability)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.Availability) error); ok {
+		r0 = rf(ctx, id, availability)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCommonAvailability - StudentUseCaseMock
+func (m *StudentUseCase) GetCommonAvailability(ctx context.Context, viewerID string, ids []string, from, to time.Time) ([]domain.Interval, error) {
+	ret := m.Called(ctx, viewerID, ids, from, to)
+
+	var r0 []domain.Interval
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, time.Time, time.Time) []domain.Interval); ok {
+		r0 = rf(ctx, viewerID, ids, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Interval)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, viewerID, ids, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAvatar - StudentUseCaseMock
+func (m *StudentUseCase) UpdateAvatar(ctx context.Context, id string, image []byte) (*domain.Avatar, error) {
+	ret := m.Called(ctx, id, image)
+
+	var r0 *domain.Avatar
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) *domain.Avatar); ok {
+		r0 = rf(ctx, id, image)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Avatar)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []byte) error); ok {
+		r1 = rf(ctx, id, image)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAvatar - StudentUseCaseMock
+func (m *StudentUseCase) DeleteAvatar(ctx context.Context, id string) error {
+	ret := m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetOnboarding - StudentUseCaseMock
+func (m *StudentUseCase) GetOnboarding(ctx context.Context, id string) (*domain.Onboarding, error) {
+	ret := m.Called(ctx, id)
+
+	var r0 *domain.Onboarding
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Onboarding); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Onboarding)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BatchGet - StudentUseCaseMock
+func (m *StudentUseCase) BatchGet(ctx context.Context, viewerID string, ids []string, include []string) (*domain.BatchGetResult, error) {
+	ret := m.Called(ctx, viewerID, ids, include)
+
+	var r0 *domain.BatchGetResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, []string) *domain.BatchGetResult); ok {
+		r0 = rf(ctx, viewerID, ids, include)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.BatchGetResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, []string) error); ok {
+		r1 = rf(ctx, viewerID, ids, include)
 	} else {
 		r1 = ret.Error(1)
 	}
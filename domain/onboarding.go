@@ -0,0 +1,55 @@
+package domain
+
+import "strings"
+
+// The steps of setting up a profile
+const (
+	StepConfirmSchool   = "confirm_school"
+	StepAddClasses      = "add_classes"
+	StepGeneralInfo     = "general_info"
+	StepUploadAvatar    = "upload_avatar"
+	StepSetAvailability = "set_availability"
+)
+
+// OnboardingStep is one thing a student does to complete their profile
+type OnboardingStep struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Done        bool   `json:"done"`
+}
+
+// Onboarding is how complete a profile is. Score is the percentage of steps done and Missing lists the names of the
+// steps left, in the order they should be done. UndeliverableEmail is set when the pending school confirmation was
+// sent to an address that can't get emails, so the student uses another one
+type Onboarding struct {
+	Score              int              `json:"score"`
+	Steps              []OnboardingStep `json:"steps"`
+	Missing            []string         `json:"missing"`
+	UndeliverableEmail *MailSuppression `json:"undeliverable_email,omitempty"`
+}
+
+// NewOnboarding checks the steps of the student's profile. availability is nil if the student never set it
+func NewOnboarding(st *Student, availability *Availability) *Onboarding {
+	hasAvailability := availability != nil && (len(availability.Weekly) > 0 || len(availability.Exceptions) > 0)
+	steps := []OnboardingStep{
+		{Name: StepConfirmSchool, Description: "Confirm your school email", Done: st.School != nil},
+		{Name: StepAddClasses, Description: "Add the classes you are taking",
+			Done: len(st.CurrentClasses) > 0 || len(st.ClassesTaken) > 0},
+		{Name: StepGeneralInfo, Description: "Tell other students about yourself",
+			Done: strings.TrimSpace(st.GeneralInfo) != ""},
+		{Name: StepUploadAvatar, Description: "Upload a profile picture", Done: st.Avatar != nil},
+		{Name: StepSetAvailability, Description: "Set when you are available to meet", Done: hasAvailability},
+	}
+
+	onboarding := &Onboarding{Steps: steps, Missing: []string{}}
+	done := 0
+	for _, step := range steps {
+		if step.Done {
+			done++
+		} else {
+			onboarding.Missing = append(onboarding.Missing, step.Name)
+		}
+	}
+	onboarding.Score = done * 100 / len(steps)
+	return onboarding
+}
@@ -0,0 +1,38 @@
+package domain_test
+
+import (
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewOnboarding(t *testing.T) {
+	t.Run("new-profile", func(t *testing.T) {
+		onboarding := domain.NewOnboarding(&domain.Student{ID: "a", FirstName: "b", GeneralInfo: "  "}, nil)
+
+		assert.Equal(t, 0, onboarding.Score)
+		assert.EqualValues(t, []string{domain.StepConfirmSchool, domain.StepAddClasses, domain.StepGeneralInfo,
+			domain.StepUploadAvatar, domain.StepSetAvailability}, onboarding.Missing)
+	})
+
+	t.Run("empty-availability-isnt-done", func(t *testing.T) {
+		st := &domain.Student{School: &domain.School{ID: "s"}, ClassesTaken: []string{"COMP 354"}, GeneralInfo: "hi",
+			Avatar: &domain.Avatar{}}
+
+		onboarding := domain.NewOnboarding(st, &domain.Availability{TimeZone: "UTC"})
+
+		assert.Equal(t, 80, onboarding.Score)
+		assert.EqualValues(t, []string{domain.StepSetAvailability}, onboarding.Missing)
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		st := &domain.Student{School: &domain.School{ID: "s"}, CurrentClasses: []string{"COMP 354"}, GeneralInfo: "hi",
+			Avatar: &domain.Avatar{}}
+
+		onboarding := domain.NewOnboarding(st, &domain.Availability{Exceptions: []domain.AvailabilityException{{}}})
+
+		assert.Equal(t, 100, onboarding.Score)
+		assert.Empty(t, onboarding.Missing)
+		assert.Len(t, onboarding.Steps, 5)
+	})
+}
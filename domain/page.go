@@ -0,0 +1,12 @@
+package domain
+
+// Page is a window into a list of results. Number starts at 1
+type Page struct {
+	Number int `json:"page"`
+	Size   int `json:"limit"`
+}
+
+// Offset is the number of results that come before the page
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "fmt"
+
+// The relations a profile can include. Reviews and reputation take their own queries, so they are only loaded when
+// asked for, in the relations or the fields. The school is read with the student, so including it only returns it
+// whatever the fields
+const (
+	IncludeReviews    = "reviews"
+	IncludeSchool     = "school"
+	IncludeReputation = "reputation"
+)
+
+// Includables are the relations a profile can include
+var Includables = []string{IncludeReviews, IncludeSchool, IncludeReputation}
+
+// StudentFields are the JSON fields of a profile a client can ask for
+var StudentFields = []string{"id", "first_name", "last_name", "email", "general_info", "school", "current_classes",
+	"classes_taken", "CreatedAt", "UpdatedAt", "reviews", "program", "year", "pronouns", "languages", "links", "avatar",
+	"onboarding", "reputation", "school_verification", "campus", "faculty"}
+
+// ProfileQuery is what a client wants from profiles. Fields are the JSON fields to return, all of them if empty, and
+// Include the relations to load with them, which are returned whatever the fields
+type ProfileQuery struct {
+	Fields  []string
+	Include []string
+}
+
+// Includes is true if the relation is asked for, as a relation or as one of the fields
+func (q ProfileQuery) Includes(relation string) bool {
+	return contains(q.Include, relation) || contains(q.Fields, relation)
+}
+
+// Wants is true if the field is returned
+func (q ProfileQuery) Wants(field string) bool {
+	return len(q.Fields) == 0 || contains(q.Fields, field) || q.Includes(field)
+}
+
+// Validate returns an error if a field or a relation doesn't exist
+func (q ProfileQuery) Validate() error {
+	for _, field := range q.Fields {
+		if !contains(StudentFields, field) {
+			return fmt.Errorf("unknown field %q", field)
+		}
+	}
+	for _, relation := range q.Include {
+		if !contains(Includables, relation) {
+			return fmt.Errorf("can't include %q. Expected one of %v", relation, Includables)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
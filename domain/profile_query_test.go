@@ -0,0 +1,52 @@
+package domain_test
+
+import (
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfileQuery(t *testing.T) {
+	t.Run("student-fields-match-json", func(t *testing.T) {
+		var fields []string
+		studentType := reflect.TypeOf(domain.Student{})
+		for i := 0; i < studentType.NumField(); i++ {
+			name := strings.Split(studentType.Field(i).Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = studentType.Field(i).Name
+			}
+			fields = append(fields, name)
+		}
+
+		assert.ElementsMatch(t, fields, domain.StudentFields)
+	})
+
+	t.Run("wants", func(t *testing.T) {
+		query := domain.ProfileQuery{Fields: []string{"first_name"}, Include: []string{domain.IncludeSchool}}
+
+		assert.True(t, query.Wants("first_name"))
+		assert.True(t, query.Wants("school"))
+		assert.False(t, query.Wants("last_name"))
+		assert.True(t, domain.ProfileQuery{}.Wants("last_name"))
+	})
+
+	t.Run("includes", func(t *testing.T) {
+		query := domain.ProfileQuery{Fields: []string{"id", domain.IncludeReviews}, Include: []string{domain.IncludeSchool}}
+
+		assert.True(t, query.Includes(domain.IncludeSchool))
+		assert.True(t, query.Includes(domain.IncludeReviews))
+		assert.False(t, query.Includes(domain.IncludeReputation))
+		assert.False(t, domain.ProfileQuery{}.Includes(domain.IncludeReviews))
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		assert.NoError(t, domain.ProfileQuery{Fields: []string{"id", "avatar"}, Include: domain.Includables}.Validate())
+		assert.Error(t, domain.ProfileQuery{Fields: []string{"password"}}.Validate())
+		assert.Error(t, domain.ProfileQuery{Include: []string{"friends"}}.Validate())
+	})
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Recommendation is a possible teammate for a class, with the score that ranked them and the reasons behind it
+type Recommendation struct {
+	Student Student  `json:"student"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// Candidate is what is known about a possible teammate when scoring them
+type Candidate struct {
+	Student      Student
+	PositiveTags int
+	NegativeTags int
+	// AvailabilityOverlap is the weekly time the candidate and the student are both free
+	AvailabilityOverlap time.Duration
+}
+
+// RecommendationWeights is how much each signal counts towards a candidate's score
+type RecommendationWeights struct {
+	SharedClass      float64 `json:"shared_class"`
+	SharedPastClass  float64 `json:"shared_past_class"`
+	PositiveTag      float64 `json:"positive_tag"`
+	NegativeTag      float64 `json:"negative_tag"`
+	SameSchool       float64 `json:"same_school"`
+	AvailabilityHour float64 `json:"availability_hour"`
+}
+
+// DefaultRecommendationWeights are used for the weights that aren't configured
+func DefaultRecommendationWeights() RecommendationWeights {
+	return RecommendationWeights{
+		SharedClass:      3,
+		SharedPastClass:  1,
+		PositiveTag:      0.5,
+		NegativeTag:      1,
+		SameSchool:       5,
+		AvailabilityHour: 0.25,
+	}
+}
+
+// Set changes the weight with that name. Returns false if there's no such weight
+func (w *RecommendationWeights) Set(name string, value float64) bool {
+	switch name {
+	case "shared_class":
+		w.SharedClass = value
+	case "shared_past_class":
+		w.SharedPastClass = value
+	case "positive_tag":
+		w.PositiveTag = value
+	case "negative_tag":
+		w.NegativeTag = value
+	case "same_school":
+		w.SameSchool = value
+	case "availability_hour":
+		w.AvailabilityHour = value
+	default:
+		return false
+	}
+	return true
+}
+
+// RecommendationUseCase recommends teammates to students
+type RecommendationUseCase interface {
+	Recommend(ctx context.Context, studentID string, class string, limit int) ([]Recommendation, error)
+}
+
+// RecommendationRepository finds the candidates to recommend and the configured weights
+type RecommendationRepository interface {
+	GetCandidates(ctx context.Context, studentID string, class string) ([]Candidate, error)
+	GetWeights(ctx context.Context) (map[string]float64, error)
+}
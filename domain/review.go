@@ -25,6 +25,7 @@ type ReviewUseCase interface {
 // ReviewRepository is the contract every review repository must employ
 type ReviewRepository interface {
 	GetReviewsFor(ctx context.Context, reviewed string) ([]Review, error)
+	GetReviewsForAll(ctx context.Context, reviewed []string) ([]Review, error)
 	GetReviewsBy(ctx context.Context, reviewer string) ([]Review, error)
 	GetReviewByAndFor(ctx context.Context, reviewer string, reviewed string) (*Review, error)
 	AddReview(ctx context.Context, review *Review) error
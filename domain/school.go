@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // School is a basic struct, matching fields from the already existing school database
 type School struct {
@@ -8,19 +11,88 @@ type School struct {
 	Name    string   `json:"name"`
 	Country string   `json:"country"`
 	Domains []string `json:"domains"`
+	// MatchedDomain is the domain of an email the school was found with
+	MatchedDomain string `json:"matched_domain,omitempty"`
+}
+
+// Campus is a site of a school
+type Campus struct {
+	ID       string `json:"id"`
+	SchoolID string `json:"school_id"`
+	Name     string `json:"name"`
+}
+
+// Faculty is a division of a school, like its faculty of engineering
+type Faculty struct {
+	ID       string `json:"id"`
+	SchoolID string `json:"school_id"`
+	Name     string `json:"name"`
+}
+
+// SchoolUnits are the campus and the faculty a student picks in their school. An empty ID unsets it
+type SchoolUnits struct {
+	CampusID  string `json:"campus_id"`
+	FacultyID string `json:"faculty_id"`
+}
+
+// SchoolChoices is returned instead of a school when several schools share the domain of an email
+type SchoolChoices struct {
+	Message string   `json:"message"`
+	Schools []School `json:"schools"`
+}
+
+// SchoolAffiliation is a school the student confirmed, from when they confirmed it until they left it or confirmed
+// another one
+type SchoolAffiliation struct {
+	School   School     `json:"school"`
+	JoinedAt time.Time  `json:"joined_at"`
+	LeftAt   *time.Time `json:"left_at,omitempty"`
+}
+
+// SchoolChange is sent to the other services when a student confirms a new school or leaves theirs. A school ID is
+// empty when there is no school
+type SchoolChange struct {
+	StudentID        string    `json:"student_id"`
+	PreviousSchoolID string    `json:"previous_school_id"`
+	SchoolID         string    `json:"school_id"`
+	ChangedAt        time.Time `json:"changed_at"`
 }
 
 // SchoolUseCase defines the contract a school use case must have
 type SchoolUseCase interface {
 	SearchSchoolByDomain(ctx context.Context, domainName string) ([]School, error)
-	SendConfirmation(ctx context.Context, st *Student, email string, school *School) error
-	ConfirmSchoolEnrollment(ctx context.Context, token string) error
+	ResolveSchool(ctx context.Context, domainName string, schoolID string) ([]School, error)
+	SearchSchools(ctx context.Context, query string, country string, page Page) ([]School, error)
+	GetCampuses(ctx context.Context, schoolID string) ([]Campus, error)
+	GetFaculties(ctx context.Context, schoolID string) ([]Faculty, error)
+	SelectSchoolUnits(ctx context.Context, studentID string, units SchoolUnits) error
+	SendConfirmation(ctx context.Context, st *Student, email string, school *School, locale string) error
+	ResendConfirmation(ctx context.Context, studentID string, locale string) error
+	ConfirmSchoolEnrollment(ctx context.Context, token string) (ConfirmationStatus, error)
+	ConfirmSchoolWithCode(ctx context.Context, studentID string, code string) error
+	LeaveSchool(ctx context.Context, studentID string) error
+	GetSchoolHistory(ctx context.Context, studentID string) ([]SchoolAffiliation, error)
+	PurgeExpiredConfirmations(interval time.Duration)
+	SchoolChangedTopic()
+	SearchCourses(ctx context.Context, studentID string, query string) ([]Course, error)
 }
 
 // SchoolRepository defines the contract a school repository should have
 type SchoolRepository interface {
-	SearchByDomain(ctx context.Context, name string) ([]School, error)
-	SaveConfirmationToken(ctx context.Context, confirmation *Confirmation) error
-	GetConfirmationByToken(ctx context.Context, token string) (*Confirmation, error)
-	AddSchoolForStudent(ctx context.Context, stID, scID string) error
+	SearchByDomain(ctx context.Context, domains []string) ([]School, error)
+	SearchByName(ctx context.Context, name string, country string, page Page) ([]School, error)
+	GetCampuses(ctx context.Context, schoolID string) ([]Campus, error)
+	GetFaculties(ctx context.Context, schoolID string) ([]Faculty, error)
+	UpdateSchoolUnits(ctx context.Context, studentID string, schoolID string, units SchoolUnits) error
+	SaveConfirmationToken(ctx context.Context, confirmation *Confirmation, since time.Time, checkSends func([]ConfirmationSend) error) error
+	GetConfirmationByToken(ctx context.Context, tokenHash string) (*Confirmation, error)
+	AddCodeAttempt(ctx context.Context, tokenHash string) (bool, error)
+	ConsumeConfirmation(ctx context.Context, confirmation *Confirmation, at time.Time) error
+	DeleteExpiredConfirmations(ctx context.Context, before time.Time) (int64, error)
+	RemoveSchool(ctx context.Context, studentID string, at time.Time) error
+	GetSchoolHistory(ctx context.Context, studentID string) ([]SchoolAffiliation, error)
+	SchoolEmailTaken(ctx context.Context, schoolID string, email string, studentID string) (bool, error)
+	GetEmailSuppression(ctx context.Context, email string) (*MailSuppression, error)
+	GetPendingConfirmations(ctx context.Context, studentID string, since time.Time) ([]Confirmation, error)
+	SearchCourses(ctx context.Context, schoolID string, codePrefix string, title string) ([]Course, error)
 }
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -18,29 +19,249 @@ type Student struct {
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Reviews        []Review `json:"reviews" faker:"-"`
+	Program        string   `json:"program" faker:"-"`
+	// Year of study, starting at 1. 0 if not given
+	Year      int          `json:"year" faker:"-"`
+	Pronouns  string       `json:"pronouns" faker:"-"`
+	Languages []string     `json:"languages" faker:"-"`
+	Links     StudentLinks `json:"links" faker:"-"`
+	Avatar    *Avatar      `json:"avatar,omitempty" faker:"-"`
+	// Onboarding is only set when students view their own profile
+	Onboarding *Onboarding `json:"onboarding,omitempty" faker:"-"`
+	// Reputation is only set when included
+	Reputation *int `json:"reputation,omitempty" faker:"-"`
+	// SchoolVerification is how the school was confirmed. Only shown to the student
+	SchoolVerification *SchoolVerification `json:"school_verification,omitempty" faker:"-"`
+	// Campus and Faculty are picked by the student once their school is confirmed
+	Campus  *Campus  `json:"campus,omitempty" faker:"-"`
+	Faculty *Faculty `json:"faculty,omitempty" faker:"-"`
+	// Privacy is only used to hide fields from other students. It is read and changed on its own
+	Privacy Privacy `json:"-" faker:"-"`
+	// Clear lists the optional profile fields an update removes, from ?clear=year,links. It is never stored
+	Clear []string `json:"-" faker:"-"`
+}
+
+// StudentLinks are the student's profiles on other websites
+type StudentLinks struct {
+	GitHub    string `json:"github,omitempty"`
+	LinkedIn  string `json:"linkedin,omitempty"`
+	Portfolio string `json:"portfolio,omitempty"`
+}
+
+// The profile fields a student can hide from other students
+const (
+	FieldProgram   = "program"
+	FieldYear      = "year"
+	FieldPronouns  = "pronouns"
+	FieldLanguages = "languages"
+	FieldLinks     = "links"
+	FieldCampus    = "campus"
+	FieldFaculty   = "faculty"
+)
+
+// HideableFields are the profile fields a student can hide from other students
+var HideableFields = []string{FieldProgram, FieldYear, FieldPronouns, FieldLanguages, FieldLinks, FieldCampus,
+	FieldFaculty}
+
+// ClearableFields are the optional profile fields a student can remove
+var ClearableFields = []string{FieldProgram, FieldYear, FieldPronouns, FieldLanguages, FieldLinks}
+
+// Privacy holds what a student is willing to share with other students
+type Privacy struct {
+	HideFromDiscovery bool     `json:"hide_from_discovery"`
+	HiddenFields      []string `json:"hidden_fields,omitempty"`
+}
+
+// Hides is true if the field is hidden from other students
+func (p Privacy) Hides(field string) bool {
+	for _, hidden := range p.HiddenFields {
+		if hidden == field {
+			return true
+		}
+	}
+	return false
+}
+
+// HidePrivateFields clears the fields the student hides, unless the viewer is the student
+func (s *Student) HidePrivateFields(viewerID string) {
+	if viewerID == s.ID {
+		return
+	}
+	s.SchoolVerification = nil
+	if s.Privacy.Hides(FieldProgram) {
+		s.Program = ""
+	}
+	if s.Privacy.Hides(FieldYear) {
+		s.Year = 0
+	}
+	if s.Privacy.Hides(FieldPronouns) {
+		s.Pronouns = ""
+	}
+	if s.Privacy.Hides(FieldLanguages) {
+		s.Languages = nil
+	}
+	if s.Privacy.Hides(FieldLinks) {
+		s.Links = StudentLinks{}
+	}
+	if s.Privacy.Hides(FieldCampus) {
+		s.Campus = nil
+	}
+	if s.Privacy.Hides(FieldFaculty) {
+		s.Faculty = nil
+	}
+}
+
+// NeedsSchoolVerification is true if the student has no school yet, or if its verification expired. Schools confirmed
+// before their email was stored need to be verified again
+func (s *Student) NeedsSchoolVerification(now time.Time) bool {
+	return s.School == nil || s.SchoolVerification == nil || !now.Before(s.SchoolVerification.ExpiresAt())
+}
+
+// Classmate is a student sharing current classes with another one
+type Classmate struct {
+	Student       Student  `json:"student"`
+	SharedClasses []string `json:"shared_classes"`
+	Reputation    int      `json:"reputation"`
+}
+
+// ClassmateFilter narrows classmates down to the ones sharing the campus or the faculty of the student
+type ClassmateFilter struct {
+	SameCampus  bool
+	SameFaculty bool
+}
+
+// How search results can be grouped
+const (
+	GroupBySchool  = "school"
+	GroupByCountry = "country"
+)
+
+// StudentGroup are the students of a school or a country. Key is the name of the school or the country, empty for the
+// students without a school
+type StudentGroup struct {
+	Key      string    `json:"key"`
+	Students []Student `json:"students"`
+}
+
+// ValidateGroupBy returns an error if students can't be grouped that way
+func ValidateGroupBy(by string) error {
+	if by != GroupBySchool && by != GroupByCountry {
+		return fmt.Errorf("can't group by %q. Expected %q or %q", by, GroupBySchool, GroupByCountry)
+	}
+	return nil
+}
+
+// GroupStudents groups the students by school or by country, in the order the groups first appear. Students keep their
+// order within a group
+func GroupStudents(students []Student, by string) ([]StudentGroup, error) {
+	if err := ValidateGroupBy(by); err != nil {
+		return nil, err
+	}
+	groups := []StudentGroup{}
+	indexes := make(map[string]int)
+	for _, student := range students {
+		key := ""
+		if student.School != nil && by == GroupBySchool {
+			key = student.School.Name
+			if key == "" {
+				key = student.School.ID
+			}
+		} else if student.School != nil {
+			key = student.School.Country
+		}
+		i, ok := indexes[key]
+		if !ok {
+			i = len(groups)
+			indexes[key] = i
+			groups = append(groups, StudentGroup{Key: key})
+		}
+		groups[i].Students = append(groups[i].Students, student)
+	}
+	return groups, nil
+}
+
+// MaxBatchGetIDs is the most students a batch lookup can ask for
+const MaxBatchGetIDs = 100
+
+// StudentSummary is the lightweight projection of a student returned by batch lookups. Reviews are only set when
+// included
+type StudentSummary struct {
+	ID        string   `json:"id"`
+	FirstName string   `json:"first_name"`
+	LastName  string   `json:"last_name"`
+	Avatar    *Avatar  `json:"avatar,omitempty"`
+	Reviews   []Review `json:"reviews,omitempty"`
+}
+
+// BatchGetRequest are the students to look up at once
+type BatchGetRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// BatchGetResult are the students found, in the order they were asked for, and the IDs of the ones that don't exist
+// or can't be seen by the viewer
+type BatchGetResult struct {
+	Students []StudentSummary `json:"students"`
+	Missing  []string         `json:"missing"`
 }
 
 // StudentUseCase interface defines the functions all studentUseCases should have
 type StudentUseCase interface {
 	Create(ctx context.Context, st *Student) error
-	GetByID(ctx context.Context, id string) (*Student, error)
+	GetByID(ctx context.Context, viewerID string, id string, query ProfileQuery) (*Student, error)
 	Update(ctx context.Context, id string, st *Student) (*Student, error)
 	Delete(ctx context.Context, id string) error
 	AddClasses(c context.Context, id string, st *Student) error
 	RemoveClasses(c context.Context, id string, st *Student) error
 	CompleteClass(c context.Context, id string, st *Student) error
-	SearchStudents(ctx context.Context, st *Student) ([]Student, error)
+	SearchStudents(ctx context.Context, viewerID string, st *Student) ([]Student, error)
+	SaveEnrollments(ctx context.Context, id string, enrollments []Enrollment) error
+	GetEnrollments(ctx context.Context, id string) ([]Enrollment, error)
+	GetClassmatesInTerm(ctx context.Context, id string, class string, term Term) ([]Student, error)
+	RollOverTerms(interval time.Duration)
+	GetClassmates(ctx context.Context, id string, filter ClassmateFilter, page Page) ([]Classmate, error)
+	GetPrivacy(ctx context.Context, id string) (*Privacy, error)
+	UpdatePrivacy(ctx context.Context, id string, privacy *Privacy) error
+	Block(ctx context.Context, id string, targetID string, kind BlockKind) error
+	Unblock(ctx context.Context, id string, targetID string, kind BlockKind) error
+	GetBlocks(ctx context.Context, id string) ([]Block, error)
+	GetAvailability(ctx context.Context, viewerID string, id string) (*Availability, error)
+	UpdateAvailability(ctx context.Context, id string, availability *Availability) error
+	GetCommonAvailability(ctx context.Context, viewerID string, ids []string, from, to time.Time) ([]Interval, error)
+	UpdateAvatar(ctx context.Context, id string, image []byte) (*Avatar, error)
+	DeleteAvatar(ctx context.Context, id string) error
+	GetOnboarding(ctx context.Context, id string) (*Onboarding, error)
+	BatchGet(ctx context.Context, viewerID string, ids []string, include []string) (*BatchGetResult, error)
 	CreateStudentTopic()
 	UpdateStudentTopic()
 	DeleteStudentTopic()
+	BlockStudentTopic()
+	UnblockStudentTopic()
 }
 
 // StudentRepository interface defines the functions all studentRepositories should have
 type StudentRepository interface {
 	Create(ctx context.Context, id string, st *Student) error
 	GetByID(ctx context.Context, id string) (*Student, error)
+	GetByIDs(ctx context.Context, viewerID string, ids []string) ([]Student, error)
 	Update(ctx context.Context, st *Student) error
 	Delete(ctx context.Context, id string) error
-	UpdateClasses(ctx context.Context, st *Student) error
-	SearchStudents(ctx context.Context, st *Student) ([]Student, error)
+	UpdateClasses(ctx context.Context, st *Student, enrollments []Enrollment) error
+	SearchStudents(ctx context.Context, viewerID string, st *Student) ([]Student, error)
+	SaveEnrollments(ctx context.Context, enrollments []Enrollment) error
+	GetEnrollments(ctx context.Context, studentID string) ([]Enrollment, error)
+	GetClassmatesInTerm(ctx context.Context, viewerID string, schoolID string, class string, term Term) ([]Student, error)
+	CompleteEndedTerms(ctx context.Context, now time.Time) (int64, error)
+	GetClassmates(ctx context.Context, st *Student, filter ClassmateFilter, page Page) ([]Classmate, error)
+	GetPrivacy(ctx context.Context, id string) (*Privacy, error)
+	UpdatePrivacy(ctx context.Context, id string, privacy *Privacy) error
+	SaveBlock(ctx context.Context, block *Block) error
+	DeleteBlock(ctx context.Context, blockerID string, blockedID string, kind BlockKind) error
+	GetBlocks(ctx context.Context, blockerID string) ([]Block, error)
+	IsBlocked(ctx context.Context, id string, otherID string) (bool, error)
+	GetAvailabilities(ctx context.Context, ids []string) ([]Availability, error)
+	SaveAvailability(ctx context.Context, availability *Availability) error
+	UpdateAvatar(ctx context.Context, id string, avatar *Avatar) error
+	GetReputation(ctx context.Context, id string) (int, error)
+	GetUndeliverableSchoolEmail(ctx context.Context, id string, since time.Time) (*MailSuppression, error)
 }
@@ -0,0 +1,96 @@
+package domain_test
+
+import (
+	"github.com/airbenders/profile/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGroupStudents(t *testing.T) {
+	concordia := &domain.School{ID: "c", Name: "Concordia University", Country: "Canada"}
+	mcgill := &domain.School{ID: "m", Name: "McGill University", Country: "Canada"}
+	mit := &domain.School{ID: "mit", Country: "United States"}
+	students := []domain.Student{
+		{ID: "a", School: concordia},
+		{ID: "b", School: mit},
+		{ID: "c", School: mcgill},
+		{ID: "d"},
+		{ID: "e", School: concordia},
+	}
+
+	t.Run("by-school", func(t *testing.T) {
+		groups, err := domain.GroupStudents(students, domain.GroupBySchool)
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, []domain.StudentGroup{
+			{Key: "Concordia University", Students: []domain.Student{students[0], students[4]}},
+			{Key: "mit", Students: []domain.Student{students[1]}},
+			{Key: "McGill University", Students: []domain.Student{students[2]}},
+			{Key: "", Students: []domain.Student{students[3]}},
+		}, groups)
+	})
+
+	t.Run("by-country", func(t *testing.T) {
+		groups, err := domain.GroupStudents(students, domain.GroupByCountry)
+
+		assert.NoError(t, err)
+		assert.Len(t, groups, 3)
+		assert.Equal(t, "Canada", groups[0].Key)
+		assert.Len(t, groups[0].Students, 3)
+	})
+
+	t.Run("unknown-grouping", func(t *testing.T) {
+		_, err := domain.GroupStudents(students, "program")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNeedsSchoolVerification(t *testing.T) {
+	now := time.Now()
+	school := &domain.School{ID: "sc"}
+	tests := []struct {
+		name    string
+		student domain.Student
+		needs   bool
+	}{
+		{"no school", domain.Student{}, true},
+		{"school without email", domain.Student{School: school}, true},
+		{"verified", domain.Student{School: school,
+			SchoolVerification: &domain.SchoolVerification{VerifiedAt: now.Add(-time.Hour)}}, false},
+		{"expired", domain.Student{School: school,
+			SchoolVerification: &domain.SchoolVerification{VerifiedAt: now.Add(-domain.SchoolVerificationTTL)}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.needs, test.student.NeedsSchoolVerification(now))
+		})
+	}
+}
+
+func TestHidePrivateFieldsHidesSchoolVerification(t *testing.T) {
+	verification := &domain.SchoolVerification{Email: "a@concordia.ca", VerifiedAt: time.Now()}
+	student := domain.Student{ID: "a", SchoolVerification: verification}
+
+	student.HidePrivateFields("a")
+	assert.Equal(t, verification, student.SchoolVerification)
+
+	student.HidePrivateFields("b")
+	assert.Nil(t, student.SchoolVerification)
+}
+
+func TestHidePrivateFieldsHidesCampusAndFaculty(t *testing.T) {
+	campus := &domain.Campus{ID: "sgw", SchoolID: "sc", Name: "Sir George Williams"}
+	faculty := &domain.Faculty{ID: "encs", SchoolID: "sc", Name: "Engineering and Computer Science"}
+	student := domain.Student{ID: "a", Campus: campus, Faculty: faculty,
+		Privacy: domain.Privacy{HiddenFields: []string{domain.FieldCampus, domain.FieldFaculty}}}
+
+	student.HidePrivateFields("a")
+	assert.Equal(t, campus, student.Campus)
+	assert.Equal(t, faculty, student.Faculty)
+
+	student.HidePrivateFields("b")
+	assert.Nil(t, student.Campus)
+	assert.Nil(t, student.Faculty)
+}
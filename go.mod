@@ -19,6 +19,7 @@ require (
 	github.com/sony/gobreaker v0.5.0
 	github.com/streadway/amqp v0.0.0-20190827072141-edfb9018d271
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/image v0.0.0-20211028202545-6944b10bf410
 )
 
 require (
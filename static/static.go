@@ -0,0 +1,9 @@
+// Package static holds the templates of the emails and pages, embedded in the binary
+package static
+
+import "embed"
+
+// Files are the templates. The emails are under emails, in a directory per locale
+//
+//go:embed *.html emails
+var Files embed.FS
@@ -0,0 +1,31 @@
+package blobstore
+
+import (
+	"github.com/airbenders/profile/domain"
+	"net/http"
+	"os"
+	"time"
+)
+
+// LocalPath is where the local store serves its blobs
+const LocalPath = "/blobs"
+
+// NewFromEnv returns the blob store configured by the environment. BLOB_STORE=s3 uses the S3_* variables, and any
+// other value stores the blobs in BLOB_DIR, served under LocalPath of DOMAIN
+func NewFromEnv() domain.BlobStore {
+	if os.Getenv("BLOB_STORE") == "s3" {
+		return NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		}, &http.Client{Timeout: 30 * time.Second})
+	}
+	dir := os.Getenv("BLOB_DIR")
+	if dir == "" {
+		dir = "blobs"
+	}
+	return NewLocalStore(dir, os.Getenv("DOMAIN")+LocalPath)
+}
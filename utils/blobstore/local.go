@@ -0,0 +1,98 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore keeps the blobs in a directory and serves them itself. Meant for development and single instances
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore is the constructor. Blobs are written under dir and their URLs start with baseURL, where Handler
+// must be mounted
+func NewLocalStore(dir string, baseURL string) *LocalStore {
+	return &LocalStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+var _ domain.BlobStore = (*LocalStore)(nil)
+
+// Put writes the blob to its file, creating the directories on the way
+func (s *LocalStore) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0o644)
+}
+
+// Delete removes the blob's file. Deleting a blob that doesn't exist isn't an error
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// URL is where the blob is served
+func (s *LocalStore) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Handler serves the blobs at prefix followed by their key. Directories aren't listed
+func (s *LocalStore) Handler(prefix string) http.Handler {
+	return http.StripPrefix(prefix, http.FileServer(blobsOnly{http.Dir(s.dir)}))
+}
+
+// blobsOnly is a file system where directories don't exist, so the keys can't be listed
+type blobsOnly struct {
+	http.FileSystem
+}
+
+// Open opens the file of a blob, or fails with os.ErrNotExist for directories
+func (fs blobsOnly) Open(name string) (http.File, error) {
+	file, err := fs.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, os.ErrNotExist
+	}
+	return file, nil
+}
+
+// path is the file of the blob. Keys can't escape the directory
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned == "/" || cleaned != "/"+key {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
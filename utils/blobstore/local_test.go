@@ -0,0 +1,55 @@
+package blobstore_test
+
+import (
+	"context"
+	"github.com/airbenders/profile/utils/blobstore"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	store := blobstore.NewLocalStore(t.TempDir(), "http://localhost:8080/blobs/")
+	ctx := context.Background()
+
+	t.Run("put-and-serve", func(t *testing.T) {
+		err := store.Put(ctx, "avatars/a/64.png", "image/png", []byte("png"))
+		assert.NoError(t, err)
+		assert.Equal(t, "http://localhost:8080/blobs/avatars/a/64.png", store.URL("avatars/a/64.png"))
+
+		w := httptest.NewRecorder()
+		store.Handler("/blobs").ServeHTTP(w, httptest.NewRequest("GET", "/blobs/avatars/a/64.png", nil))
+		body, _ := ioutil.ReadAll(w.Body)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "png", string(body))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, store.Put(ctx, "avatars/b/64.png", "image/png", []byte("png")))
+
+		assert.NoError(t, store.Delete(ctx, "avatars/b/64.png"))
+		assert.NoError(t, store.Delete(ctx, "avatars/b/64.png"))
+
+		w := httptest.NewRecorder()
+		store.Handler("/blobs").ServeHTTP(w, httptest.NewRequest("GET", "/blobs/avatars/b/64.png", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("directory-not-listed", func(t *testing.T) {
+		assert.NoError(t, store.Put(ctx, "avatars/c/64.png", "image/png", []byte("png")))
+
+		for _, target := range []string{"/blobs/", "/blobs/avatars/", "/blobs/avatars/c", "/blobs/avatars/c/"} {
+			w := httptest.NewRecorder()
+			store.Handler("/blobs").ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+			assert.Equal(t, http.StatusNotFound, w.Code, target)
+		}
+	})
+
+	t.Run("key-outside-dir", func(t *testing.T) {
+		assert.Error(t, store.Put(ctx, "../escape.png", "image/png", []byte("png")))
+		assert.Error(t, store.Put(ctx, "/absolute.png", "image/png", []byte("png")))
+		assert.Error(t, store.Delete(ctx, ""))
+	})
+}
@@ -1,11 +1,17 @@
 package errors
 
-import "net/http"
+import (
+	"math"
+	"net/http"
+	"time"
+)
 
 // RestError struct. Has a status code and a custom message
 type RestError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
+	// RetryAfter is the number of seconds to wait before trying again, for 429 errors
+	RetryAfter int `json:"retry_after,omitempty"`
 }
 
 func (e RestError) Error() string {
@@ -51,3 +57,24 @@ func NewUnauthorizedError(message string) *RestError {
 		Message: message,
 	}
 }
+
+// NewForbiddenError returns error with status code 403
+func NewForbiddenError(message string) *RestError {
+	return &RestError{
+		Code:    http.StatusForbidden,
+		Message: message,
+	}
+}
+
+// NewTooManyRequestsError returns error with status code 429. retryAfter is rounded up to the second
+func NewTooManyRequestsError(message string, retryAfter time.Duration) *RestError {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &RestError{
+		Code:       http.StatusTooManyRequests,
+		Message:    message,
+		RetryAfter: seconds,
+	}
+}
@@ -0,0 +1,72 @@
+package httputils
+
+import (
+	"encoding/json"
+	"github.com/airbenders/profile/domain"
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+// QueryList reads a query parameter given either as a comma separated list or many times, e.g. ?include=a,b&include=c
+func QueryList(c *gin.Context, name string) []string {
+	var list []string
+	for _, value := range c.QueryArray(name) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// ParseProfileQuery reads the fields and include query parameters. Without either of them, profiles come with their
+// reviews like they always did
+func ParseProfileQuery(c *gin.Context) domain.ProfileQuery {
+	_, hasFields := c.GetQuery("fields")
+	_, hasInclude := c.GetQuery("include")
+	if !hasFields && !hasInclude {
+		return domain.ProfileQuery{Include: []string{domain.IncludeReviews}}
+	}
+	return domain.ProfileQuery{
+		Fields:  QueryList(c, "fields"),
+		Include: QueryList(c, "include"),
+	}
+}
+
+// SelectFields returns the JSON fields wanted by the query of v, an object or a list of objects. v is returned as is
+// when all fields are wanted
+func SelectFields(v interface{}, query domain.ProfileQuery) (interface{}, error) {
+	if len(query.Fields) == 0 {
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(string(data), "[") {
+		var objects []map[string]json.RawMessage
+		if err = json.Unmarshal(data, &objects); err != nil {
+			return nil, err
+		}
+		for i := range objects {
+			objects[i] = selectFields(objects[i], query)
+		}
+		return objects, nil
+	}
+	var object map[string]json.RawMessage
+	if err = json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	return selectFields(object, query), nil
+}
+
+func selectFields(object map[string]json.RawMessage, query domain.ProfileQuery) map[string]json.RawMessage {
+	selected := make(map[string]json.RawMessage, len(query.Fields))
+	for field, value := range object {
+		if query.Wants(field) {
+			selected[field] = value
+		}
+	}
+	return selected
+}
@@ -0,0 +1,28 @@
+package httputils
+
+import (
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// ParsePage reads the page and limit query parameters. Missing parameters fall back to the first page of
+// defaultSize results. Returns an error if they aren't positive numbers or if limit is more than maxSize
+func ParsePage(c *gin.Context, defaultSize, maxSize int) (domain.Page, error) {
+	page := domain.Page{Number: 1, Size: defaultSize}
+	var err error
+	if raw := c.Query("page"); raw != "" {
+		page.Number, err = strconv.Atoi(raw)
+		if err != nil || page.Number < 1 {
+			return domain.Page{}, fmt.Errorf("page must be a positive number")
+		}
+	}
+	if raw := c.Query("limit"); raw != "" {
+		page.Size, err = strconv.Atoi(raw)
+		if err != nil || page.Size < 1 || page.Size > maxSize {
+			return domain.Page{}, fmt.Errorf("limit must be a number between 1 and %d", maxSize)
+		}
+	}
+	return page, nil
+}
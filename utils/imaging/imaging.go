@@ -0,0 +1,183 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/image/webp"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// The image types that can be uploaded
+const (
+	JPEG = "image/jpeg"
+	PNG  = "image/png"
+	WebP = "image/webp"
+)
+
+const jpegQuality = 85
+
+// ErrUnsupportedType is returned for data that isn't a JPEG, PNG or WebP image
+var ErrUnsupportedType = errors.New("unsupported image type. Expected a JPEG, PNG or WebP image")
+
+// ContentType detects the type of the image from its content, ignoring what the client claims it is.
+// Returns ErrUnsupportedType for anything other than JPEG, PNG and WebP
+func ContentType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case JPEG, PNG, WebP:
+		return contentType, nil
+	}
+	return "", ErrUnsupportedType
+}
+
+// Extension is the file extension for the content type, with the dot
+func Extension(contentType string) string {
+	switch contentType {
+	case JPEG:
+		return ".jpg"
+	case PNG:
+		return ".png"
+	case WebP:
+		return ".webp"
+	}
+	return ""
+}
+
+// Dimensions reads the width and height of the image from its header, without decoding the pixels
+func Dimensions(data []byte, contentType string) (int, int, error) {
+	if contentType == WebP {
+		return webPDimensions(data)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid image: %w", err)
+	}
+	return config.Width, config.Height, nil
+}
+
+// ThumbnailType is the type of the thumbnails of an image. There's no WebP encoder, so WebP thumbnails are PNG to
+// keep the transparency
+func ThumbnailType(contentType string) string {
+	if contentType == WebP {
+		return PNG
+	}
+	return contentType
+}
+
+// Thumbnail decodes the image, crops the largest centered square and scales it to size x size. The thumbnail is
+// encoded with ThumbnailType
+func Thumbnail(data []byte, contentType string, size int) ([]byte, error) {
+	var img image.Image
+	var err error
+	switch contentType {
+	case JPEG:
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	case PNG:
+		img, err = png.Decode(bytes.NewReader(data))
+	case WebP:
+		img, err = webp.Decode(bytes.NewReader(data))
+	default:
+		return nil, ErrUnsupportedType
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	thumbnail := resize(cropSquare(img), size)
+	var buf bytes.Buffer
+	if ThumbnailType(contentType) == PNG {
+		err = png.Encode(&buf, thumbnail)
+	} else {
+		err = jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// cropSquare copies the largest centered square of the image to an RGBA image
+func cropSquare(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), img, origin, draw.Src)
+	return square
+}
+
+// resize scales the square image to size x size. Each pixel is the average of the pixels it covers, which keeps
+// thumbnails smooth when shrinking. Growing repeats pixels
+func resize(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, side, size)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, side, size)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(row[sx*4+c])
+					}
+				}
+			}
+			count := (y1 - y0) * (x1 - x0)
+			i := y*dst.Stride + x*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[i+c] = uint8(sum[c] / count)
+			}
+		}
+	}
+	return dst
+}
+
+// span returns the source pixels covered by the destination pixel i, always at least one
+func span(i, side, size int) (int, int) {
+	start := i * side / size
+	end := (i + 1) * side / size
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
+
+// webPDimensions reads the canvas size from the header of a lossy, lossless or extended WebP image
+func webPDimensions(data []byte) (int, int, error) {
+	invalid := errors.New("invalid WebP image")
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, invalid
+	}
+	chunk := data[20:]
+	switch string(data[12:16]) {
+	case "VP8 ":
+		if chunk[3] != 0x9d || chunk[4] != 0x01 || chunk[5] != 0x2a {
+			return 0, 0, invalid
+		}
+		width := int(binary.LittleEndian.Uint16(chunk[6:8]) & 0x3fff)
+		height := int(binary.LittleEndian.Uint16(chunk[8:10]) & 0x3fff)
+		return width, height, nil
+	case "VP8L":
+		if chunk[0] != 0x2f {
+			return 0, 0, invalid
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:5])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, nil
+	case "VP8X":
+		width := int(chunk[4]) | int(chunk[5])<<8 | int(chunk[6])<<16
+		height := int(chunk[7]) | int(chunk[8])<<8 | int(chunk[9])<<16
+		return width + 1, height + 1, nil
+	}
+	return 0, 0, invalid
+}
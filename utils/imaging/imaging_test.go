@@ -0,0 +1,142 @@
+package imaging_test
+
+import (
+	"bytes"
+	"github.com/airbenders/profile/utils/imaging"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"testing"
+)
+
+func encodedPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			// left half red, right half blue
+			if x < width/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestContentType(t *testing.T) {
+	data := encodedPNG(t, 4, 4)
+	contentType, err := imaging.ContentType(data)
+	assert.NoError(t, err)
+	assert.Equal(t, imaging.PNG, contentType)
+
+	webp := append([]byte("RIFF\x00\x00\x00\x00WEBPVP8L"), make([]byte, 16)...)
+	contentType, err = imaging.ContentType(webp)
+	assert.NoError(t, err)
+	assert.Equal(t, imaging.WebP, contentType)
+
+	_, err = imaging.ContentType([]byte("<html>not an image</html>"))
+	assert.Equal(t, imaging.ErrUnsupportedType, err)
+}
+
+func TestDimensions(t *testing.T) {
+	t.Run("png", func(t *testing.T) {
+		width, height, err := imaging.Dimensions(encodedPNG(t, 30, 20), imaging.PNG)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30, width)
+		assert.Equal(t, 20, height)
+	})
+
+	t.Run("webp-lossless", func(t *testing.T) {
+		// 300 x 200: width-1 in the first 14 bits and height-1 in the next 14
+		bits := uint32(299) | uint32(199)<<14
+		data := []byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00\x2f")
+		data = append(data, byte(bits), byte(bits>>8), byte(bits>>16), byte(bits>>24))
+		data = append(data, make([]byte, 8)...)
+
+		width, height, err := imaging.Dimensions(data, imaging.WebP)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 300, width)
+		assert.Equal(t, 200, height)
+	})
+
+	t.Run("webp-lossy", func(t *testing.T) {
+		data := []byte("RIFF\x00\x00\x00\x00WEBPVP8 \x00\x00\x00\x00\x00\x00\x00\x9d\x01\x2a\x40\x01\xc8\x00")
+		data = append(data, make([]byte, 8)...)
+
+		width, height, err := imaging.Dimensions(data, imaging.WebP)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 320, width)
+		assert.Equal(t, 200, height)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := imaging.Dimensions([]byte("RIFF"), imaging.WebP)
+		assert.Error(t, err)
+
+		_, _, err = imaging.Dimensions([]byte("\x89PNG\r\n\x1a\n"), imaging.PNG)
+		assert.Error(t, err)
+	})
+}
+
+func TestThumbnail(t *testing.T) {
+	t.Run("png", func(t *testing.T) {
+		// 80 x 40: the centered square is 40 x 40, half red and half blue
+		data, err := imaging.Thumbnail(encodedPNG(t, 80, 40), imaging.PNG, 8)
+		assert.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 8, 8), img.Bounds())
+		assert.Equal(t, color.RGBA{R: 255, A: 255}, color.RGBAModel.Convert(img.At(0, 4)))
+		assert.Equal(t, color.RGBA{B: 255, A: 255}, color.RGBAModel.Convert(img.At(7, 4)))
+	})
+
+	t.Run("jpeg", func(t *testing.T) {
+		img := image.NewGray(image.Rect(0, 0, 10, 10))
+		var buf bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&buf, img, nil))
+
+		data, err := imaging.Thumbnail(buf.Bytes(), imaging.JPEG, 32)
+		assert.NoError(t, err)
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, 32, config.Width)
+		assert.Equal(t, 32, config.Height)
+	})
+
+	t.Run("webp", func(t *testing.T) {
+		webp, err := ioutil.ReadFile("testdata/blue-purple-pink.lossy.webp")
+		assert.NoError(t, err)
+
+		data, err := imaging.Thumbnail(webp, imaging.WebP, 64)
+		assert.NoError(t, err)
+
+		assert.Equal(t, imaging.PNG, imaging.ThumbnailType(imaging.WebP))
+		config, err := png.DecodeConfig(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, 64, config.Width)
+		assert.Equal(t, 64, config.Height)
+	})
+
+	t.Run("invalid-webp", func(t *testing.T) {
+		_, err := imaging.Thumbnail([]byte("RIFF"), imaging.WebP, 8)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := imaging.Thumbnail([]byte("GIF89a"), "image/gif", 8)
+
+		assert.Equal(t, imaging.ErrUnsupportedType, err)
+	})
+}
@@ -0,0 +1,103 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Email is a message with a plain text and an HTML version, which mail clients pick from
+type Email struct {
+	From    mail.Address
+	To      mail.Address
+	Subject string
+	Text    string
+	HTML    string
+	// Date defaults to now
+	Date time.Time
+	// MessageID defaults to a random one on the domain of From
+	MessageID string
+}
+
+// Bytes builds the RFC 5322 message of the email as a multipart/alternative of its text and its HTML. Names and
+// subjects that aren't ASCII are encoded as RFC 2047 words, and the parts as quoted-printable UTF-8
+func (e Email) Bytes() ([]byte, error) {
+	if e.From.Address == "" || e.To.Address == "" {
+		return nil, fmt.Errorf("an email needs a sender and a recipient")
+	}
+	date := e.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	messageID := e.MessageID
+	if messageID == "" {
+		var err error
+		if messageID, err = newMessageID(e.From.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	if err := writePart(parts, "text/plain", e.Text); err != nil {
+		return nil, err
+	}
+	if err := writePart(parts, "text/html", e.HTML); err != nil {
+		return nil, err
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	// mail.Address encodes names that aren't ASCII, and QEncoding leaves ASCII subjects as they are
+	writeHeader(&message, "From", e.From.String())
+	writeHeader(&message, "To", e.To.String())
+	writeHeader(&message, "Subject", mime.QEncoding.Encode("UTF-8", e.Subject))
+	writeHeader(&message, "Date", date.Format(time.RFC1123Z))
+	writeHeader(&message, "Message-ID", messageID)
+	writeHeader(&message, "MIME-Version", "1.0")
+	writeHeader(&message, "Content-Type", mime.FormatMediaType("multipart/alternative",
+		map[string]string{"boundary": parts.Boundary()}))
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+func writeHeader(b *bytes.Buffer, key string, value string) {
+	b.WriteString(key + ": " + value + "\r\n")
+}
+
+// writePart adds the content as a quoted-printable UTF-8 part. Its line breaks become CRLF
+func writePart(parts *multipart.Writer, contentType string, content string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=UTF-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := parts.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	w := quotedprintable.NewWriter(part)
+	if _, err = w.Write([]byte(content)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// newMessageID returns a random Message-ID on the domain of the address
+func newMessageID(address string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	host := address[strings.LastIndex(address, "@")+1:]
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), host), nil
+}
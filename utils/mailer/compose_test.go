@@ -0,0 +1,83 @@
+package mailer_test
+
+import (
+	"bytes"
+	"github.com/airbenders/profile/utils/mailer"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailBytes(t *testing.T) {
+	t.Run("multipart", func(t *testing.T) {
+		email := mailer.Email{
+			From:    mail.Address{Name: "Stud Pal", Address: "no-reply@studpal.ca"},
+			To:      mail.Address{Name: "Zoé Tremblay", Address: "zoe@concordia.ca"},
+			Subject: "Confirmez votre école",
+			Text:    "Bonjour Zoé,\nhttps://studpal.ca/school/confirmation?token=abc",
+			HTML:    "<p>Bonjour Zoé,</p>",
+			Date:    time.Date(2021, 11, 2, 10, 0, 0, 0, time.UTC),
+		}
+
+		raw, err := email.Bytes()
+
+		assert.NoError(t, err)
+		message, err := mail.ReadMessage(bytes.NewReader(raw))
+		assert.NoError(t, err)
+		decoder := new(mime.WordDecoder)
+		subject, err := decoder.DecodeHeader(message.Header.Get("Subject"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Confirmez votre école", subject)
+		to, err := message.Header.AddressList("To")
+		assert.NoError(t, err)
+		assert.Equal(t, []*mail.Address{{Name: "Zoé Tremblay", Address: "zoe@concordia.ca"}}, to)
+		assert.Equal(t, "Tue, 02 Nov 2021 10:00:00 +0000", message.Header.Get("Date"))
+		assert.Regexp(t, "^<[0-9a-f]{32}@studpal.ca>$", message.Header.Get("Message-ID"))
+		assert.Equal(t, "1.0", message.Header.Get("MIME-Version"))
+
+		mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+		assert.NoError(t, err)
+		assert.Equal(t, "multipart/alternative", mediaType)
+		parts := multipart.NewReader(message.Body, params["boundary"])
+		for _, expected := range []struct{ contentType, content string }{
+			{"text/plain; charset=UTF-8", email.Text},
+			{"text/html; charset=UTF-8", email.HTML},
+		} {
+			part, err := parts.NextPart()
+			assert.NoError(t, err)
+			assert.Equal(t, expected.contentType, part.Header.Get("Content-Type"))
+			// the reader decodes quoted-printable parts, whose line breaks are CRLF
+			content, err := ioutil.ReadAll(part)
+			assert.NoError(t, err)
+			assert.Equal(t, strings.ReplaceAll(expected.content, "\n", "\r\n"), string(content))
+		}
+		_, err = parts.NextPart()
+		assert.Error(t, err)
+	})
+
+	t.Run("message-id", func(t *testing.T) {
+		email := mailer.Email{
+			From:      mail.Address{Address: "no-reply@studpal.ca"},
+			To:        mail.Address{Address: "zoe@concordia.ca"},
+			MessageID: "<1@studpal.ca>",
+		}
+
+		raw, err := email.Bytes()
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(raw), "\r\nMessage-ID: <1@studpal.ca>\r\n")
+	})
+
+	t.Run("error-no-recipient", func(t *testing.T) {
+		email := mailer.Email{From: mail.Address{Address: "no-reply@studpal.ca"}}
+
+		_, err := email.Bytes()
+
+		assert.Error(t, err)
+	})
+}
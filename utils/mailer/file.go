@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileTransport writes every email to its own .eml file instead of sending it. Meant for development, the files
+// open in any mail client
+type FileTransport struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFileTransport is the constructor. The files are written in dir, which is created if needed
+func NewFileTransport(dir string) *FileTransport {
+	return &FileTransport{
+		dir: dir,
+		now: time.Now,
+	}
+}
+
+var _ domain.Mailer = (*FileTransport)(nil)
+
+// SendSimpleMail writes the message to a new file named after when it was sent
+func (t *FileTransport) SendSimpleMail(ctx context.Context, to string, body []byte) error {
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", t.now().UTC().Format("20060102T150405.000000000Z"), hex.EncodeToString(suffix))
+	return ioutil.WriteFile(filepath.Join(t.dir, name), body, 0o644)
+}
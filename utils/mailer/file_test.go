@@ -0,0 +1,25 @@
+package mailer_test
+
+import (
+	"context"
+	"github.com/airbenders/profile/utils/mailer"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileTransport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	transport := mailer.NewFileTransport(dir)
+
+	assert.NoError(t, transport.SendSimpleMail(context.Background(), "ada@concordia.ca", []byte("Subject: a\r\n\r\nfirst")))
+	assert.NoError(t, transport.SendSimpleMail(context.Background(), "ada@concordia.ca", []byte("Subject: b\r\n\r\nsecond")))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	content, err := ioutil.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "Subject: a\r\n\r\nfirst", string(content))
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"github.com/airbenders/profile/domain"
+	"log"
+	"os"
+	"time"
+)
+
+// defaultTimeout bounds a whole SMTP exchange when SMTP_TIMEOUT isn't set
+const defaultTimeout = 10 * time.Second
+
+// NewFromEnv returns the mailer configured by the environment. MAIL_TRANSPORT=file writes the emails to MAIL_DIR,
+// MAIL_TRANSPORT=memory keeps them in memory, and any other value sends them with the SMTP_* variables
+func NewFromEnv() domain.Mailer {
+	switch os.Getenv("MAIL_TRANSPORT") {
+	case "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return NewFileTransport(dir)
+	case "memory":
+		return NewMemoryTransport()
+	}
+
+	timeout := defaultTimeout
+	if raw := os.Getenv("SMTP_TIMEOUT"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			log.Printf("invalid SMTP_TIMEOUT %q, using %s", raw, defaultTimeout)
+		} else {
+			timeout = parsed
+		}
+	}
+	return NewSMTPTransport(SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("USER"),
+		Password: os.Getenv("PASSWORD"),
+		From:     os.Getenv("EMAIL_FROM"),
+		Security: Security(os.Getenv("SMTP_SECURITY")),
+		Timeout:  timeout,
+	})
+}
@@ -0,0 +1,78 @@
+package mailer
+
+import (
+	"context"
+	"github.com/airbenders/profile/domain"
+	"sync"
+	"time"
+)
+
+// Message is an email kept by the memory transport
+type Message struct {
+	To     string
+	Body   []byte
+	SentAt time.Time
+}
+
+// MemoryTransport keeps the emails instead of sending them, so tests can look at them
+type MemoryTransport struct {
+	mu       sync.Mutex
+	messages []Message
+	err      error
+}
+
+// NewMemoryTransport is the constructor
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+var _ domain.Mailer = (*MemoryTransport)(nil)
+
+// SendSimpleMail keeps the email, or returns the error set with FailWith
+func (t *MemoryTransport) SendSimpleMail(ctx context.Context, to string, body []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.err != nil {
+		return t.err
+	}
+	t.messages = append(t.messages, Message{To: to, Body: append([]byte(nil), body...), SentAt: time.Now()})
+	return nil
+}
+
+// FailWith makes the next emails fail with err. A nil err sends them again
+func (t *MemoryTransport) FailWith(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.err = err
+}
+
+// Messages returns the emails sent so far, oldest first
+func (t *MemoryTransport) Messages() []Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Message(nil), t.messages...)
+}
+
+// Last returns the last email sent to the address, and false if there is none
+func (t *MemoryTransport) Last(to string) (Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := len(t.messages) - 1; i >= 0; i-- {
+		if t.messages[i].To == to {
+			return t.messages[i], true
+		}
+	}
+	return Message{}, false
+}
+
+// Reset forgets the emails sent so far
+func (t *MemoryTransport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.messages = nil
+}
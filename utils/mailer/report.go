@@ -0,0 +1,140 @@
+package mailer
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// ParseReport reads the bounces and complaints of a report sent back by a mail server: a delivery status
+// notification (RFC 3464) or an abuse report (RFC 5965). Other messages are an error
+func ParseReport(r io.Reader) ([]domain.MailEvent, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	if mediaType != "multipart/report" {
+		return nil, fmt.Errorf("expected a multipart/report, got %s", mediaType)
+	}
+
+	var events []domain.MailEvent
+	var feedback textproto.MIMEHeader
+	var originalTo string
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch partType {
+		case "message/delivery-status":
+			fields, err := readFieldGroups(part)
+			if err != nil {
+				return nil, err
+			}
+			// the first group is about the message, the others about each recipient
+			for _, recipient := range fields[1:] {
+				if event, ok := bounceEvent(recipient); ok {
+					events = append(events, event)
+				}
+			}
+		case "message/feedback-report":
+			fields, err := readFieldGroups(part)
+			if err != nil {
+				return nil, err
+			}
+			feedback = fields[0]
+		case "message/rfc822", "text/rfc822-headers":
+			original, err := textproto.NewReader(bufio.NewReader(part)).ReadMIMEHeader()
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			originalTo = original.Get("To")
+		}
+	}
+
+	if feedback != nil {
+		// the recipient is optional in abuse reports, the original message says it too
+		to := feedback.Get("Original-Rcpt-To")
+		if to == "" {
+			to = originalTo
+		}
+		events = append(events, domain.MailEvent{
+			Type:       domain.MailComplaint,
+			Email:      to,
+			Diagnostic: feedback.Get("Feedback-Type"),
+		})
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("the report has no bounce or complaint")
+	}
+	return events, nil
+}
+
+// bounceEvent is the bounce of the recipient fields of a delivery status notification. Only failed and delayed
+// deliveries are bounces
+func bounceEvent(recipient textproto.MIMEHeader) (domain.MailEvent, bool) {
+	action := strings.ToLower(strings.TrimSpace(recipient.Get("Action")))
+	if action != "failed" && action != "delayed" {
+		return domain.MailEvent{}, false
+	}
+	address := recipient.Get("Final-Recipient")
+	if address == "" {
+		address = recipient.Get("Original-Recipient")
+	}
+	diagnostic := recipient.Get("Diagnostic-Code")
+	if diagnostic == "" {
+		diagnostic = recipient.Get("Status")
+	}
+	return domain.MailEvent{
+		Type:  domain.MailBounce,
+		Email: typedValue(address),
+		// 5.x.x statuses are permanent failures, 4.x.x ones may work later
+		Permanent:  action == "failed" && strings.HasPrefix(strings.TrimSpace(recipient.Get("Status")), "5"),
+		Diagnostic: typedValue(diagnostic),
+	}, true
+}
+
+// typedValue drops the type of a field like "rfc822; ada@concordia.ca"
+func typedValue(field string) string {
+	if i := strings.Index(field, ";"); i >= 0 {
+		field = field[i+1:]
+	}
+	return strings.TrimSpace(field)
+}
+
+// readFieldGroups reads the groups of header fields separated by blank lines of a report part
+func readFieldGroups(r io.Reader) ([]textproto.MIMEHeader, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+	var groups []textproto.MIMEHeader
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			groups = append(groups, fields)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("empty report")
+	}
+	return groups, nil
+}
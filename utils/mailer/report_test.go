@@ -0,0 +1,116 @@
+package mailer_test
+
+import (
+	"github.com/airbenders/profile/domain"
+	"github.com/airbenders/profile/utils/mailer"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const deliveryStatusReport = `From: Mail Delivery System <MAILER-DAEMON@mx.studpal.ca>
+To: no-reply@studpal.ca
+Subject: Undelivered Mail Returned to Sender
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary="b"
+
+--b
+Content-Type: text/plain
+
+The mail system could not deliver your message.
+
+--b
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mx.studpal.ca
+Arrival-Date: Tue, 2 Nov 2021 10:00:00 +0000
+
+Final-Recipient: rfc822; ada@concordia.ca
+Original-Recipient: rfc822; ada@concordia.ca
+Action: failed
+Status: 5.1.1
+Diagnostic-Code: smtp; 550 5.1.1 <ada@concordia.ca>: Recipient address rejected:
+ User unknown
+
+Final-Recipient: rfc822; bob@mcgill.ca
+Action: delayed
+Status: 4.2.2
+Diagnostic-Code: smtp; 452 4.2.2 Mailbox full
+
+Final-Recipient: rfc822; carl@mcgill.ca
+Action: delivered
+Status: 2.0.0
+
+--b
+Content-Type: text/rfc822-headers
+
+From: no-reply@studpal.ca
+Subject: Smarties app confirmation
+
+--b--
+`
+
+const abuseReport = `From: abuse@mail.concordia.ca
+To: no-reply@studpal.ca
+Subject: FW: Smarties app confirmation
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=feedback-report; boundary="b"
+
+--b
+Content-Type: text/plain
+
+This is an email abuse report.
+
+--b
+Content-Type: message/feedback-report
+
+Feedback-Type: abuse
+User-Agent: SomeGenerator/1.0
+Version: 1
+
+--b
+Content-Type: message/rfc822
+
+From: no-reply@studpal.ca
+To: Ada <ada@concordia.ca>
+Subject: Smarties app confirmation
+
+Hello Ada!
+--b--
+`
+
+func TestParseReport(t *testing.T) {
+	t.Run("delivery-status", func(t *testing.T) {
+		events, err := mailer.ParseReport(strings.NewReader(deliveryStatusReport))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.MailEvent{
+			{Type: domain.MailBounce, Email: "ada@concordia.ca", Permanent: true,
+				Diagnostic: "550 5.1.1 <ada@concordia.ca>: Recipient address rejected: User unknown"},
+			{Type: domain.MailBounce, Email: "bob@mcgill.ca", Diagnostic: "452 4.2.2 Mailbox full"},
+		}, events)
+	})
+
+	t.Run("abuse", func(t *testing.T) {
+		events, err := mailer.ParseReport(strings.NewReader(abuseReport))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.MailEvent{{Type: domain.MailComplaint, Email: "Ada <ada@concordia.ca>",
+			Diagnostic: "abuse"}}, events)
+	})
+
+	t.Run("error-not-a-report", func(t *testing.T) {
+		_, err := mailer.ParseReport(strings.NewReader("Subject: hi\r\nContent-Type: text/plain\r\n\r\nhello"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error-nothing-failed", func(t *testing.T) {
+		report := strings.Replace(strings.Replace(deliveryStatusReport, "Action: failed", "Action: delivered", 1),
+			"Action: delayed", "Action: relayed", 1)
+
+		_, err := mailer.ParseReport(strings.NewReader(report))
+
+		assert.Error(t, err)
+	})
+}
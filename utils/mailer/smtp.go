@@ -0,0 +1,158 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/airbenders/profile/domain"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// Security is how the connection to the SMTP server is encrypted
+type Security string
+
+// The securities of SMTPConfig
+const (
+	// SecurityStartTLS upgrades the connection with STARTTLS, and fails if the server doesn't offer it
+	SecurityStartTLS Security = "starttls"
+	// SecurityTLS connects with TLS from the start, usually on port 465
+	SecurityTLS Security = "tls"
+	// SecurityNone sends in clear text. Only for servers on the same host or in tests
+	SecurityNone Security = "none"
+)
+
+// SMTPConfig is where the SMTP server is and how to sign in to it
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	// From is the envelope sender, either an address or a name and an address
+	From string
+	// Security defaults to SecurityTLS on port 465 and to SecurityStartTLS otherwise
+	Security Security
+	// Timeout bounds the whole exchange with the server, from connecting to the end of the email
+	Timeout time.Duration
+	// TLSConfig overrides the TLS settings, e.g. to trust another certificate authority
+	TLSConfig *tls.Config
+}
+
+// SMTPTransport sends the emails with an SMTP server, one connection per email
+type SMTPTransport struct {
+	config SMTPConfig
+}
+
+// NewSMTPTransport is the constructor
+func NewSMTPTransport(config SMTPConfig) *SMTPTransport {
+	if config.Security == "" {
+		config.Security = SecurityStartTLS
+		if config.Port == "465" {
+			config.Security = SecurityTLS
+		}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	return &SMTPTransport{config: config}
+}
+
+var _ domain.Mailer = (*SMTPTransport)(nil)
+
+// SendSimpleMail sends the email, giving up after the timeout or when ctx is done
+func (t *SMTPTransport) SendSimpleMail(ctx context.Context, to string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, t.config.Timeout)
+	defer cancel()
+
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("smtp: can't connect to %s: %w", t.config.Host, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	// closing the connection unblocks the exchange if ctx is cancelled before the deadline
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	if err = t.send(conn, to, body); err != nil {
+		return fmt.Errorf("smtp: can't send to %s: %w", to, err)
+	}
+	return nil
+}
+
+// dial opens the connection to the server, with TLS for SecurityTLS
+func (t *SMTPTransport) dial(ctx context.Context) (net.Conn, error) {
+	address := net.JoinHostPort(t.config.Host, t.config.Port)
+	if t.config.Security == SecurityTLS {
+		dialer := &tls.Dialer{Config: t.tlsConfig()}
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// send has the SMTP exchange for one email on the connection
+func (t *SMTPTransport) send(conn net.Conn, to string, body []byte) error {
+	client, err := smtp.NewClient(conn, t.config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if t.config.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("the server doesn't support STARTTLS")
+		}
+		if err = client.StartTLS(t.tlsConfig()); err != nil {
+			return err
+		}
+	}
+	if t.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			auth := smtp.PlainAuth("", t.config.Username, t.config.Password, t.config.Host)
+			if err = client.Auth(auth); err != nil {
+				return err
+			}
+		}
+	}
+
+	from, err := mail.ParseAddress(t.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", t.config.From, err)
+	}
+	if err = client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err = client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(body); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (t *SMTPTransport) tlsConfig() *tls.Config {
+	if t.config.TLSConfig != nil {
+		return t.config.TLSConfig.Clone()
+	}
+	return &tls.Config{ServerName: t.config.Host}
+}
@@ -0,0 +1,106 @@
+package mailer_test
+
+import (
+	"context"
+	"github.com/airbenders/profile/utils/mailer"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer answers one SMTP exchange per connection and sends the MAIL command and the data of the emails
+// it gets to received. It only offers the given extensions
+func fakeSMTPServer(t *testing.T, extensions ...string) (host string, port string, received chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	received = make(chan string, 1)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				text := textproto.NewConn(conn)
+				text.PrintfLine("220 localhost ready")
+				var mailCommand string
+				for {
+					line, err := text.ReadLine()
+					if err != nil {
+						return
+					}
+					switch command := strings.ToUpper(strings.Fields(line + " ")[0]); command {
+					case "EHLO":
+						for _, extension := range extensions {
+							text.PrintfLine("250-%s", extension)
+						}
+						text.PrintfLine("250 localhost")
+					case "MAIL":
+						mailCommand = line
+						text.PrintfLine("250 ok")
+					case "DATA":
+						text.PrintfLine("354 go ahead")
+						data, _ := text.ReadDotBytes()
+						received <- mailCommand + "\n" + string(data)
+						text.PrintfLine("250 queued")
+					case "QUIT":
+						text.PrintfLine("221 bye")
+						return
+					default:
+						text.PrintfLine("250 ok")
+					}
+				}
+			}()
+		}
+	}()
+
+	host, port, err = net.SplitHostPort(listener.Addr().String())
+	assert.NoError(t, err)
+	return host, port, received
+}
+
+func TestSMTPTransport(t *testing.T) {
+	t.Run("send", func(t *testing.T) {
+		host, port, received := fakeSMTPServer(t)
+		transport := mailer.NewSMTPTransport(mailer.SMTPConfig{Host: host, Port: port, From: "Stud Pal <no-reply@studpal.ca>",
+			Security: mailer.SecurityNone, Timeout: time.Second})
+
+		err := transport.SendSimpleMail(context.Background(), "ada@concordia.ca", []byte("Subject: hi\r\n\r\nhello"))
+
+		assert.NoError(t, err)
+		data := <-received
+		assert.Equal(t, "MAIL FROM:<no-reply@studpal.ca>\nSubject: hi\n\nhello\n", data)
+	})
+
+	t.Run("starttls-not-offered", func(t *testing.T) {
+		host, port, _ := fakeSMTPServer(t)
+		transport := mailer.NewSMTPTransport(mailer.SMTPConfig{Host: host, Port: port, Timeout: time.Second})
+
+		err := transport.SendSimpleMail(context.Background(), "ada@concordia.ca", []byte("hello"))
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "STARTTLS")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		// the server accepts the connection but never greets
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer listener.Close()
+		host, port, _ := net.SplitHostPort(listener.Addr().String())
+		transport := mailer.NewSMTPTransport(mailer.SMTPConfig{Host: host, Port: port, Security: mailer.SecurityNone,
+			Timeout: 50 * time.Millisecond})
+
+		start := time.Now()
+		err = transport.SendSimpleMail(context.Background(), "ada@concordia.ca", []byte("hello"))
+
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
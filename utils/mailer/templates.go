@@ -0,0 +1,90 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Templates are the emails in every locale. Each email is a pair of files in the directory of its locale:
+// name.txt with the plain text and a "subject" template, and name.html with the HTML
+type Templates struct {
+	defaultLocale string
+	text          map[string]*texttemplate.Template
+	html          map[string]*htmltemplate.Template
+}
+
+// ParseTemplates reads the templates under dir of fsys, where every directory is a locale like dir/en. Emails
+// missing in a locale fall back to defaultLocale
+func ParseTemplates(fsys fs.FS, dir string, defaultLocale string) (*Templates, error) {
+	t := &Templates{
+		defaultLocale: defaultLocale,
+		text:          map[string]*texttemplate.Template{},
+		html:          map[string]*htmltemplate.Template{},
+	}
+	files, err := fs.Glob(fsys, path.Join(dir, "*", "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		key := templateKey(path.Base(path.Dir(file)), strings.TrimSuffix(path.Base(file), ".txt"))
+		text, err := texttemplate.ParseFS(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("%s has no subject template", file)
+		}
+		html, err := htmltemplate.ParseFS(fsys, strings.TrimSuffix(file, ".txt")+".html")
+		if err != nil {
+			return nil, err
+		}
+		t.text[key], t.html[key] = text, html
+	}
+	return t, nil
+}
+
+// MustParseTemplates is ParseTemplates for templates embedded in the binary, which can't be missing
+func MustParseTemplates(fsys fs.FS, dir string, defaultLocale string) *Templates {
+	t, err := ParseTemplates(fsys, dir, defaultLocale)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Render fills the subject, the text and the HTML of the email with data, in the locale if there is one
+func (t *Templates) Render(name string, locale string, data interface{}) (subject string, text string, html string, err error) {
+	key := templateKey(locale, name)
+	if _, ok := t.text[key]; !ok {
+		key = templateKey(t.defaultLocale, name)
+	}
+	textTemplate, ok := t.text[key]
+	if !ok {
+		return "", "", "", fmt.Errorf("no %s email template", name)
+	}
+
+	var b bytes.Buffer
+	if err = textTemplate.ExecuteTemplate(&b, "subject", data); err != nil {
+		return "", "", "", err
+	}
+	subject = strings.TrimSpace(b.String())
+	b.Reset()
+	if err = textTemplate.Execute(&b, data); err != nil {
+		return "", "", "", err
+	}
+	text = strings.TrimSpace(b.String()) + "\n"
+	b.Reset()
+	if err = t.html[key].Execute(&b, data); err != nil {
+		return "", "", "", err
+	}
+	return subject, text, b.String(), nil
+}
+
+func templateKey(locale string, name string) string {
+	return locale + "/" + name
+}
@@ -0,0 +1,59 @@
+package mailer_test
+
+import (
+	"github.com/airbenders/profile/utils/mailer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTemplates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"emails/en/welcome.txt":  {Data: []byte("{{define \"subject\"}}Welcome {{.Name}}{{end}}\nHi {{.Name}},\n")},
+		"emails/en/welcome.html": {Data: []byte("<p>Hi {{.Name}}</p>")},
+		"emails/fr/welcome.txt":  {Data: []byte("{{define \"subject\"}}Bienvenue {{.Name}}{{end}}\nSalut {{.Name}},\n")},
+		"emails/fr/welcome.html": {Data: []byte("<p>Salut {{.Name}}</p>")},
+	}
+	templates, err := mailer.ParseTemplates(fsys, "emails", "en")
+	assert.NoError(t, err)
+	data := struct{ Name string }{"<Zoé>"}
+
+	t.Run("locale", func(t *testing.T) {
+		subject, text, html, err := templates.Render("welcome", "fr", data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bienvenue <Zoé>", subject)
+		assert.Equal(t, "Salut <Zoé>,\n", text)
+		assert.Equal(t, "<p>Salut &lt;Zoé&gt;</p>", html)
+	})
+
+	t.Run("default-locale", func(t *testing.T) {
+		subject, _, _, err := templates.Render("welcome", "de", data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Welcome <Zoé>", subject)
+	})
+
+	t.Run("error-unknown", func(t *testing.T) {
+		_, _, _, err := templates.Render("goodbye", "en", data)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error-no-html", func(t *testing.T) {
+		_, err := mailer.ParseTemplates(fstest.MapFS{
+			"emails/en/welcome.txt": {Data: []byte("{{define \"subject\"}}Welcome{{end}}")},
+		}, "emails", "en")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error-no-subject", func(t *testing.T) {
+		_, err := mailer.ParseTemplates(fstest.MapFS{
+			"emails/en/welcome.txt":  {Data: []byte("Hi")},
+			"emails/en/welcome.html": {Data: []byte("<p>Hi</p>")},
+		}, "emails", "en")
+
+		assert.Error(t, err)
+	})
+}
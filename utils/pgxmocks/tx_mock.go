@@ -93,10 +93,19 @@ func (tx *TxMock) Exec(ctx context.Context, sql string, arguments ...interface{}
 	return r0, r1
 }
 
-// Query mock function. We don't have to impl this since we aren't using them but still needs to be impl for
-// the interface
-func (tx *TxMock) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
-	panic("not impl")
+// Query mock function
+func (tx *TxMock) Query(ctx context.Context, sql string, arguments ...interface{}) (pgx.Rows, error) {
+	args := tx.Called(ctx, sql, arguments)
+
+	var r0 pgx.Rows
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(pgx.Rows)
+	}
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
 }
 
 // QueryRow mock function. We don't have to impl this since we aren't using them but still needs to be impl for